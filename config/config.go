@@ -5,12 +5,15 @@ import (
 	"os"
 
 	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/batch"
 	"github.com/adrianliechti/llama/pkg/chain"
 	"github.com/adrianliechti/llama/pkg/extractor"
 	"github.com/adrianliechti/llama/pkg/index"
 	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
 	"github.com/adrianliechti/llama/pkg/segmenter"
 	"github.com/adrianliechti/llama/pkg/summarizer"
+	"github.com/adrianliechti/llama/pkg/thread"
 	"github.com/adrianliechti/llama/pkg/tool"
 	"github.com/adrianliechti/llama/pkg/translator"
 
@@ -22,6 +25,8 @@ type Config struct {
 
 	Authorizers []authorizer.Provider
 
+	quota *quota.Manager
+
 	models map[string]provider.Model
 
 	completer   map[string]provider.Completer
@@ -40,6 +45,9 @@ type Config struct {
 
 	tools  map[string]tool.Tool
 	chains map[string]chain.Provider
+
+	threads thread.Store
+	batches batch.Store
 }
 
 func Parse(path string) (*Config, error) {
@@ -57,6 +65,10 @@ func Parse(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := c.registerQuotas(file); err != nil {
+		return nil, err
+	}
+
 	if err := c.registerProviders(file); err != nil {
 		return nil, err
 	}
@@ -93,12 +105,22 @@ func Parse(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := c.registerThreads(file); err != nil {
+		return nil, err
+	}
+
+	if err := c.registerBatches(file); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
 type configFile struct {
 	Authorizers []authorizerConfig `yaml:"authorizers"`
 
+	Quotas map[string]quotaConfig `yaml:"quotas"`
+
 	Providers []providerConfig `yaml:"providers"`
 
 	Indexes map[string]indexConfig `yaml:"indexes"`
@@ -110,6 +132,9 @@ type configFile struct {
 	Chains map[string]chainConfig `yaml:"chains"`
 
 	Routers map[string]routerConfig `yaml:"routers"`
+
+	Threads *threadConfig `yaml:"threads"`
+	Batches *batchConfig  `yaml:"batches"`
 }
 
 func parseFile(path string) (*configFile, error) {
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/authorizer/oidc"
@@ -16,6 +17,33 @@ type authorizerConfig struct {
 
 	Issuer   string `yaml:"issuer"`
 	Audience string `yaml:"audience"`
+
+	Issuers []authorizerIssuerConfig `yaml:"issuers"`
+	Rules   []authorizerRuleConfig   `yaml:"rules"`
+
+	Keys map[string]authorizerKeyConfig `yaml:"keys"`
+}
+
+type authorizerIssuerConfig struct {
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+}
+
+type authorizerRuleConfig struct {
+	Claim  string   `yaml:"claim"`
+	Values []string `yaml:"values"`
+
+	Models  []string `yaml:"models"`
+	Indexes []string `yaml:"indexes"`
+}
+
+type authorizerKeyConfig struct {
+	Token string `yaml:"token"`
+
+	Models  []string `yaml:"models"`
+	Indexes []string `yaml:"indexes"`
+
+	Expires *time.Time `yaml:"expires"`
 }
 
 func (c *Config) registerAuthorizer(f *configFile) error {
@@ -46,9 +74,39 @@ func createAuthorizer(cfg authorizerConfig) (authorizer.Provider, error) {
 }
 
 func staticAuthorizer(cfg authorizerConfig) (authorizer.Provider, error) {
-	return static.New(cfg.Token)
+	var options []static.Option
+
+	for name, k := range cfg.Keys {
+		options = append(options, static.WithKey(static.Key{
+			Name:  name,
+			Token: k.Token,
+
+			Models:  k.Models,
+			Indexes: k.Indexes,
+
+			Expires: k.Expires,
+		}))
+	}
+
+	return static.New(cfg.Token, options...)
 }
 
 func oidcAuthorizer(cfg authorizerConfig) (authorizer.Provider, error) {
-	return oidc.New(cfg.Issuer, cfg.Audience)
+	var options []oidc.Option
+
+	for _, i := range cfg.Issuers {
+		options = append(options, oidc.WithIssuer(i.Issuer, i.Audience))
+	}
+
+	for _, r := range cfg.Rules {
+		options = append(options, oidc.WithRule(oidc.Rule{
+			Claim:  r.Claim,
+			Values: r.Values,
+
+			Models:  r.Models,
+			Indexes: r.Indexes,
+		}))
+	}
+
+	return oidc.New(cfg.Issuer, cfg.Audience, options...)
 }
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adrianliechti/llama/pkg/batch"
+	"github.com/adrianliechti/llama/pkg/batch/file"
+	"github.com/adrianliechti/llama/pkg/batch/memory"
+)
+
+type batchConfig struct {
+	Type string `yaml:"type"`
+
+	Path string `yaml:"path"`
+}
+
+func (cfg *Config) registerBatches(f *configFile) error {
+	var c batchConfig
+
+	if f.Batches != nil {
+		c = *f.Batches
+	}
+
+	store, err := createBatchStore(c)
+
+	if err != nil {
+		return err
+	}
+
+	cfg.batches = store
+
+	return nil
+}
+
+func (cfg *Config) Batches() batch.Store {
+	return cfg.batches
+}
+
+func createBatchStore(cfg batchConfig) (batch.Store, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "memory":
+		return memory.New()
+
+	case "file":
+		return file.New(cfg.Path)
+
+	default:
+		return nil, errors.New("invalid batch store type: " + cfg.Type)
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/cache"
+	"github.com/adrianliechti/llama/pkg/cache/disk"
+	"github.com/adrianliechti/llama/pkg/cache/memory"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type cacheConfig struct {
+	Type string `yaml:"type"`
+
+	Path string `yaml:"path"`
+	Size int    `yaml:"size"`
+
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// The cache is the outermost layer of a model. Cache hits cost no provider tokens, so they are served
+// without checking or recording the quota of the caller (and bypass rate limits and metrics as well),
+// even if the caller is over quota. Only cache misses reach the provider and count against the quota.
+func cacheCompleter(id string, context modelContext, completer provider.Completer) provider.Completer {
+	if _, ok := completer.(cache.Completer); ok || context.Cache == nil {
+		return completer
+	}
+
+	return cache.NewCompleter(id, context.Cache, context.CacheTTL, completer)
+}
+
+func cacheEmbedder(id string, context modelContext, embedder provider.Embedder) provider.Embedder {
+	if _, ok := embedder.(cache.Embedder); ok || context.Cache == nil {
+		return embedder
+	}
+
+	return cache.NewEmbedder(id, context.Cache, context.CacheTTL, embedder)
+}
+
+func createCache(cfg cacheConfig) (cache.Store, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "memory":
+		return memoryCache(cfg)
+
+	case "disk":
+		return diskCache(cfg)
+
+	default:
+		return nil, errors.New("invalid cache type: " + cfg.Type)
+	}
+}
+
+func memoryCache(cfg cacheConfig) (cache.Store, error) {
+	var options []memory.Option
+
+	if cfg.Size > 0 {
+		options = append(options, memory.WithSize(cfg.Size))
+	}
+
+	return memory.New(options...)
+}
+
+func diskCache(cfg cacheConfig) (cache.Store, error) {
+	return disk.New(cfg.Path)
+}
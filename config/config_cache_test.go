@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/cache/memory"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+
+	"github.com/stretchr/testify/require"
+)
+
+type usageCompleter struct {
+	calls int
+}
+
+func (c *usageCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.calls++
+
+	return &provider.Completion{
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "answer",
+		},
+
+		Usage: &provider.Usage{
+			InputTokens:  80,
+			OutputTokens: 20,
+		},
+	}, nil
+}
+
+func TestCacheHitsBypassQuota(t *testing.T) {
+	ctx := authorizer.WithPrincipal(context.Background(), &authorizer.Principal{ID: "alice"})
+
+	store, err := memory.New()
+	require.NoError(t, err)
+
+	cfg := &Config{
+		quota: quota.New(map[string]quota.Limits{
+			"*": {TokensPerMinute: 100},
+		}),
+	}
+
+	p := &usageCompleter{}
+
+	completer := cfg.decorateCompleter("test", "model", modelContext{Cache: store}, p)
+
+	temperature := float32(0)
+	options := &provider.CompleteOptions{Temperature: &temperature}
+
+	messages := func(content string) []provider.Message {
+		return []provider.Message{
+			{
+				Role:    provider.MessageRoleUser,
+				Content: content,
+			},
+		}
+	}
+
+	// the miss reaches the provider and exhausts the quota
+	_, err = completer.Complete(ctx, messages("hello"), options)
+	require.NoError(t, err)
+
+	// the hit is served although the caller is over quota
+	result, err := completer.Complete(ctx, messages("hello"), options)
+	require.NoError(t, err)
+	require.Equal(t, "answer", result.Message.Content)
+
+	// a miss is rejected by the quota before it reaches the provider
+	_, err = completer.Complete(ctx, messages("goodbye"), options)
+
+	var quotaErr *quota.Error
+	require.ErrorAs(t, err, &quotaErr)
+
+	require.Equal(t, 1, p.calls)
+}
@@ -91,6 +91,26 @@ func createCompleter(cfg providerConfig, model modelContext) (provider.Completer
 	}
 }
 
+// providers enforcing json schema response formats natively; others are validated by the gateway
+func nativeSchemaSupport(provider string) bool {
+	switch strings.ToLower(provider) {
+	case "openai", "azure", "github", "mistral", "google":
+		return true
+	}
+
+	return false
+}
+
+// providers completing prompts with a suffix (fill-in-the-middle) natively; others use a prompt template
+func nativeFIMSupport(provider string) bool {
+	switch strings.ToLower(provider) {
+	case "mistral", "llama", "ollama":
+		return true
+	}
+
+	return false
+}
+
 func anthropicCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
 	var options []anthropic.Option
 
@@ -154,7 +174,7 @@ func huggingfaceCompleter(cfg providerConfig, model modelContext) (provider.Comp
 func llamaCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
 	var options []llama.Option
 
-	return llama.NewCompleter(model.ID, cfg.URL, options...)
+	return llama.NewCompleter(cfg.URL, model.ID, options...)
 }
 
 func mistralCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
@@ -180,7 +200,9 @@ func ollamaCompleter(cfg providerConfig, model modelContext) (provider.Completer
 }
 
 func openaiCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
-	var options []openai.Option
+	options := []openai.Option{
+		openai.WithStreamUsage(),
+	}
 
 	if cfg.Token != "" {
 		options = append(options, openai.WithToken(cfg.Token))
@@ -69,6 +69,16 @@ func createEmbedder(cfg providerConfig, model modelContext) (provider.Embedder,
 	}
 }
 
+// providers returning embeddings of requested dimensions natively; others are truncated by the gateway
+func nativeDimensionsSupport(provider string) bool {
+	switch strings.ToLower(provider) {
+	case "openai", "azure", "github", "cohere", "jina":
+		return true
+	}
+
+	return false
+}
+
 func azureEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
 	var options []azure.Option
 
@@ -122,7 +132,7 @@ func jinaEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, er
 func llamaEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
 	var options []llama.Option
 
-	return llama.NewEmbedder(model.ID, cfg.URL, options...)
+	return llama.NewEmbedder(cfg.URL, model.ID, options...)
 }
 
 func ollamaEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
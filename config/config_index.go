@@ -3,14 +3,17 @@ package config
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/adrianliechti/llama/pkg/index"
 	"github.com/adrianliechti/llama/pkg/index/azure"
 	"github.com/adrianliechti/llama/pkg/index/chroma"
 	"github.com/adrianliechti/llama/pkg/index/custom"
 	"github.com/adrianliechti/llama/pkg/index/elasticsearch"
+	"github.com/adrianliechti/llama/pkg/index/hnsw"
 	"github.com/adrianliechti/llama/pkg/index/memory"
 	"github.com/adrianliechti/llama/pkg/index/qdrant"
+	"github.com/adrianliechti/llama/pkg/index/reranker"
 	"github.com/adrianliechti/llama/pkg/index/weaviate"
 	"github.com/adrianliechti/llama/pkg/otel"
 )
@@ -41,6 +44,15 @@ type indexConfig struct {
 
 	Namespace string `yaml:"namespace"`
 
+	Path             string        `yaml:"path"`
+	SnapshotInterval time.Duration `yaml:"snapshot_interval"`
+
+	M              int `yaml:"m"`
+	EfConstruction int `yaml:"ef_construction"`
+	EfSearch       int `yaml:"ef_search"`
+
+	Hybrid *float32 `yaml:"hybrid"`
+
 	Embedder string `yaml:"embedder"`
 	Reranker string `yaml:"reranker"`
 }
@@ -73,6 +85,10 @@ func (cfg *Config) registerIndexes(f *configFile) error {
 			return err
 		}
 
+		if context.Reranker != nil {
+			index = reranker.New(index, context.Reranker)
+		}
+
 		if _, ok := index.(otel.Index); !ok {
 			index = otel.NewIndex(i.Type, id, index)
 		}
@@ -86,13 +102,16 @@ func (cfg *Config) registerIndexes(f *configFile) error {
 func createIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
 	switch strings.ToLower(cfg.Type) {
 	case "azure":
-		return azureIndex(cfg)
+		return azureIndex(cfg, context)
 
 	case "chroma":
 		return chromaIndex(cfg, context)
 
 	case "elasticsearch":
-		return elasticsearchIndex(cfg)
+		return elasticsearchIndex(cfg, context)
+
+	case "hnsw":
+		return hnswIndex(cfg, context)
 
 	case "memory":
 		return memoryIndex(cfg, context)
@@ -111,9 +130,17 @@ func createIndex(cfg indexConfig, context indexContext) (index.Provider, error)
 	}
 }
 
-func azureIndex(cfg indexConfig) (index.Provider, error) {
+func azureIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
 	var options []azure.Option
 
+	if context.Embedder != nil {
+		options = append(options, azure.WithEmbedder(context.Embedder))
+	}
+
+	if cfg.Hybrid != nil {
+		options = append(options, azure.WithHybrid(*cfg.Hybrid))
+	}
+
 	return azure.New(cfg.URL, cfg.Namespace, cfg.Token, options...)
 }
 
@@ -124,19 +151,49 @@ func chromaIndex(cfg indexConfig, context indexContext) (index.Provider, error)
 		options = append(options, chroma.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, chroma.WithReranker(context.Reranker))
-	}
-
 	return chroma.New(cfg.URL, cfg.Namespace, options...)
 }
 
-func elasticsearchIndex(cfg indexConfig) (index.Provider, error) {
+func elasticsearchIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
 	var options []elasticsearch.Option
 
+	if context.Embedder != nil {
+		options = append(options, elasticsearch.WithEmbedder(context.Embedder))
+	}
+
+	if cfg.Hybrid != nil {
+		options = append(options, elasticsearch.WithHybrid(*cfg.Hybrid))
+	}
+
 	return elasticsearch.New(cfg.URL, cfg.Namespace, options...)
 }
 
+func hnswIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
+	var options []hnsw.Option
+
+	if context.Embedder != nil {
+		options = append(options, hnsw.WithEmbedder(context.Embedder))
+	}
+
+	if cfg.Hybrid != nil {
+		options = append(options, hnsw.WithHybrid(*cfg.Hybrid))
+	}
+
+	if cfg.M > 0 {
+		options = append(options, hnsw.WithM(cfg.M))
+	}
+
+	if cfg.EfConstruction > 0 {
+		options = append(options, hnsw.WithEfConstruction(cfg.EfConstruction))
+	}
+
+	if cfg.EfSearch > 0 {
+		options = append(options, hnsw.WithEfSearch(cfg.EfSearch))
+	}
+
+	return hnsw.New(options...)
+}
+
 func memoryIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
 	var options []memory.Option
 
@@ -144,8 +201,16 @@ func memoryIndex(cfg indexConfig, context indexContext) (index.Provider, error)
 		options = append(options, memory.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, memory.WithReranker(context.Reranker))
+	if cfg.Hybrid != nil {
+		options = append(options, memory.WithHybrid(*cfg.Hybrid))
+	}
+
+	if cfg.Path != "" {
+		options = append(options, memory.WithPath(cfg.Path))
+	}
+
+	if cfg.SnapshotInterval > 0 {
+		options = append(options, memory.WithSnapshotInterval(cfg.SnapshotInterval))
 	}
 
 	return memory.New(options...)
@@ -158,8 +223,8 @@ func qdrantIndex(cfg indexConfig, context indexContext) (index.Provider, error)
 		options = append(options, qdrant.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, qdrant.WithReranker(context.Reranker))
+	if cfg.Hybrid != nil {
+		options = append(options, qdrant.WithHybrid(*cfg.Hybrid))
 	}
 
 	return qdrant.New(cfg.URL, cfg.Namespace, options...)
@@ -172,8 +237,8 @@ func weaviateIndex(cfg indexConfig, context indexContext) (index.Provider, error
 		options = append(options, weaviate.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, weaviate.WithReranker(context.Reranker))
+	if cfg.Hybrid != nil {
+		options = append(options, weaviate.WithHybrid(*cfg.Hybrid))
 	}
 
 	return weaviate.New(cfg.URL, cfg.Namespace, options...)
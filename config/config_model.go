@@ -4,8 +4,11 @@ import (
 	"errors"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/adrianliechti/llama/pkg/cache"
 	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
 	"golang.org/x/time/rate"
 )
 
@@ -66,6 +69,10 @@ type modelConfig struct {
 	Description string `yaml:"description"`
 
 	Limit *int `yaml:"limit"`
+
+	Cache *cacheConfig `yaml:"cache"`
+
+	Pricing *pricingConfig `yaml:"pricing"`
 }
 
 type modelContext struct {
@@ -77,6 +84,11 @@ type modelContext struct {
 	Description string
 
 	Limiter *rate.Limiter
+
+	Cache    cache.Store
+	CacheTTL time.Duration
+
+	Pricing *quota.Pricing
 }
 
 func DetectModelType(id string) ModelType {
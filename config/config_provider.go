@@ -3,8 +3,13 @@ package config
 import (
 	"errors"
 
+	"github.com/adrianliechti/llama/pkg/fim"
+	"github.com/adrianliechti/llama/pkg/jsonschema"
 	"github.com/adrianliechti/llama/pkg/limiter"
+	"github.com/adrianliechti/llama/pkg/matryoshka"
 	"github.com/adrianliechti/llama/pkg/otel"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
 
 	reranker "github.com/adrianliechti/llama/pkg/provider/adapter/reranker"
 	summarizer "github.com/adrianliechti/llama/pkg/summarizer/adapter"
@@ -43,6 +48,24 @@ func (cfg *Config) registerProviders(f *configFile) error {
 				context.Limiter = rate.NewLimiter(rate.Limit(*limit), *limit)
 			}
 
+			if m.Pricing != nil {
+				context.Pricing = &quota.Pricing{
+					Input:  m.Pricing.Input,
+					Output: m.Pricing.Output,
+				}
+			}
+
+			if m.Cache != nil {
+				store, err := createCache(*m.Cache)
+
+				if err != nil {
+					return err
+				}
+
+				context.Cache = store
+				context.CacheTTL = m.Cache.TTL
+			}
+
 			switch context.Type {
 			case ModelTypeCompleter:
 				completer, err := createCompleter(p, context)
@@ -51,14 +74,21 @@ func (cfg *Config) registerProviders(f *configFile) error {
 					return err
 				}
 
+				// limit the provider itself, so every upstream request of the decorators below (like schema retries) waits for its own token
 				if _, ok := completer.(limiter.Completer); !ok {
 					completer = limiter.NewCompleter(context.Limiter, completer)
 				}
 
-				if _, ok := completer.(otel.Completer); !ok {
-					completer = otel.NewCompleter(p.Type, id, completer)
+				if !nativeFIMSupport(p.Type) {
+					completer = fim.NewCompleter(completer)
 				}
 
+				if !nativeSchemaSupport(p.Type) {
+					completer = jsonschema.NewCompleter(completer)
+				}
+
+				completer = cfg.decorateCompleter(p.Type, id, context, completer)
+
 				cfg.RegisterCompleter(id, completer)
 				cfg.RegisterSummarizer(id, summarizer.FromCompleter(completer))
 
@@ -69,13 +99,11 @@ func (cfg *Config) registerProviders(f *configFile) error {
 					return err
 				}
 
-				if _, ok := embedder.(limiter.Embedder); !ok {
-					embedder = limiter.NewEmbedder(context.Limiter, embedder)
+				if !nativeDimensionsSupport(p.Type) {
+					embedder = matryoshka.NewEmbedder(embedder)
 				}
 
-				if _, ok := embedder.(otel.Embedder); !ok {
-					embedder = otel.NewEmbedder(p.Type, id, embedder)
-				}
+				embedder = cfg.decorateEmbedder(p.Type, id, context, embedder)
 
 				cfg.RegisterEmbedder(id, embedder)
 				cfg.RegisterReranker(id, reranker.FromEmbedder(embedder))
@@ -203,3 +231,31 @@ func (c *providerModelsConfig) UnmarshalYAML(value *yaml.Node) error {
 
 	return errors.New("invalid models config")
 }
+
+func (cfg *Config) decorateCompleter(providerType, id string, context modelContext, completer provider.Completer) provider.Completer {
+	if _, ok := completer.(otel.Completer); !ok {
+		completer = otel.NewCompleter(providerType, id, completer)
+	}
+
+	if _, ok := completer.(quota.Completer); !ok && cfg.quota != nil {
+		completer = quota.NewCompleter(cfg.quota, context.Pricing, completer)
+	}
+
+	return cacheCompleter(id, context, completer)
+}
+
+func (cfg *Config) decorateEmbedder(providerType, id string, context modelContext, embedder provider.Embedder) provider.Embedder {
+	if _, ok := embedder.(limiter.Embedder); !ok {
+		embedder = limiter.NewEmbedder(context.Limiter, embedder)
+	}
+
+	if _, ok := embedder.(otel.Embedder); !ok {
+		embedder = otel.NewEmbedder(providerType, id, embedder)
+	}
+
+	if _, ok := embedder.(quota.Embedder); !ok && cfg.quota != nil {
+		embedder = quota.NewEmbedder(cfg.quota, context.Pricing, embedder)
+	}
+
+	return cacheEmbedder(id, context, embedder)
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/adrianliechti/llama/pkg/quota"
+)
+
+type quotaConfig struct {
+	TokensPerMinute int `yaml:"tokens_per_minute"`
+	TokensPerDay    int `yaml:"tokens_per_day"`
+
+	SpendPerMonth float64 `yaml:"spend_per_month"`
+}
+
+type pricingConfig struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+func (cfg *Config) registerQuotas(f *configFile) error {
+	if len(f.Quotas) == 0 {
+		return nil
+	}
+
+	limits := make(map[string]quota.Limits)
+
+	for id, q := range f.Quotas {
+		limits[id] = quota.Limits{
+			TokensPerMinute: q.TokensPerMinute,
+			TokensPerDay:    q.TokensPerDay,
+
+			SpendPerMonth: q.SpendPerMonth,
+		}
+	}
+
+	cfg.quota = quota.New(limits)
+
+	return nil
+}
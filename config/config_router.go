@@ -2,60 +2,339 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router/balancer"
+	"github.com/adrianliechti/llama/pkg/router/fallback"
 	"github.com/adrianliechti/llama/pkg/router/roundrobin"
+
+	"gopkg.in/yaml.v3"
 )
 
 type routerConfig struct {
 	Type string `yaml:"type"`
 
-	Models []string `yaml:"models"`
+	Strategy string `yaml:"strategy"`
+
+	Threshold   *float64       `yaml:"threshold"`
+	MinRequests *int           `yaml:"min_requests"`
+	Cooldown    *time.Duration `yaml:"cooldown"`
+
+	Models routerModelsConfig `yaml:"models"`
+}
+
+type routerModelsConfig []routerModelConfig
+
+type routerModelConfig struct {
+	ID string `yaml:"id"`
+
+	Weight int `yaml:"weight"`
+}
+
+func (c *routerModelConfig) UnmarshalYAML(value *yaml.Node) error {
+	var id string
+
+	if err := value.Decode(&id); err == nil {
+		*c = routerModelConfig{
+			ID: id,
+		}
+
+		return nil
+	}
+
+	type config routerModelConfig
+
+	var model config
+
+	if err := value.Decode(&model); err != nil {
+		return errors.New("invalid router model config")
+	}
+
+	*c = routerModelConfig(model)
+	return nil
 }
 
 type routerContext struct {
-	Completers []provider.Completer
+	ID string
+
+	Models []routerModelConfig
+
+	Completers   []provider.Completer
+	Embedders    []provider.Embedder
+	Renderers    []provider.Renderer
+	Rerankers    []provider.Reranker
+	Synthesizers []provider.Synthesizer
+	Transcribers []provider.Transcriber
 }
 
 func (cfg *Config) registerRouters(f *configFile) error {
 	for id, r := range f.Routers {
-		context := routerContext{}
-
-		for _, m := range r.Models {
-			completer, err := cfg.Completer(m)
-
-			if err != nil {
-				return err
-			}
+		context, err := cfg.routerContext(id, r)
 
-			context.Completers = append(context.Completers, completer)
+		if err != nil {
+			return err
 		}
 
-		router, err := createRouter(r, context)
+		routers, err := createRouter(r, *context)
 
 		if err != nil {
 			return err
 		}
 
-		if completer, ok := router.(provider.Completer); ok {
-			cfg.RegisterCompleter(id, completer)
+		for _, router := range routers {
+			if completer, ok := router.(provider.Completer); ok {
+				cfg.RegisterCompleter(id, completer)
+			}
+
+			if embedder, ok := router.(provider.Embedder); ok {
+				cfg.RegisterEmbedder(id, embedder)
+			}
+
+			if renderer, ok := router.(provider.Renderer); ok {
+				cfg.RegisterRenderer(id, renderer)
+			}
+
+			if reranker, ok := router.(provider.Reranker); ok {
+				cfg.RegisterReranker(id, reranker)
+			}
+
+			if synthesizer, ok := router.(provider.Synthesizer); ok {
+				cfg.RegisterSynthesizer(id, synthesizer)
+			}
+
+			if transcriber, ok := router.(provider.Transcriber); ok {
+				cfg.RegisterTranscriber(id, transcriber)
+			}
 		}
 	}
 
 	return nil
 }
 
-func createRouter(cfg routerConfig, context routerContext) (any, error) {
+func (cfg *Config) routerContext(id string, r routerConfig) (*routerContext, error) {
+	context := &routerContext{
+		ID: id,
+
+		Models: r.Models,
+	}
+
+	if len(r.Models) == 0 {
+		return nil, errors.New("router has no models: " + id)
+	}
+
+	for _, m := range r.Models {
+		var found bool
+
+		if p, err := cfg.Completer(m.ID); err == nil {
+			context.Completers = append(context.Completers, p)
+			found = true
+		}
+
+		if p, err := cfg.Embedder(m.ID); err == nil {
+			context.Embedders = append(context.Embedders, p)
+			found = true
+		}
+
+		if p, err := cfg.Renderer(m.ID); err == nil {
+			context.Renderers = append(context.Renderers, p)
+			found = true
+		}
+
+		if p, err := cfg.Reranker(m.ID); err == nil {
+			context.Rerankers = append(context.Rerankers, p)
+			found = true
+		}
+
+		if p, err := cfg.Synthesizer(m.ID); err == nil {
+			context.Synthesizers = append(context.Synthesizers, p)
+			found = true
+		}
+
+		if p, err := cfg.Transcriber(m.ID); err == nil {
+			context.Transcribers = append(context.Transcribers, p)
+			found = true
+		}
+
+		// an unknown model would otherwise only surface as a missing common capability
+		if !found {
+			return nil, fmt.Errorf("router %s references unknown model: %s", id, m.ID)
+		}
+	}
+
+	if len(context.Completers) != len(r.Models) {
+		context.Completers = nil
+	}
+
+	if len(context.Embedders) != len(r.Models) {
+		context.Embedders = nil
+	}
+
+	if len(context.Renderers) != len(r.Models) {
+		context.Renderers = nil
+	}
+
+	if len(context.Rerankers) != len(r.Models) {
+		context.Rerankers = nil
+	}
+
+	if len(context.Synthesizers) != len(r.Models) {
+		context.Synthesizers = nil
+	}
+
+	if len(context.Transcribers) != len(r.Models) {
+		context.Transcribers = nil
+	}
+
+	if context.Completers == nil && context.Embedders == nil && context.Renderers == nil && context.Rerankers == nil && context.Synthesizers == nil && context.Transcribers == nil {
+		return nil, errors.New("router models do not share a common capability: " + id)
+	}
+
+	return context, nil
+}
+
+func createRouter(cfg routerConfig, context routerContext) ([]any, error) {
 	switch strings.ToLower(cfg.Type) {
 	case "roundrobin":
 		return roundrobinRouter(cfg, context)
 
+	case "fallback":
+		return fallbackRouter(cfg, context)
+
+	case "balancer":
+		return balancerRouter(cfg, context)
+
 	default:
 		return nil, errors.New("invalid router type: " + cfg.Type)
 	}
 }
 
-func roundrobinRouter(cfg routerConfig, context routerContext) (any, error) {
-	return roundrobin.NewCompleter(context.Completers...)
+func roundrobinRouter(cfg routerConfig, context routerContext) ([]any, error) {
+	return createRouters(context,
+		roundrobin.NewCompleter,
+		roundrobin.NewEmbedder,
+		roundrobin.NewRenderer,
+		roundrobin.NewReranker,
+		roundrobin.NewSynthesizer,
+		roundrobin.NewTranscriber,
+	)
+}
+
+func fallbackRouter(cfg routerConfig, context routerContext) ([]any, error) {
+	return createRouters(context,
+		fallback.NewCompleter,
+		fallback.NewEmbedder,
+		fallback.NewRenderer,
+		fallback.NewReranker,
+		fallback.NewSynthesizer,
+		fallback.NewTranscriber,
+	)
+}
+
+func balancerRouter(cfg routerConfig, context routerContext) ([]any, error) {
+	options := []balancer.Option{
+		balancer.WithName(context.ID),
+	}
+
+	if cfg.Strategy != "" {
+		options = append(options, balancer.WithStrategy(balancer.Strategy(strings.ToLower(cfg.Strategy))))
+	}
+
+	if cfg.Threshold != nil {
+		options = append(options, balancer.WithThreshold(*cfg.Threshold))
+	}
+
+	if cfg.MinRequests != nil {
+		options = append(options, balancer.WithMinRequests(*cfg.MinRequests))
+	}
+
+	if cfg.Cooldown != nil {
+		options = append(options, balancer.WithCooldown(*cfg.Cooldown))
+	}
+
+	return createRouters(context,
+		balancerRouterFunc(context.Models, balancer.NewCompleter, options),
+		balancerRouterFunc(context.Models, balancer.NewEmbedder, options),
+		balancerRouterFunc(context.Models, balancer.NewRenderer, options),
+		balancerRouterFunc(context.Models, balancer.NewReranker, options),
+		balancerRouterFunc(context.Models, balancer.NewSynthesizer, options),
+		balancerRouterFunc(context.Models, balancer.NewTranscriber, options),
+	)
+}
+
+type routerFunc[T any] func(providers ...T) (T, error)
+
+// createRouters creates a router for every capability the router models share
+func createRouters(
+	context routerContext,
+	completer routerFunc[provider.Completer],
+	embedder routerFunc[provider.Embedder],
+	renderer routerFunc[provider.Renderer],
+	reranker routerFunc[provider.Reranker],
+	synthesizer routerFunc[provider.Synthesizer],
+	transcriber routerFunc[provider.Transcriber],
+) ([]any, error) {
+	routers := []func() (any, error){
+		capabilityRouter(context.Completers, completer),
+		capabilityRouter(context.Embedders, embedder),
+		capabilityRouter(context.Renderers, renderer),
+		capabilityRouter(context.Rerankers, reranker),
+		capabilityRouter(context.Synthesizers, synthesizer),
+		capabilityRouter(context.Transcribers, transcriber),
+	}
+
+	var result []any
+
+	for _, router := range routers {
+		p, err := router()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if p != nil {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+func capabilityRouter[T any](providers []T, create routerFunc[T]) func() (any, error) {
+	return func() (any, error) {
+		if providers == nil {
+			return nil, nil
+		}
+
+		p, err := create(providers...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return p, nil
+	}
+}
+
+func balancerRouterFunc[T any](models []routerModelConfig, create func([]balancer.Backend[T], ...balancer.Option) (T, error), options []balancer.Option) routerFunc[T] {
+	return func(providers ...T) (T, error) {
+		return create(balancerBackends(models, providers), options...)
+	}
+}
+
+func balancerBackends[T any](models []routerModelConfig, providers []T) []balancer.Backend[T] {
+	var result []balancer.Backend[T]
+
+	for i, m := range models {
+		result = append(result, balancer.Backend[T]{
+			Name:   m.ID,
+			Weight: m.Weight,
+
+			Provider: providers[i],
+		})
+	}
+
+	return result
 }
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testEmbedder struct{}
+
+func (testEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	return &provider.Embedding{}, nil
+}
+
+func newRouterTestConfig() *Config {
+	cfg := &Config{}
+
+	cfg.RegisterCompleter("chat-a", &usageCompleter{})
+	cfg.RegisterCompleter("chat-b", &usageCompleter{})
+	cfg.RegisterEmbedder("embed", testEmbedder{})
+
+	return cfg
+}
+
+func TestRouterContext(t *testing.T) {
+	cfg := newRouterTestConfig()
+
+	context, err := cfg.routerContext("lb", routerConfig{
+		Models: routerModelsConfig{{ID: "chat-a"}, {ID: "chat-b"}},
+	})
+
+	require.NoError(t, err)
+
+	require.Len(t, context.Completers, 2)
+	require.Nil(t, context.Embedders)
+}
+
+func TestRouterContextUnknownModel(t *testing.T) {
+	cfg := newRouterTestConfig()
+
+	_, err := cfg.routerContext("lb", routerConfig{
+		Models: routerModelsConfig{{ID: "chat-a"}, {ID: "chat-c"}},
+	})
+
+	require.EqualError(t, err, "router lb references unknown model: chat-c")
+}
+
+func TestRouterContextNoCommonCapability(t *testing.T) {
+	cfg := newRouterTestConfig()
+
+	_, err := cfg.routerContext("lb", routerConfig{
+		Models: routerModelsConfig{{ID: "chat-a"}, {ID: "embed"}},
+	})
+
+	require.EqualError(t, err, "router models do not share a common capability: lb")
+}
+
+func TestRouterContextNoModels(t *testing.T) {
+	cfg := newRouterTestConfig()
+
+	_, err := cfg.routerContext("lb", routerConfig{})
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adrianliechti/llama/pkg/thread"
+	"github.com/adrianliechti/llama/pkg/thread/file"
+	"github.com/adrianliechti/llama/pkg/thread/memory"
+)
+
+type threadConfig struct {
+	Type string `yaml:"type"`
+
+	Path string `yaml:"path"`
+}
+
+func (cfg *Config) registerThreads(f *configFile) error {
+	var c threadConfig
+
+	if f.Threads != nil {
+		c = *f.Threads
+	}
+
+	store, err := createThreadStore(c)
+
+	if err != nil {
+		return err
+	}
+
+	cfg.threads = store
+
+	return nil
+}
+
+func (cfg *Config) Threads() thread.Store {
+	return cfg.threads
+}
+
+func createThreadStore(cfg threadConfig) (thread.Store, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "memory":
+		return memory.New()
+
+	case "file":
+		return file.New(cfg.Path)
+
+	default:
+		return nil, errors.New("invalid thread store type: " + cfg.Type)
+	}
+}
@@ -0,0 +1,18 @@
+package oidc
+
+type Option func(*Provider)
+
+func WithIssuer(url, audience string) Option {
+	return func(p *Provider) {
+		p.issuers = append(p.issuers, Issuer{
+			URL:      url,
+			Audience: audience,
+		})
+	}
+}
+
+func WithRule(rule Rule) Option {
+	return func(p *Provider) {
+		p.rules = append(p.rules, rule)
+	}
+}
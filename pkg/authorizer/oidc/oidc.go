@@ -3,55 +3,186 @@ package oidc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
+
 	"github.com/coreos/go-oidc/v3/oidc"
 )
 
 type Provider struct {
-	provider *oidc.Provider
-	verifier *oidc.IDTokenVerifier
+	issuers []Issuer
+	rules   []Rule
+
+	verifiers []*oidc.IDTokenVerifier
+}
+
+type Issuer struct {
+	URL      string
+	Audience string
+}
+
+type Rule struct {
+	Claim  string
+	Values []string
+
+	Models  []string
+	Indexes []string
 }
 
-func New(issuer, audience string) (*Provider, error) {
-	cfg := &oidc.Config{
-		ClientID: audience,
+func New(issuer, audience string, options ...Option) (*Provider, error) {
+	p := &Provider{}
+
+	if issuer != "" {
+		p.issuers = append(p.issuers, Issuer{
+			URL:      issuer,
+			Audience: audience,
+		})
 	}
 
-	provider, err := oidc.NewProvider(context.Background(), issuer)
+	for _, option := range options {
+		option(p)
+	}
 
-	if err != nil {
-		return nil, err
+	if len(p.issuers) == 0 {
+		return nil, errors.New("issuer is required")
 	}
 
-	verifier := provider.Verifier(cfg)
+	for _, i := range p.issuers {
+		cfg := &oidc.Config{
+			ClientID: i.Audience,
+		}
+
+		provider, err := oidc.NewProvider(context.Background(), i.URL)
+
+		if err != nil {
+			return nil, err
+		}
+
+		p.verifiers = append(p.verifiers, provider.Verifier(cfg))
+	}
 
-	return &Provider{
-		provider: provider,
-		verifier: verifier,
-	}, nil
+	return p, nil
 }
 
-func (p *Provider) Verify(ctx context.Context, r *http.Request) error {
+func (p *Provider) Verify(ctx context.Context, r *http.Request) (*authorizer.Principal, error) {
 	header := r.Header.Get("Authorization")
 
 	if header == "" {
-		return errors.New("missing authorization header")
+		return nil, errors.New("missing authorization header")
 	}
 
 	if !strings.HasPrefix(header, "Bearer ") {
-		return errors.New("invalid authorization header")
+		return nil, errors.New("invalid authorization header")
 	}
 
 	token := strings.TrimPrefix(header, "Bearer ")
 
-	idtoken, err := p.verifier.Verify(ctx, token)
+	var errs []error
+
+	for _, v := range p.verifiers {
+		idtoken, err := v.Verify(ctx, token)
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var claims map[string]any
+
+		if err := idtoken.Claims(&claims); err != nil {
+			return nil, err
+		}
+
+		return p.principal(idtoken.Issuer, idtoken.Subject, claims), nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (p *Provider) principal(issuer, subject string, claims map[string]any) *authorizer.Principal {
+	id := subject
+
+	// subjects are only unique per issuer
+	if len(p.issuers) > 1 {
+		id = issuer + "#" + subject
+	}
+
+	principal := &authorizer.Principal{
+		ID: id,
+	}
+
+	if email, ok := claims["email"].(string); ok {
+		principal.Email = email
+	}
+
+	if len(p.rules) == 0 {
+		return principal
+	}
+
+	principal.Models = []string{}
+	principal.Indexes = []string{}
+
+	for _, rule := range p.rules {
+		if !matchClaim(claims, rule.Claim, rule.Values) {
+			continue
+		}
+
+		principal.Models = append(principal.Models, rule.Models...)
+		principal.Indexes = append(principal.Indexes, rule.Indexes...)
+	}
+
+	return principal
+}
+
+func matchClaim(claims map[string]any, claim string, values []string) bool {
+	var value any = claims
+
+	for _, key := range strings.Split(claim, ".") {
+		m, ok := value.(map[string]any)
+
+		if !ok {
+			return false
+		}
+
+		value = m[key]
+	}
+
+	var actual []string
+
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return false
+		}
+
+		actual = []string{v}
+
+	case []any:
+		for _, item := range v {
+			actual = append(actual, fmt.Sprint(item))
+		}
+
+	case nil:
+		return false
+
+	default:
+		actual = []string{fmt.Sprint(v)}
+	}
+
+	if len(values) == 0 {
+		return len(actual) > 0
+	}
 
-	if err != nil {
-		return err
+	for _, a := range actual {
+		for _, v := range values {
+			if a == v {
+				return true
+			}
+		}
 	}
 
-	_ = idtoken
-	return nil
+	return false
 }
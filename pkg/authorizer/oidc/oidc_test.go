@@ -0,0 +1,103 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchClaim(t *testing.T) {
+	claims := map[string]any{
+		"email":  "alice@example.com",
+		"groups": []any{"admins", "developers"},
+		"level":  float64(3),
+		"empty":  "",
+		"none":   []any{},
+		"realm_access": map[string]any{
+			"roles": []any{"offline_access", "llm-user"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		claim  string
+		values []string
+		match  bool
+	}{
+		{"string", "email", []string{"alice@example.com"}, true},
+		{"string mismatch", "email", []string{"bob@example.com"}, false},
+		{"array", "groups", []string{"guests", "developers"}, true},
+		{"array mismatch", "groups", []string{"guests"}, false},
+		{"number", "level", []string{"3"}, true},
+		{"nested", "realm_access.roles", []string{"llm-user"}, true},
+		{"nested mismatch", "realm_access.roles", []string{"llm-admin"}, false},
+		{"nested missing", "realm_access.groups", []string{"llm-user"}, false},
+		{"nested through value", "email.domain", []string{"example.com"}, false},
+		{"missing", "roles", []string{"admins"}, false},
+		{"present", "groups", nil, true},
+		{"missing present", "roles", nil, false},
+		{"empty string present", "empty", nil, false},
+		{"empty string", "empty", []string{""}, false},
+		{"empty array present", "none", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.match, matchClaim(claims, test.claim, test.values))
+		})
+	}
+}
+
+func TestPrincipalRules(t *testing.T) {
+	p := &Provider{
+		issuers: []Issuer{{URL: "https://login.example.com"}},
+
+		rules: []Rule{
+			{Claim: "groups", Values: []string{"developers"}, Models: []string{"gpt-4o"}, Indexes: []string{"docs"}},
+			{Claim: "groups", Values: []string{"admins"}, Models: []string{"gpt-4o", "o1"}},
+			{Claim: "groups", Values: []string{"guests"}, Models: []string{"mini"}},
+		},
+	}
+
+	claims := map[string]any{
+		"email":  "alice@example.com",
+		"groups": []any{"admins", "developers"},
+	}
+
+	principal := p.principal("https://login.example.com", "alice", claims)
+
+	require.Equal(t, "alice", principal.ID)
+	require.Equal(t, "alice@example.com", principal.Email)
+
+	// every matching rule adds its models and indexes, in the order of the rules
+	require.Equal(t, []string{"gpt-4o", "gpt-4o", "o1"}, principal.Models)
+	require.Equal(t, []string{"docs"}, principal.Indexes)
+
+	// without a matching rule, nothing is allowed
+	principal = p.principal("https://login.example.com", "bob", map[string]any{})
+
+	require.Empty(t, principal.Models)
+	require.NotNil(t, principal.Models)
+	require.NotNil(t, principal.Indexes)
+
+	// without rules, everything is allowed
+	principal = (&Provider{}).principal("https://login.example.com", "carol", claims)
+
+	require.Nil(t, principal.Models)
+	require.Nil(t, principal.Indexes)
+}
+
+func TestPrincipalIssuers(t *testing.T) {
+	p := &Provider{
+		issuers: []Issuer{
+			{URL: "https://a.example.com"},
+			{URL: "https://b.example.com"},
+		},
+	}
+
+	a := p.principal("https://a.example.com", "1234", nil)
+	b := p.principal("https://b.example.com", "1234", nil)
+
+	require.Equal(t, "https://a.example.com#1234", a.ID)
+	require.Equal(t, "https://b.example.com#1234", b.ID)
+}
@@ -0,0 +1,75 @@
+package authorizer
+
+import (
+	"context"
+	"path"
+)
+
+type Principal struct {
+	ID    string
+	Email string
+
+	Models  []string
+	Indexes []string
+}
+
+func (p *Principal) AllowModel(id string) bool {
+	if p.Models == nil {
+		return true
+	}
+
+	return matchAny(p.Models, id)
+}
+
+func (p *Principal) AllowIndex(id string) bool {
+	if p.Indexes == nil {
+		return true
+	}
+
+	return matchAny(p.Indexes, id)
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+func AllowModel(ctx context.Context, id string) bool {
+	p, ok := PrincipalFromContext(ctx)
+
+	if !ok {
+		return true
+	}
+
+	return p.AllowModel(id)
+}
+
+func AllowIndex(ctx context.Context, id string) bool {
+	p, ok := PrincipalFromContext(ctx)
+
+	if !ok {
+		return true
+	}
+
+	return p.AllowIndex(id)
+}
+
+func matchAny(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if pattern == id {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern, id); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -6,5 +6,5 @@ import (
 )
 
 type Provider interface {
-	Verify(ctx context.Context, r *http.Request) error
+	Verify(ctx context.Context, r *http.Request) (*Principal, error)
 }
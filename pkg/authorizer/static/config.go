@@ -0,0 +1,9 @@
+package static
+
+type Option func(*Provider)
+
+func WithKey(key Key) Option {
+	return func(p *Provider) {
+		p.keys = append(p.keys, key)
+	}
+}
@@ -2,41 +2,99 @@ package static
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
 )
 
 type Provider struct {
 	token string
+
+	keys []Key
+}
+
+type Key struct {
+	Name  string
+	Token string
+
+	Models  []string
+	Indexes []string
+
+	Expires *time.Time
 }
 
-func New(token string) (*Provider, error) {
-	return &Provider{
+func New(token string, options ...Option) (*Provider, error) {
+	p := &Provider{
 		token: token,
-	}, nil
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	for _, k := range p.keys {
+		if k.Name == "" {
+			return nil, errors.New("key name is required")
+		}
+
+		if k.Token == "" {
+			return nil, errors.New("key token is required: " + k.Name)
+		}
+	}
+
+	return p, nil
 }
 
-func (p *Provider) Verify(ctx context.Context, r *http.Request) error {
-	if p.token == "" {
-		return nil
+func (p *Provider) Verify(ctx context.Context, r *http.Request) (*authorizer.Principal, error) {
+	if p.token == "" && len(p.keys) == 0 {
+		return &authorizer.Principal{
+			ID: "static",
+		}, nil
 	}
 
 	header := r.Header.Get("Authorization")
 
+	// anthropic clients send the key as x-api-key
+	if header == "" && r.Header.Get("X-Api-Key") != "" {
+		header = "Bearer " + r.Header.Get("X-Api-Key")
+	}
+
 	if header == "" {
-		return errors.New("missing authorization header")
+		return nil, errors.New("missing authorization header")
 	}
 
 	if !strings.HasPrefix(header, "Bearer ") {
-		return errors.New("invalid authorization header")
+		return nil, errors.New("invalid authorization header")
 	}
 
 	token := strings.TrimPrefix(header, "Bearer ")
 
-	if !strings.EqualFold(token, p.token) {
-		return errors.New("invalid token")
+	if p.token != "" && strings.EqualFold(token, p.token) {
+		return &authorizer.Principal{
+			ID: "static",
+		}, nil
+	}
+
+	for _, k := range p.keys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(k.Token)) != 1 {
+			continue
+		}
+
+		if k.Expires != nil && time.Now().After(*k.Expires) {
+			return nil, errors.New("token expired")
+		}
+
+		return &authorizer.Principal{
+			ID: k.Name,
+
+			Models:  k.Models,
+			Indexes: k.Indexes,
+		}, nil
 	}
 
-	return nil
+	return nil, errors.New("invalid token")
 }
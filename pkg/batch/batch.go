@@ -0,0 +1,75 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type Store interface {
+	CreateFile(ctx context.Context, f File, content io.Reader) (*File, error)
+	GetFile(ctx context.Context, id string) (*File, error)
+	OpenFile(ctx context.Context, id string) (io.ReadCloser, error)
+	ListFiles(ctx context.Context) ([]File, error)
+	DeleteFile(ctx context.Context, id string) error
+
+	CreateBatch(ctx context.Context, b Batch) error
+	UpdateBatch(ctx context.Context, b Batch) error
+	GetBatch(ctx context.Context, id string) (*Batch, error)
+	ListBatches(ctx context.Context) ([]Batch, error)
+}
+
+type File struct {
+	ID    string
+	Owner string
+
+	Name    string
+	Purpose string
+
+	Size int64
+
+	CreatedAt time.Time
+}
+
+type Status string
+
+const (
+	StatusValidating Status = "validating"
+	StatusFailed     Status = "failed"
+	StatusInProgress Status = "in_progress"
+	StatusFinalizing Status = "finalizing"
+	StatusCompleted  Status = "completed"
+	StatusCancelling Status = "cancelling"
+	StatusCancelled  Status = "cancelled"
+)
+
+type Batch struct {
+	ID    string
+	Owner string
+
+	Endpoint string
+	Window   string
+
+	InputFile  string
+	OutputFile string
+	ErrorFile  string
+
+	Status Status
+	Error  string
+
+	Total     int
+	Completed int
+	Failed    int
+
+	Metadata map[string]string
+
+	CreatedAt    time.Time
+	StartedAt    *time.Time
+	FinalizingAt *time.Time
+	CompletedAt  *time.Time
+	FailedAt     *time.Time
+	CancelledAt  *time.Time
+}
@@ -0,0 +1,294 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/adrianliechti/llama/pkg/batch"
+)
+
+var _ batch.Store = &Store{}
+
+type Store struct {
+	mu sync.Mutex
+
+	path string
+}
+
+func New(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	for _, dir := range []string{"files", "batches"} {
+		if err := os.MkdirAll(filepath.Join(path, dir), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Store{
+		path: path,
+	}, nil
+}
+
+func (s *Store) CreateFile(ctx context.Context, f batch.File, content io.Reader) (*batch.File, error) {
+	path, err := s.filePath("files", f.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.path, "files"), ".tmp-*")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, content)
+
+	if err != nil {
+		tmp.Close()
+		return nil, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	f.Size = size
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+
+	if err := s.writeJSON(path+".json", f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (s *Store) GetFile(ctx context.Context, id string) (*batch.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.filePath("files", id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var f batch.File
+
+	if err := s.readJSON(path+".json", &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (s *Store) OpenFile(ctx context.Context, id string) (io.ReadCloser, error) {
+	path, err := s.filePath("files", id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, batch.ErrNotFound
+	}
+
+	return f, err
+}
+
+func (s *Store) ListFiles(ctx context.Context) ([]batch.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []batch.File
+
+	err := s.list("files", func(path string) error {
+		var f batch.File
+
+		if err := s.readJSON(path, &f); err != nil {
+			return err
+		}
+
+		result = append(result, f)
+		return nil
+	})
+
+	return result, err
+}
+
+func (s *Store) DeleteFile(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.filePath("files", id)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path + ".json"); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return batch.ErrNotFound
+		}
+
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+func (s *Store) CreateBatch(ctx context.Context, b batch.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.filePath("batches", b.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return s.writeJSON(path+".json", b)
+}
+
+func (s *Store) UpdateBatch(ctx context.Context, b batch.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.filePath("batches", b.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path + ".json"); err != nil {
+		return batch.ErrNotFound
+	}
+
+	return s.writeJSON(path+".json", b)
+}
+
+func (s *Store) GetBatch(ctx context.Context, id string) (*batch.Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.filePath("batches", id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var b batch.Batch
+
+	if err := s.readJSON(path+".json", &b); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func (s *Store) ListBatches(ctx context.Context) ([]batch.Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []batch.Batch
+
+	err := s.list("batches", func(path string) error {
+		var b batch.Batch
+
+		if err := s.readJSON(path, &b); err != nil {
+			return err
+		}
+
+		result = append(result, b)
+		return nil
+	})
+
+	return result, err
+}
+
+func (s *Store) filePath(dir, id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return "", batch.ErrNotFound
+	}
+
+	return filepath.Join(s.path, dir, id), nil
+}
+
+func (s *Store) list(dir string, fn func(path string) error) error {
+	entries, err := os.ReadDir(filepath.Join(s.path, dir))
+
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		if err := fn(filepath.Join(s.path, dir, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return batch.ErrNotFound
+		}
+
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func (s *Store) writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
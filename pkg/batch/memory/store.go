@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/adrianliechti/llama/pkg/batch"
+)
+
+var _ batch.Store = &Store{}
+
+type Store struct {
+	mu sync.RWMutex
+
+	files    map[string]batch.File
+	contents map[string][]byte
+
+	batches map[string]batch.Batch
+}
+
+func New() (*Store, error) {
+	return &Store{
+		files:    make(map[string]batch.File),
+		contents: make(map[string][]byte),
+
+		batches: make(map[string]batch.Batch),
+	}, nil
+}
+
+func (s *Store) CreateFile(ctx context.Context, f batch.File, content io.Reader) (*batch.File, error) {
+	data, err := io.ReadAll(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	f.Size = int64(len(data))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[f.ID] = f
+	s.contents[f.ID] = data
+
+	return &f, nil
+}
+
+func (s *Store) GetFile(ctx context.Context, id string) (*batch.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.files[id]
+
+	if !ok {
+		return nil, batch.ErrNotFound
+	}
+
+	return &f, nil
+}
+
+func (s *Store) OpenFile(ctx context.Context, id string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.contents[id]
+
+	if !ok {
+		return nil, batch.ErrNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *Store) ListFiles(ctx context.Context) ([]batch.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]batch.File, 0, len(s.files))
+
+	for _, f := range s.files {
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+func (s *Store) DeleteFile(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.files[id]; !ok {
+		return batch.ErrNotFound
+	}
+
+	delete(s.files, id)
+	delete(s.contents, id)
+
+	return nil
+}
+
+func (s *Store) CreateBatch(ctx context.Context, b batch.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches[b.ID] = b
+	return nil
+}
+
+func (s *Store) UpdateBatch(ctx context.Context, b batch.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.batches[b.ID]; !ok {
+		return batch.ErrNotFound
+	}
+
+	s.batches[b.ID] = b
+	return nil
+}
+
+func (s *Store) GetBatch(ctx context.Context, id string) (*batch.Batch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.batches[id]
+
+	if !ok {
+		return nil, batch.ErrNotFound
+	}
+
+	return &b, nil
+}
+
+func (s *Store) ListBatches(ctx context.Context) ([]batch.Batch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]batch.Batch, 0, len(s.batches))
+
+	for _, b := range s.batches {
+		result = append(result, b)
+	}
+
+	return result, nil
+}
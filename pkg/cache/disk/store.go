@@ -0,0 +1,114 @@
+package disk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/cache"
+)
+
+var _ cache.Store = &Store{}
+
+type Store struct {
+	path string
+}
+
+type entry struct {
+	Value []byte `json:"value"`
+
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+func New(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	s := &Store{
+		path: path,
+	}
+
+	if err := os.MkdirAll(s.path, 0755); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool) {
+	path := s.filePath(key)
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, false
+	}
+
+	var item entry
+
+	if err := json.Unmarshal(data, &item); err != nil {
+		os.Remove(path)
+		return nil, false
+	}
+
+	if item.Expires != nil && time.Now().After(*item.Expires) {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return item.Value, true
+}
+
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	item := entry{
+		Value: value,
+	}
+
+	if ttl > 0 {
+		expires := time.Now().Add(ttl)
+		item.Expires = &expires
+	}
+
+	data, err := json.Marshal(item)
+
+	if err != nil {
+		return err
+	}
+
+	path := s.filePath(key)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (s *Store) filePath(key string) string {
+	if len(key) > 2 {
+		return filepath.Join(s.path, key[:2], key)
+	}
+
+	return filepath.Join(s.path, key)
+}
@@ -0,0 +1,9 @@
+package memory
+
+type Option func(*Store)
+
+func WithSize(size int) Option {
+	return func(s *Store) {
+		s.size = size
+	}
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/cache"
+)
+
+var _ cache.Store = &Store{}
+
+type Store struct {
+	mu sync.Mutex
+
+	size int
+
+	items   map[string]*list.Element
+	entries *list.List
+}
+
+type entry struct {
+	key   string
+	value []byte
+
+	expires time.Time
+}
+
+func New(options ...Option) (*Store, error) {
+	s := &Store{
+		size: 1000,
+
+		items:   make(map[string]*list.Element),
+		entries: list.New(),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s, nil
+}
+
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.items[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	item := e.Value.(*entry)
+
+	if !item.expires.IsZero() && time.Now().After(item.expires) {
+		s.remove(e)
+		return nil, false
+	}
+
+	s.entries.MoveToFront(e)
+
+	return item.value, true
+}
+
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expires time.Time
+
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	if e, ok := s.items[key]; ok {
+		item := e.Value.(*entry)
+		item.value = value
+		item.expires = expires
+
+		s.entries.MoveToFront(e)
+		return nil
+	}
+
+	s.items[key] = s.entries.PushFront(&entry{
+		key:   key,
+		value: value,
+
+		expires: expires,
+	})
+
+	for s.size > 0 && s.entries.Len() > s.size {
+		s.remove(s.entries.Back())
+	}
+
+	return nil
+}
+
+func (s *Store) remove(e *list.Element) {
+	item := e.Value.(*entry)
+
+	delete(s.items, item.key)
+	s.entries.Remove(e)
+}
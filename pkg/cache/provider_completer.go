@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Completer interface {
+	Cache
+	provider.Completer
+}
+
+type cachedCompleter struct {
+	model string
+
+	store Store
+	ttl   time.Duration
+
+	provider provider.Completer
+}
+
+func NewCompleter(model string, s Store, ttl time.Duration, p provider.Completer) Completer {
+	return &cachedCompleter{
+		model: model,
+
+		store: s,
+		ttl:   ttl,
+
+		provider: p,
+	}
+}
+
+func (p *cachedCompleter) cacheSetup() {
+}
+
+func (p *cachedCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil {
+		options = new(provider.CompleteOptions)
+	}
+
+	if p.store == nil || !cacheable(messages, options) {
+		return p.provider.Complete(ctx, messages, options)
+	}
+
+	key, err := cacheKey(struct {
+		Model    string
+		Messages []provider.Message
+		Tools    []provider.Tool
+
+		ToolChoice *provider.ToolChoice
+
+		Stop      []string
+		MaxTokens *int
+		Format    provider.CompletionFormat
+		Schema    *provider.Schema
+		Suffix    string
+
+		TopP             *float32
+		Seed             *int
+		PresencePenalty  *float32
+		FrequencyPenalty *float32
+
+		N           *int
+		Logprobs    bool
+		TopLogprobs *int
+	}{
+		p.model,
+		messages,
+		options.Tools,
+
+		options.ToolChoice,
+
+		options.Stop,
+		options.MaxTokens,
+		options.Format,
+		options.Schema,
+		options.Suffix,
+
+		options.TopP,
+		options.Seed,
+		options.PresencePenalty,
+		options.FrequencyPenalty,
+
+		options.N,
+		options.Logprobs,
+		options.TopLogprobs,
+	})
+
+	if err != nil {
+		return p.provider.Complete(ctx, messages, options)
+	}
+
+	if data, ok := p.store.Get(ctx, key); ok {
+		var completion provider.Completion
+
+		if err := json.Unmarshal(data, &completion); err == nil {
+			if options.Stream != nil {
+				if err := options.Stream(ctx, completion); err != nil {
+					return nil, err
+				}
+			}
+
+			return &completion, nil
+		}
+	}
+
+	result, err := p.provider.Complete(ctx, messages, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		p.store.Set(ctx, key, data, p.ttl)
+	}
+
+	return result, nil
+}
+
+func cacheable(messages []provider.Message, options *provider.CompleteOptions) bool {
+	if options.Temperature == nil || *options.Temperature != 0 {
+		return false
+	}
+
+	for _, m := range messages {
+		if len(m.Files) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
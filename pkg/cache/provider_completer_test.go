@@ -0,0 +1,198 @@
+package cache_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/cache"
+	"github.com/adrianliechti/llama/pkg/cache/memory"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingCompleter answers with the number of calls it received
+type countingCompleter struct {
+	calls int
+}
+
+func (c *countingCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.calls++
+
+	return &provider.Completion{
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: strings.Repeat("a", c.calls),
+		},
+	}, nil
+}
+
+func newTestStore(t *testing.T) cache.Store {
+	t.Helper()
+
+	s, err := memory.New()
+	require.NoError(t, err)
+
+	return s
+}
+
+func temperature(v float32) *float32 {
+	return &v
+}
+
+func userMessages(content string) []provider.Message {
+	return []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: content,
+		},
+	}
+}
+
+func TestCompleterHit(t *testing.T) {
+	ctx := context.Background()
+
+	p := &countingCompleter{}
+	c := cache.NewCompleter("model", newTestStore(t), 0, p)
+
+	options := &provider.CompleteOptions{Temperature: temperature(0)}
+
+	first, err := c.Complete(ctx, userMessages("hello"), options)
+	require.NoError(t, err)
+
+	var streamed []string
+
+	second, err := c.Complete(ctx, userMessages("hello"), &provider.CompleteOptions{
+		Temperature: temperature(0),
+
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			streamed = append(streamed, completion.Message.Content)
+			return nil
+		},
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, 1, p.calls)
+	require.Equal(t, first.Message.Content, second.Message.Content)
+
+	// hits are replayed to streaming callers as a single chunk
+	require.Equal(t, []string{first.Message.Content}, streamed)
+}
+
+func TestCompleterTemperature(t *testing.T) {
+	ctx := context.Background()
+
+	for _, options := range []*provider.CompleteOptions{
+		nil,
+		{},
+		{Temperature: temperature(0.5)},
+	} {
+		p := &countingCompleter{}
+		c := cache.NewCompleter("model", newTestStore(t), 0, p)
+
+		for range 2 {
+			_, err := c.Complete(ctx, userMessages("hello"), options)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 2, p.calls)
+	}
+}
+
+func TestCompleterFiles(t *testing.T) {
+	ctx := context.Background()
+
+	p := &countingCompleter{}
+	c := cache.NewCompleter("model", newTestStore(t), 0, p)
+
+	messages := []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "describe",
+
+			Files: []provider.File{
+				{
+					Name:    "image.png",
+					Content: strings.NewReader("png"),
+				},
+			},
+		},
+	}
+
+	for range 2 {
+		_, err := c.Complete(ctx, messages, &provider.CompleteOptions{Temperature: temperature(0)})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, p.calls)
+}
+
+func TestCompleterKey(t *testing.T) {
+	ctx := context.Background()
+
+	tool := provider.Tool{
+		Name: "weather",
+
+		Parameters: map[string]any{
+			"type": "object",
+		},
+	}
+
+	schema := &provider.Schema{
+		Name: "answer",
+
+		Schema: map[string]any{
+			"type": "object",
+		},
+	}
+
+	requests := map[string]struct {
+		model    string
+		messages []provider.Message
+		options  *provider.CompleteOptions
+	}{
+		"base": {
+			"model", userMessages("hello"), &provider.CompleteOptions{Temperature: temperature(0)},
+		},
+
+		"messages": {
+			"model", userMessages("hello!"), &provider.CompleteOptions{Temperature: temperature(0)},
+		},
+
+		"tools": {
+			"model", userMessages("hello"), &provider.CompleteOptions{Temperature: temperature(0), Tools: []provider.Tool{tool}},
+		},
+
+		"schema": {
+			"model", userMessages("hello"), &provider.CompleteOptions{Temperature: temperature(0), Format: provider.CompletionFormatJSONSchema, Schema: schema},
+		},
+
+		"model": {
+			"other", userMessages("hello"), &provider.CompleteOptions{Temperature: temperature(0)},
+		},
+	}
+
+	s := newTestStore(t)
+	p := &countingCompleter{}
+
+	for _, r := range requests {
+		c := cache.NewCompleter(r.model, s, 0, p)
+
+		_, err := c.Complete(ctx, r.messages, r.options)
+		require.NoError(t, err)
+	}
+
+	// every request differs from the base request in one aspect only, so none of them is a hit
+	require.Equal(t, len(requests), p.calls)
+
+	for _, r := range requests {
+		c := cache.NewCompleter(r.model, s, 0, p)
+
+		_, err := c.Complete(ctx, r.messages, r.options)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, len(requests), p.calls)
+}
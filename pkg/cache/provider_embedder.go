@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Embedder interface {
+	Cache
+	provider.Embedder
+}
+
+type cachedEmbedder struct {
+	model string
+
+	store Store
+	ttl   time.Duration
+
+	provider provider.Embedder
+}
+
+func NewEmbedder(model string, s Store, ttl time.Duration, p provider.Embedder) Embedder {
+	return &cachedEmbedder{
+		model: model,
+
+		store: s,
+		ttl:   ttl,
+
+		provider: p,
+	}
+}
+
+func (p *cachedEmbedder) cacheSetup() {
+}
+
+func (p *cachedEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	if p.store == nil {
+		return p.provider.Embed(ctx, texts, options)
+	}
+
+	var dimensions *int
+
+	if options != nil {
+		dimensions = options.Dimensions
+	}
+
+	keys := make([]string, len(texts))
+	embeddings := make([][]float32, len(texts))
+
+	var missing []int
+
+	for i, text := range texts {
+		key, err := cacheKey(struct {
+			Model   string
+			Content string
+
+			Dimensions *int
+		}{
+			p.model,
+			text,
+
+			dimensions,
+		})
+
+		if err != nil {
+			return p.provider.Embed(ctx, texts, options)
+		}
+
+		keys[i] = key
+
+		if data, ok := p.store.Get(ctx, key); ok {
+			var embedding []float32
+
+			if err := json.Unmarshal(data, &embedding); err == nil {
+				embeddings[i] = embedding
+				continue
+			}
+		}
+
+		missing = append(missing, i)
+	}
+
+	result := &provider.Embedding{
+		Embeddings: embeddings,
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	input := make([]string, len(missing))
+
+	for i, index := range missing {
+		input[i] = texts[index]
+	}
+
+	embedding, err := p.provider.Embed(ctx, input, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embedding.Embeddings) != len(input) {
+		return nil, errors.New("invalid embeddings count")
+	}
+
+	for i, index := range missing {
+		embeddings[index] = embedding.Embeddings[i]
+
+		if data, err := json.Marshal(embedding.Embeddings[i]); err == nil {
+			p.store.Set(ctx, keys[index], data, p.ttl)
+		}
+	}
+
+	result.Usage = embedding.Usage
+
+	return result, nil
+}
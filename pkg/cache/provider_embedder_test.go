@@ -0,0 +1,69 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/cache"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmbedder embeds texts as their length and records the texts it received
+type recordingEmbedder struct {
+	texts [][]string
+}
+
+func (e *recordingEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	e.texts = append(e.texts, texts)
+
+	result := &provider.Embedding{}
+
+	for _, text := range texts {
+		result.Embeddings = append(result.Embeddings, []float32{float32(len(text))})
+	}
+
+	return result, nil
+}
+
+func TestEmbedderMissingTexts(t *testing.T) {
+	ctx := context.Background()
+
+	p := &recordingEmbedder{}
+	e := cache.NewEmbedder("model", newTestStore(t), 0, p)
+
+	_, err := e.Embed(ctx, []string{"a", "bb"}, nil)
+	require.NoError(t, err)
+
+	result, err := e.Embed(ctx, []string{"bb", "ccc"}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{{"a", "bb"}, {"ccc"}}, p.texts)
+	require.Equal(t, [][]float32{{2}, {3}}, result.Embeddings)
+}
+
+func TestEmbedderKey(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestStore(t)
+	p := &recordingEmbedder{}
+
+	dimensions := 256
+
+	_, err := cache.NewEmbedder("model", s, 0, p).Embed(ctx, []string{"a"}, nil)
+	require.NoError(t, err)
+
+	_, err = cache.NewEmbedder("model", s, 0, p).Embed(ctx, []string{"a"}, &provider.EmbedOptions{Dimensions: &dimensions})
+	require.NoError(t, err)
+
+	_, err = cache.NewEmbedder("other", s, 0, p).Embed(ctx, []string{"a"}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, p.texts, 3)
+
+	_, err = cache.NewEmbedder("model", s, 0, p).Embed(ctx, []string{"a"}, &provider.EmbedOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, p.texts, 3)
+}
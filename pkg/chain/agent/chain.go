@@ -61,6 +61,18 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 		options = new(provider.CompleteOptions)
 	}
 
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("agent chain", "multiple choices")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("agent chain", "logprobs")
+	}
+
+	if options.ToolChoice != nil && options.ToolChoice.Mode != provider.ToolChoiceModeAuto {
+		return nil, provider.UnsupportedOption("agent chain", "tool choice")
+	}
+
 	if len(c.messages) > 0 {
 		values, err := template.ApplyMessages(c.messages, nil)
 
@@ -101,8 +113,15 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 
 		MaxTokens:   options.MaxTokens,
 		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		Seed: options.Seed,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
 
 		Format: options.Format,
+		Schema: options.Schema,
 	}
 
 	if len(options.Tools) > 0 {
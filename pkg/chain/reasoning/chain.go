@@ -56,6 +56,18 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 		options = new(provider.CompleteOptions)
 	}
 
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("reasoning chain", "multiple choices")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("reasoning chain", "logprobs")
+	}
+
+	if options.ToolChoice != nil && options.ToolChoice.Mode != provider.ToolChoiceModeAuto {
+		return nil, provider.UnsupportedOption("reasoning chain", "tool choice")
+	}
+
 	if options.Temperature == nil {
 		options.Temperature = c.temperature
 	}
@@ -81,6 +93,12 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 	inputOptions := &provider.CompleteOptions{
 		MaxTokens:   options.MaxTokens,
 		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		Seed: options.Seed,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
 
 		Format: provider.CompletionFormatJSON,
 	}
@@ -0,0 +1,108 @@
+package fim
+
+import (
+	"context"
+	"strings"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/template"
+)
+
+var _ provider.Completer = (*Completer)(nil)
+
+var defaultTemplate = template.MustTemplate(`Fill in the missing text at <FILL> so that it connects the text before and after it.
+Respond only with the missing text. Do not repeat the surrounding text and do not add any explanations or markdown.
+
+{{ .Prefix }}<FILL>{{ .Suffix }}`)
+
+type Completer struct {
+	template *template.Template
+
+	completer provider.Completer
+}
+
+type Option func(*Completer)
+
+func NewCompleter(completer provider.Completer, options ...Option) *Completer {
+	c := &Completer{
+		template: defaultTemplate,
+
+		completer: completer,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+func WithTemplate(template *template.Template) Option {
+	return func(c *Completer) {
+		c.template = template
+	}
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil || options.Suffix == "" || len(messages) == 0 {
+		return c.completer.Complete(ctx, messages, options)
+	}
+
+	prefix := messages[len(messages)-1].Content
+
+	prompt, err := c.template.Execute(map[string]any{
+		"Prefix": prefix,
+		"Suffix": options.Suffix,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	input := append([]provider.Message{}, messages[:len(messages)-1]...)
+
+	input = append(input, provider.Message{
+		Role:    provider.MessageRoleUser,
+		Content: prompt,
+	})
+
+	inputOptions := *options
+	inputOptions.Stream = nil
+	inputOptions.Suffix = ""
+
+	completion, err := c.completer.Complete(ctx, input, &inputOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	completion.Message.Content = trimCompletion(completion.Message.Content, prefix, options.Suffix)
+
+	for i := range completion.Choices {
+		completion.Choices[i].Message.Content = trimCompletion(completion.Choices[i].Message.Content, prefix, options.Suffix)
+	}
+
+	if options.Stream != nil {
+		if err := options.Stream(ctx, *completion); err != nil {
+			return nil, err
+		}
+	}
+
+	return completion, nil
+}
+
+// chat models tend to wrap the middle in code blocks or to echo the surrounding text
+func trimCompletion(s, prefix, suffix string) string {
+	if trimmed := strings.TrimSpace(s); strings.HasPrefix(trimmed, "```") {
+		trimmed = strings.TrimSuffix(trimmed, "```")
+
+		if i := strings.Index(trimmed, "\n"); i >= 0 {
+			s = strings.TrimSuffix(trimmed[i+1:], "\n")
+		}
+	}
+
+	s = strings.TrimPrefix(s, prefix)
+	s = strings.TrimSuffix(s, suffix)
+
+	return s
+}
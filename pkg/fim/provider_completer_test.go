@@ -0,0 +1,116 @@
+package fim
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/template"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCompleter records its last request and answers with a fixed content
+type recordingCompleter struct {
+	content string
+
+	messages []provider.Message
+	options  *provider.CompleteOptions
+}
+
+func (c *recordingCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.messages = messages
+	c.options = options
+
+	return &provider.Completion{
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: c.content,
+		},
+	}, nil
+}
+
+func TestCompletePrompt(t *testing.T) {
+	inner := &recordingCompleter{content: "return a + b"}
+	c := NewCompleter(inner)
+
+	messages := []provider.Message{
+		{Role: provider.MessageRoleSystem, Content: "you write go"},
+		{Role: provider.MessageRoleUser, Content: "func add(a, b int) int {\n\t"},
+	}
+
+	var chunks []string
+
+	completion, err := c.Complete(context.Background(), messages, &provider.CompleteOptions{
+		Suffix: "\n}",
+
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			chunks = append(chunks, completion.Message.Content)
+			return nil
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "return a + b", completion.Message.Content)
+
+	// the answer is streamed as a single chunk once it is trimmed
+	require.Equal(t, []string{"return a + b"}, chunks)
+
+	require.Len(t, inner.messages, 2)
+	require.Equal(t, messages[0], inner.messages[0])
+
+	require.Equal(t, provider.MessageRoleUser, inner.messages[1].Role)
+	require.True(t, strings.HasSuffix(inner.messages[1].Content, "\n\nfunc add(a, b int) int {\n\t<FILL>\n}"))
+
+	require.Empty(t, inner.options.Suffix)
+	require.Nil(t, inner.options.Stream)
+}
+
+func TestCompleteTemplate(t *testing.T) {
+	inner := &recordingCompleter{content: "middle"}
+	c := NewCompleter(inner, WithTemplate(template.MustTemplate(`<PRE>{{ .Prefix }}<SUF>{{ .Suffix }}<MID>`)))
+
+	_, err := c.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "start"}}, &provider.CompleteOptions{
+		Suffix: "end",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "<PRE>start<SUF>end<MID>", inner.messages[0].Content)
+}
+
+func TestCompleteWithoutSuffix(t *testing.T) {
+	inner := &recordingCompleter{content: "```\nunchanged\n```"}
+	c := NewCompleter(inner)
+
+	messages := []provider.Message{{Role: provider.MessageRoleUser, Content: "hello"}}
+
+	completion, err := c.Complete(context.Background(), messages, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, messages, inner.messages)
+	require.Equal(t, "```\nunchanged\n```", completion.Message.Content)
+}
+
+func TestTrimCompletion(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{"plain", "return a + b", "return a + b"},
+		{"whitespace", "\treturn a + b\n", "\treturn a + b\n"},
+		{"code block", "```\nreturn a + b\n```", "return a + b"},
+		{"code block with language", "```go\nreturn a + b\n```", "return a + b"},
+		{"code block with whitespace", "  ```go\nreturn a + b\n```\n", "return a + b"},
+		{"echoed prefix", "func add(a, b int) int {return a + b", "return a + b"},
+		{"echoed suffix", "return a + b}", "return a + b"},
+		{"echoed prefix in code block", "```go\nfunc add(a, b int) int {return a + b}\n```", "return a + b"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.output, trimCompletion(test.input, "func add(a, b int) int {", "}"))
+		})
+	}
+}
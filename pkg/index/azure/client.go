@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/adrianliechti/llama/pkg/index"
 )
@@ -23,6 +24,13 @@ type Client struct {
 	token string
 
 	namespace string
+
+	embedder index.Embedder
+
+	hybrid *float32
+
+	mu         sync.Mutex
+	dimensions int
 }
 
 func New(url, namespace, token string, options ...Option) (*Client, error) {
@@ -80,10 +88,40 @@ func convertError(resp *http.Response) error {
 }
 
 func (c *Client) ensureCollection(ctx context.Context, name string) error {
-	return c.upsertCollection(ctx, name)
+	dimensions, err := c.embeddingDimensions(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return c.upsertCollection(ctx, name, dimensions)
 }
 
-func (c *Client) upsertCollection(ctx context.Context, name string) error {
+// embeddingDimensions probes the embedder once, the index schema needs the vector size
+func (c *Client) embeddingDimensions(ctx context.Context) (int, error) {
+	if c.embedder == nil {
+		return 0, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dimensions > 0 {
+		return c.dimensions, nil
+	}
+
+	embedding, err := c.embedder.Embed(ctx, []string{"init"}, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	c.dimensions = len(embedding.Embeddings[0])
+
+	return c.dimensions, nil
+}
+
+func (c *Client) upsertCollection(ctx context.Context, name string, dimensions int) error {
 	body := map[string]any{
 		"name": name,
 
@@ -122,6 +160,35 @@ func (c *Client) upsertCollection(ctx context.Context, name string) error {
 		},
 	}
 
+	if dimensions > 0 {
+		body["fields"] = append(body["fields"].([]map[string]any), map[string]any{
+			"name": "embedding",
+			"type": "Collection(Edm.Single)",
+
+			"searchable":  true,
+			"retrievable": false,
+
+			"dimensions":          dimensions,
+			"vectorSearchProfile": "default",
+		})
+
+		body["vectorSearch"] = map[string]any{
+			"algorithms": []map[string]any{
+				{
+					"name": "hnsw",
+					"kind": "hnsw",
+				},
+			},
+
+			"profiles": []map[string]any{
+				{
+					"name":      "default",
+					"algorithm": "hnsw",
+				},
+			},
+		}
+	}
+
 	req, _ := http.NewRequestWithContext(ctx, "PUT", c.requestURL("/indexes/"+name, nil), jsonReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("api-key", c.token)
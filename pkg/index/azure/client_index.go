@@ -13,6 +13,12 @@ func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
 		return err
 	}
 
+	documents, err := index.EmbedDocuments(ctx, c.embedder, documents)
+
+	if err != nil {
+		return err
+	}
+
 	items := []map[string]any{}
 
 	for _, d := range documents {
@@ -32,6 +38,10 @@ func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
 			"location": d.Location,
 		}
 
+		if len(d.Embedding) > 0 {
+			item["embedding"] = d.Embedding
+		}
+
 		if len(d.Metadata) > 0 {
 			metadata := []map[string]string{}
 
@@ -3,7 +3,6 @@ package azure
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 
 	"github.com/adrianliechti/llama/pkg/index"
@@ -19,15 +18,61 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		options.Limit = to.Ptr(10)
 	}
 
-	queries := map[string]string{
-		"search": query,
+	hybrid := c.hybrid
+
+	if options.Hybrid != nil {
+		hybrid = options.Hybrid
+	}
+
+	if c.embedder == nil {
+		hybrid = nil
+	}
+
+	body := map[string]any{
+		"top": *options.Limit,
+	}
+
+	if hybrid == nil || *hybrid < 1 {
+		body["search"] = query
+	}
+
+	if options.Filter != nil {
+		filter, err := convertFilter(*options.Filter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body["filter"] = filter
 	}
 
-	if options.Limit != nil {
-		queries["$top"] = fmt.Sprintf("%d", *options.Limit)
+	if hybrid != nil && *hybrid > 0 {
+		embedding, err := c.embedder.Embed(ctx, []string{query}, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		vectorQuery := map[string]any{
+			"kind":   "vector",
+			"fields": "embedding",
+
+			"vector": embedding.Embeddings[0],
+			"k":      max(*options.Limit, 50),
+		}
+
+		// the text query has a fixed weight of 1 in the service's reciprocal rank fusion
+		if *hybrid < 1 {
+			vectorQuery["weight"] = *hybrid / (1 - *hybrid)
+		}
+
+		body["vectorQueries"] = []map[string]any{
+			vectorQuery,
+		}
 	}
 
-	req, _ := http.NewRequestWithContext(ctx, "GET", c.requestURL("/indexes/"+c.namespace+"/docs", queries), nil)
+	req, _ := http.NewRequestWithContext(ctx, "POST", c.requestURL("/indexes/"+c.namespace+"/docs/search", nil), jsonReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("api-key", c.token)
 
 	resp, err := c.client.Do(req)
@@ -38,6 +83,10 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, convertError(resp)
+	}
+
 	var result Results
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -48,6 +97,8 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 
 	for _, r := range result.Value {
 		result := index.Result{
+			Score: r.Score(),
+
 			Document: index.Document{
 				ID: r.ID(),
 
@@ -2,6 +2,8 @@ package azure
 
 import (
 	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/index"
 )
 
 type Option func(*Client)
@@ -11,3 +13,16 @@ func WithClient(client *http.Client) Option {
 		c.client = client
 	}
 }
+
+func WithEmbedder(embedder index.Embedder) Option {
+	return func(c *Client) {
+		c.embedder = embedder
+	}
+}
+
+// WithHybrid fuses keyword and vector results by default, weight ranges from 0 (keyword only) to 1 (vector only)
+func WithHybrid(weight float32) Option {
+	return func(c *Client) {
+		c.hybrid = &weight
+	}
+}
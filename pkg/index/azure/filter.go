@@ -0,0 +1,85 @@
+package azure
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+// convertFilter translates a filter into an OData expression on the metadata key/value collection
+func convertFilter(f index.Filter) (string, error) {
+	if len(f.And) > 0 || len(f.Or) > 0 {
+		separator, filters := " and ", f.And
+
+		if len(f.Or) > 0 {
+			separator, filters = " or ", f.Or
+		}
+
+		var expressions []string
+
+		for _, c := range filters {
+			expression, err := convertFilter(c)
+
+			if err != nil {
+				return "", err
+			}
+
+			expressions = append(expressions, expression)
+		}
+
+		return "(" + strings.Join(expressions, separator) + ")", nil
+	}
+
+	var condition string
+
+	switch f.Operator {
+	case index.OperatorEqual, index.OperatorNotEqual:
+		condition = "m/value eq " + quote(f.Value)
+
+	case index.OperatorGreater:
+		condition = "m/value gt " + quote(f.Value)
+
+	case index.OperatorGreaterEqual:
+		condition = "m/value ge " + quote(f.Value)
+
+	case index.OperatorLess:
+		condition = "m/value lt " + quote(f.Value)
+
+	case index.OperatorLessEqual:
+		condition = "m/value le " + quote(f.Value)
+
+	case index.OperatorIn, index.OperatorNotIn:
+		var values []string
+
+		for _, v := range f.Values {
+			values = append(values, "m/value eq "+quote(v))
+		}
+
+		condition = "(" + strings.Join(values, " or ") + ")"
+
+	case index.OperatorPrefix:
+		// a prefix is the range up to the prefix with its last character incremented
+		condition = "m/value ge " + quote(f.Value)
+
+		if r, size := utf8.DecodeLastRuneInString(f.Value); r != utf8.RuneError {
+			condition += " and m/value lt " + quote(f.Value[:len(f.Value)-size]+string(r+1))
+		}
+
+	default:
+		return "", errors.New("unsupported filter operator: " + string(f.Operator))
+	}
+
+	expression := "metadata/any(m: m/key eq " + quote(f.Field) + " and " + condition + ")"
+
+	if f.Operator == index.OperatorNotEqual || f.Operator == index.OperatorNotIn {
+		expression = "not " + expression
+	}
+
+	return expression, nil
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,57 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   index.Filter
+		expected string
+	}{
+		{"eq", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: "o'neil"},
+			`metadata/any(m: m/key eq 'author' and m/value eq 'o''neil')`},
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "alice"},
+			`not metadata/any(m: m/key eq 'author' and m/value eq 'alice')`},
+		{"gt", index.Filter{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+			`metadata/any(m: m/key eq 'year' and m/value gt '2020')`},
+		{"gte", index.Filter{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2020"},
+			`metadata/any(m: m/key eq 'year' and m/value ge '2020')`},
+		{"lt", index.Filter{Field: "year", Operator: index.OperatorLess, Value: "2020"},
+			`metadata/any(m: m/key eq 'year' and m/value lt '2020')`},
+		{"lte", index.Filter{Field: "year", Operator: index.OperatorLessEqual, Value: "2020"},
+			`metadata/any(m: m/key eq 'year' and m/value le '2020')`},
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"alice", "bob"}},
+			`metadata/any(m: m/key eq 'author' and (m/value eq 'alice' or m/value eq 'bob'))`},
+		{"nin", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"alice"}},
+			`not metadata/any(m: m/key eq 'author' and (m/value eq 'alice'))`},
+		{"prefix", index.Filter{Field: "author", Operator: index.OperatorPrefix, Value: "al"},
+			`metadata/any(m: m/key eq 'author' and m/value ge 'al' and m/value lt 'am')`},
+		{"and", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+		}}, `(metadata/any(m: m/key eq 'author' and m/value eq 'alice') and metadata/any(m: m/key eq 'year' and m/value gt '2020'))`},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+		}}, `(metadata/any(m: m/key eq 'author' and m/value eq 'alice') or metadata/any(m: m/key eq 'author' and m/value eq 'bob'))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertFilter(tt.filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestConvertFilterUnsupported(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "author", Operator: "like", Value: "al"})
+	require.Error(t, err)
+}
@@ -54,6 +54,14 @@ func (r Result) Metadata() map[string]string {
 	return nil
 }
 
+func (r Result) Score() float32 {
+	if val, ok := r["@search.score"].(float64); ok {
+		return float32(val)
+	}
+
+	return 0
+}
+
 func (r Result) String(name string) string {
 	val, ok := r[name]
 
@@ -0,0 +1,163 @@
+package bm25
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Index is an in-memory Okapi BM25 keyword index, it is not safe for concurrent use
+type Index struct {
+	postings map[string]map[string]int
+
+	terms   map[string][]string
+	lengths map[string]int
+
+	total int
+}
+
+type Result struct {
+	ID    string
+	Score float32
+}
+
+func New() *Index {
+	return &Index{
+		postings: make(map[string]map[string]int),
+
+		terms:   make(map[string][]string),
+		lengths: make(map[string]int),
+	}
+}
+
+// Tokenize splits text into lower-cased runs of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func (i *Index) Len() int {
+	return len(i.lengths)
+}
+
+// Add indexes the text of a document, replacing an earlier version with the same id
+func (i *Index) Add(id, text string) {
+	i.Remove(id)
+
+	tokens := Tokenize(text)
+	frequencies := make(map[string]int)
+
+	for _, t := range tokens {
+		frequencies[t]++
+	}
+
+	terms := make([]string, 0, len(frequencies))
+
+	for t, n := range frequencies {
+		postings, ok := i.postings[t]
+
+		if !ok {
+			postings = make(map[string]int)
+			i.postings[t] = postings
+		}
+
+		postings[id] = n
+		terms = append(terms, t)
+	}
+
+	i.terms[id] = terms
+	i.lengths[id] = len(tokens)
+
+	i.total += len(tokens)
+}
+
+func (i *Index) Remove(id string) {
+	length, ok := i.lengths[id]
+
+	if !ok {
+		return
+	}
+
+	for _, t := range i.terms[id] {
+		delete(i.postings[t], id)
+
+		if len(i.postings[t]) == 0 {
+			delete(i.postings, t)
+		}
+	}
+
+	delete(i.terms, id)
+	delete(i.lengths, id)
+
+	i.total -= length
+}
+
+// Search returns up to limit matching documents by descending score, accept may be nil
+func (i *Index) Search(query string, limit int, accept func(id string) bool) []Result {
+	if len(i.lengths) == 0 {
+		return nil
+	}
+
+	count := float64(len(i.lengths))
+	average := float64(i.total) / count
+
+	scores := make(map[string]float64)
+	seen := make(map[string]bool)
+
+	for _, t := range Tokenize(query) {
+		if seen[t] {
+			continue
+		}
+
+		seen[t] = true
+		postings := i.postings[t]
+
+		if len(postings) == 0 {
+			continue
+		}
+
+		n := float64(len(postings))
+		idf := math.Log(1 + (count-n+0.5)/(n+0.5))
+
+		for id, f := range postings {
+			tf := float64(f)
+			length := float64(i.lengths[id])
+
+			scores[id] += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*length/average))
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+
+	for id, score := range scores {
+		if accept != nil && !accept(id) {
+			continue
+		}
+
+		results = append(results, Result{
+			ID:    id,
+			Score: float32(score),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].ID < results[j].ID
+		}
+
+		return results[i].Score > results[j].Score
+	})
+
+	if limit >= 0 && limit < len(results) {
+		results = results[:limit]
+	}
+
+	return results
+}
@@ -0,0 +1,105 @@
+package bm25_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index/bm25"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndex() *bm25.Index {
+	i := bm25.New()
+
+	i.Add("a", "The quick brown fox")
+	i.Add("b", "the lazy dog")
+	i.Add("c", "Quick, quick fox!")
+
+	return i
+}
+
+func resultIDs(results []bm25.Result) []string {
+	var ids []string
+
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	require.Equal(t, []string{"hello", "world", "42x", "café", "über"}, bm25.Tokenize("Hello, World!  42x café-Über"))
+	require.Empty(t, bm25.Tokenize(" ,.;- "))
+}
+
+func TestSearchScore(t *testing.T) {
+	i := newTestIndex()
+
+	result := i.Search("dog", -1, nil)
+	require.Len(t, result, 1)
+
+	// dog occurs once in b, a document of 3 tokens in an index of 3 documents with 10 tokens
+	idf := math.Log(1 + (3-1+0.5)/(1+0.5))
+	score := idf * 1 * (1.2 + 1) / (1 + 1.2*(1-0.75+0.75*3/(10.0/3)))
+
+	require.Equal(t, "b", result[0].ID)
+	require.InDelta(t, score, result[0].Score, 1e-6)
+
+	// repeated query terms are counted once
+	require.Equal(t, result, i.Search("dog DOG dog", -1, nil))
+}
+
+func TestSearchRanking(t *testing.T) {
+	i := newTestIndex()
+
+	// c contains quick twice in fewer tokens than a
+	require.Equal(t, []string{"c", "a"}, resultIDs(i.Search("quick", -1, nil)))
+
+	// rare terms outweigh common ones
+	require.Equal(t, []string{"b", "a"}, resultIDs(i.Search("the lazy", -1, nil)))
+
+	require.Empty(t, i.Search("cat", -1, nil))
+	require.Empty(t, bm25.New().Search("quick", -1, nil))
+}
+
+func TestSearchLimitAndAccept(t *testing.T) {
+	i := newTestIndex()
+
+	// a matches all query terms
+	require.Equal(t, []string{"a", "c", "b"}, resultIDs(i.Search("quick fox the", -1, nil)))
+	require.Equal(t, []string{"a", "c"}, resultIDs(i.Search("quick fox the", 2, nil)))
+	require.Empty(t, i.Search("quick fox the", 0, nil))
+
+	accept := func(id string) bool {
+		return id != "c"
+	}
+
+	// the limit applies after filtering
+	require.Equal(t, []string{"a", "b"}, resultIDs(i.Search("quick fox the", 2, accept)))
+}
+
+func TestRemove(t *testing.T) {
+	i := newTestIndex()
+
+	i.Remove("c")
+	i.Remove("missing")
+
+	require.Equal(t, 2, i.Len())
+	require.Equal(t, []string{"a"}, resultIDs(i.Search("quick", -1, nil)))
+
+	// re-adding a document replaces its terms
+	i.Add("a", "slow turtle")
+
+	require.Equal(t, 2, i.Len())
+	require.Empty(t, i.Search("quick", -1, nil))
+	require.Equal(t, []string{"a"}, resultIDs(i.Search("turtle", -1, nil)))
+
+	// scores only depend on the current documents
+	fresh := bm25.New()
+	fresh.Add("a", "slow turtle")
+	fresh.Add("b", "the lazy dog")
+
+	require.Equal(t, fresh.Search("turtle dog", -1, nil), i.Search("turtle dog", -1, nil))
+}
@@ -24,7 +24,6 @@ type Client struct {
 	namespace string
 
 	embedder index.Embedder
-	reranker index.Reranker
 }
 
 func New(url, namespace string, options ...Option) (*Client, error) {
@@ -119,6 +118,12 @@ func (c *Client) List(ctx context.Context, options *index.ListOptions) ([]index.
 }
 
 func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
+	documents, err := index.EmbedDocuments(ctx, c.embedder, documents)
+
+	if err != nil {
+		return err
+	}
+
 	if len(documents) == 0 {
 		return nil
 	}
@@ -151,16 +156,6 @@ func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
 			metadata = make(map[string]string)
 		}
 
-		if len(d.Embedding) == 0 && c.embedder != nil {
-			embedding, err := c.embedder.Embed(ctx, d.Content)
-
-			if err != nil {
-				return err
-			}
-
-			d.Embedding = embedding.Data
-		}
-
 		body.IDs[i] = d.ID
 
 		body.Embeddings[i] = d.Embedding
@@ -223,7 +218,7 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		return nil, err
 	}
 
-	embedding, err := c.embedder.Embed(ctx, query)
+	embedding, err := c.embedder.Embed(ctx, []string{query}, nil)
 
 	if err != nil {
 		return nil, err
@@ -233,7 +228,7 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 
 	body := map[string]any{
 		"query_embeddings": [][]float32{
-			embedding.Data,
+			embedding.Embeddings[0],
 		},
 
 		"include": []string{
@@ -243,8 +238,14 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		},
 	}
 
-	if len(options.Filters) > 0 {
-		body["where"] = options.Filters
+	if options.Filter != nil {
+		where, err := convertFilter(*options.Filter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body["where"] = where
 	}
 
 	if options.Limit != nil {
@@ -0,0 +1,70 @@
+package chroma
+
+import (
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+var operators = map[index.Operator]string{
+	index.OperatorEqual:        "$eq",
+	index.OperatorNotEqual:     "$ne",
+	index.OperatorGreater:      "$gt",
+	index.OperatorGreaterEqual: "$gte",
+	index.OperatorLess:         "$lt",
+	index.OperatorLessEqual:    "$lte",
+	index.OperatorIn:           "$in",
+	index.OperatorNotIn:        "$nin",
+}
+
+// convertFilter translates a filter into a where clause, chroma only compares numbers in ranges
+func convertFilter(f index.Filter) (map[string]any, error) {
+	if len(f.And) > 0 || len(f.Or) > 0 {
+		key, filters := "$and", f.And
+
+		if len(f.Or) > 0 {
+			key, filters = "$or", f.Or
+		}
+
+		var clauses []map[string]any
+
+		for _, c := range filters {
+			clause, err := convertFilter(c)
+
+			if err != nil {
+				return nil, err
+			}
+
+			clauses = append(clauses, clause)
+		}
+
+		// chroma requires at least two operands
+		if len(clauses) == 1 {
+			return clauses[0], nil
+		}
+
+		return map[string]any{key: clauses}, nil
+	}
+
+	op, ok := operators[f.Operator]
+
+	if !ok {
+		return nil, errors.New("unsupported filter operator: " + string(f.Operator))
+	}
+
+	var value any = f.Value
+
+	if f.Operator == index.OperatorIn || f.Operator == index.OperatorNotIn {
+		value = f.Values
+	}
+
+	if n, ok := f.Number(); ok && f.IsRange() {
+		value = n
+	}
+
+	return map[string]any{
+		f.Field: map[string]any{
+			op: value,
+		},
+	}, nil
+}
@@ -0,0 +1,56 @@
+package chroma
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   index.Filter
+		expected string
+	}{
+		{"eq", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: "alice"}, `{"author": {"$eq": "alice"}}`},
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "alice"}, `{"author": {"$ne": "alice"}}`},
+		{"gt", index.Filter{Field: "year", Operator: index.OperatorGreater, Value: "2020"}, `{"year": {"$gt": 2020}}`},
+		{"gte", index.Filter{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2020"}, `{"year": {"$gte": 2020}}`},
+		{"lt", index.Filter{Field: "year", Operator: index.OperatorLess, Value: "2020.5"}, `{"year": {"$lt": 2020.5}}`},
+		{"lte", index.Filter{Field: "year", Operator: index.OperatorLessEqual, Value: "2020"}, `{"year": {"$lte": 2020}}`},
+		{"eq number", index.Filter{Field: "year", Operator: index.OperatorEqual, Value: "2020"}, `{"year": {"$eq": "2020"}}`},
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"alice", "bob"}}, `{"author": {"$in": ["alice", "bob"]}}`},
+		{"nin", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"alice"}}, `{"author": {"$nin": ["alice"]}}`},
+		{"and single", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+		}}, `{"author": {"$eq": "alice"}}`},
+		{"and", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+		}}, `{"$and": [{"author": {"$eq": "alice"}}, {"year": {"$gt": 2020}}]}`},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+		}}, `{"$or": [{"author": {"$eq": "alice"}}, {"author": {"$eq": "bob"}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertFilter(tt.filter)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+
+			require.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestConvertFilterUnsupported(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "author", Operator: index.OperatorPrefix, Value: "al"})
+	require.Error(t, err)
+}
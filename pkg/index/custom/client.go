@@ -75,10 +75,23 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		limit = &val
 	}
 
+	var filters map[string]string
+
+	if options.Filter != nil {
+		val, err := convertFilter(*options.Filter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filters = val
+	}
+
 	data, err := c.client.Query(ctx, &QueryRequest{
 		Query: query,
 
-		Limit: limit,
+		Limit:   limit,
+		Filters: filters,
 	})
 
 	if err != nil {
@@ -109,3 +122,40 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 
 	return results, nil
 }
+
+// convertFilter maps equality comparisons to the filters of the protocol, which cannot express other operators
+func convertFilter(f index.Filter) (map[string]string, error) {
+	if f.IsComparison() {
+		if f.Operator != index.OperatorEqual {
+			return nil, errors.New("custom index only supports eq filters")
+		}
+
+		return map[string]string{
+			f.Field: f.Value,
+		}, nil
+	}
+
+	if len(f.Or) > 0 {
+		return nil, errors.New("custom index does not support or filters")
+	}
+
+	result := make(map[string]string)
+
+	for _, c := range f.And {
+		filters, err := convertFilter(c)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range filters {
+			if existing, ok := result[k]; ok && existing != v {
+				return nil, errors.New("custom index does not support conflicting filters on " + k)
+			}
+
+			result[k] = v
+		}
+	}
+
+	return result, nil
+}
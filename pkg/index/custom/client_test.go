@@ -0,0 +1,45 @@
+package custom
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	result, err := convertFilter(index.Filter{And: []index.Filter{
+		{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+		{And: []index.Filter{
+			{Field: "year", Operator: index.OperatorEqual, Value: "2020"},
+		}},
+	}})
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"author": "alice", "year": "2020"}, result)
+}
+
+func TestConvertFilterUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter index.Filter
+	}{
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "alice"}},
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"alice"}}},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+		}}},
+		{"conflicting", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := convertFilter(tt.filter)
+			require.Error(t, err)
+		})
+	}
+}
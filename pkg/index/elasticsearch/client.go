@@ -22,6 +22,10 @@ type Client struct {
 	url string
 
 	namespace string
+
+	embedder index.Embedder
+
+	hybrid *float32
 }
 
 func New(url, namespace string, options ...Option) (*Client, error) {
@@ -57,6 +61,10 @@ func (c *Client) List(ctx context.Context, options *index.ListOptions) ([]index.
 		"query": map[string]any{
 			"match_all": map[string]any{},
 		},
+
+		"_source": map[string]any{
+			"excludes": []string{"embedding"},
+		},
 	}
 
 	req, _ := http.NewRequestWithContext(ctx, "GET", u, jsonReader(body))
@@ -101,6 +109,18 @@ func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
 		return nil
 	}
 
+	documents, err := index.EmbedDocuments(ctx, c.embedder, documents)
+
+	if err != nil {
+		return err
+	}
+
+	if c.embedder != nil {
+		if err := c.ensureMapping(ctx, len(documents[0].Embedding)); err != nil {
+			return err
+		}
+	}
+
 	for _, d := range documents {
 		if d.ID == "" {
 			d.ID = uuid.NewString()
@@ -114,6 +134,8 @@ func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
 
 			Content:  d.Content,
 			Metadata: d.Metadata,
+
+			Embedding: d.Embedding,
 		}
 
 		u, _ := url.JoinPath(c.url, "/"+c.namespace+"/_doc/"+convertID(d.ID))
@@ -158,16 +180,121 @@ func (c *Client) Delete(ctx context.Context, ids ...string) error {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
-	u, _ := url.JoinPath(c.url, "/"+c.namespace+"/_search")
+	if options == nil {
+		options = new(index.QueryOptions)
+	}
 
-	body := map[string]any{
-		"query": map[string]any{
+	limit := 10
+
+	if options.Limit != nil {
+		limit = *options.Limit
+	}
+
+	hybrid := c.hybrid
+
+	if options.Hybrid != nil {
+		hybrid = options.Hybrid
+	}
+
+	if c.embedder == nil {
+		hybrid = nil
+	}
+
+	var filter map[string]any
+
+	if options.Filter != nil {
+		var err error
+
+		if filter, err = convertFilter(*options.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	size := limit
+
+	if hybrid != nil {
+		size = max(limit, 50)
+	}
+
+	var results []index.Result
+
+	if hybrid == nil || *hybrid < 1 {
+		match := map[string]any{
 			"multi_match": map[string]any{
 				"query":    query,
 				"fields":   []string{"content", "metadata.*"},
 				"analyzer": "english",
 			},
-		},
+		}
+
+		if filter != nil {
+			match = map[string]any{
+				"bool": map[string]any{
+					"must":   match,
+					"filter": filter,
+				},
+			}
+		}
+
+		body := map[string]any{
+			"size":  size,
+			"query": match,
+		}
+
+		keywords, err := c.search(ctx, body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = keywords
+	}
+
+	if hybrid != nil {
+		embedding, err := c.embedder.Embed(ctx, []string{query}, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		knn := map[string]any{
+			"field":        "embedding",
+			"query_vector": embedding.Embeddings[0],
+
+			"k":              size,
+			"num_candidates": max(size*2, 100),
+		}
+
+		if filter != nil {
+			knn["filter"] = filter
+		}
+
+		body := map[string]any{
+			"size": size,
+			"knn":  knn,
+		}
+
+		vectors, err := c.search(ctx, body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = index.Fuse(vectors, results, *hybrid)
+
+		if len(results) > limit {
+			results = results[:limit]
+		}
+	}
+
+	return results, nil
+}
+
+func (c *Client) search(ctx context.Context, body map[string]any) ([]index.Result, error) {
+	u, _ := url.JoinPath(c.url, "/"+c.namespace+"/_search")
+
+	body["_source"] = map[string]any{
+		"excludes": []string{"embedding"},
 	}
 
 	req, _ := http.NewRequestWithContext(ctx, "GET", u, jsonReader(body))
@@ -179,6 +306,8 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		return nil, err
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, convertError(resp)
 	}
@@ -210,6 +339,60 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 	return results, nil
 }
 
+// ensureMapping declares the embedding field as dense vector, which dynamic mapping would index as plain floats
+func (c *Client) ensureMapping(ctx context.Context, dims int) error {
+	properties := map[string]any{
+		"embedding": map[string]any{
+			"type":       "dense_vector",
+			"dims":       dims,
+			"index":      true,
+			"similarity": "cosine",
+		},
+	}
+
+	u, _ := url.JoinPath(c.url, "/"+c.namespace)
+
+	req, _ := http.NewRequestWithContext(ctx, "HEAD", u, nil)
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	body := map[string]any{
+		"mappings": map[string]any{
+			"properties": properties,
+		},
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		u, _ = url.JoinPath(u, "_mapping")
+
+		body = map[string]any{
+			"properties": properties,
+		}
+	}
+
+	req, _ = http.NewRequestWithContext(ctx, "PUT", u, jsonReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = c.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return convertError(resp)
+	}
+
+	return nil
+}
+
 func convertID(id string) string {
 	if id == "" {
 		return uuid.NewString()
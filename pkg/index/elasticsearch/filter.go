@@ -0,0 +1,66 @@
+package elasticsearch
+
+import (
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+// convertFilter translates a filter into a bool query, relying on the default dynamic mapping
+// which indexes metadata strings as text with a keyword subfield (or as date if they look like one)
+func convertFilter(f index.Filter) (map[string]any, error) {
+	if len(f.And) > 0 || len(f.Or) > 0 {
+		var clauses []any
+
+		for _, c := range append(f.And, f.Or...) {
+			clause, err := convertFilter(c)
+
+			if err != nil {
+				return nil, err
+			}
+
+			clauses = append(clauses, clause)
+		}
+
+		if len(f.Or) > 0 {
+			return map[string]any{"bool": map[string]any{"should": clauses, "minimum_should_match": 1}}, nil
+		}
+
+		return map[string]any{"bool": map[string]any{"filter": clauses}}, nil
+	}
+
+	field := "metadata." + f.Field
+	keyword := field + ".keyword"
+
+	switch f.Operator {
+	case index.OperatorEqual:
+		return map[string]any{"term": map[string]any{keyword: map[string]any{"value": f.Value, "case_insensitive": true}}}, nil
+
+	case index.OperatorNotEqual:
+		return map[string]any{"bool": map[string]any{"must_not": []any{
+			map[string]any{"term": map[string]any{keyword: map[string]any{"value": f.Value, "case_insensitive": true}}},
+		}}}, nil
+
+	case index.OperatorIn:
+		return map[string]any{"terms": map[string]any{keyword: f.Values}}, nil
+
+	case index.OperatorNotIn:
+		return map[string]any{"bool": map[string]any{"must_not": []any{
+			map[string]any{"terms": map[string]any{keyword: f.Values}},
+		}}}, nil
+
+	case index.OperatorGreater, index.OperatorGreaterEqual, index.OperatorLess, index.OperatorLessEqual:
+		var value any = f.Value
+
+		if n, ok := f.Number(); ok {
+			value = n
+		}
+
+		return map[string]any{"range": map[string]any{field: map[string]any{string(f.Operator): value}}}, nil
+
+	case index.OperatorPrefix:
+		return map[string]any{"prefix": map[string]any{keyword: map[string]any{"value": f.Value, "case_insensitive": true}}}, nil
+	}
+
+	return nil, errors.New("unsupported filter operator: " + string(f.Operator))
+}
@@ -0,0 +1,62 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   index.Filter
+		expected string
+	}{
+		{"eq", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			`{"term": {"metadata.author.keyword": {"value": "alice", "case_insensitive": true}}}`},
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "alice"},
+			`{"bool": {"must_not": [{"term": {"metadata.author.keyword": {"value": "alice", "case_insensitive": true}}}]}}`},
+		{"gt", index.Filter{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+			`{"range": {"metadata.year": {"gt": 2020}}}`},
+		{"gte", index.Filter{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2020"},
+			`{"range": {"metadata.year": {"gte": 2020}}}`},
+		{"lt date", index.Filter{Field: "date", Operator: index.OperatorLess, Value: "2024-01-15"},
+			`{"range": {"metadata.date": {"lt": "2024-01-15"}}}`},
+		{"lte", index.Filter{Field: "year", Operator: index.OperatorLessEqual, Value: "2020"},
+			`{"range": {"metadata.year": {"lte": 2020}}}`},
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"alice", "bob"}},
+			`{"terms": {"metadata.author.keyword": ["alice", "bob"]}}`},
+		{"nin", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"alice"}},
+			`{"bool": {"must_not": [{"terms": {"metadata.author.keyword": ["alice"]}}]}}`},
+		{"prefix", index.Filter{Field: "author", Operator: index.OperatorPrefix, Value: "al"},
+			`{"prefix": {"metadata.author.keyword": {"value": "al", "case_insensitive": true}}}`},
+		{"and", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorIn, Values: []string{"alice"}},
+			{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+		}}, `{"bool": {"filter": [{"terms": {"metadata.author.keyword": ["alice"]}}, {"range": {"metadata.year": {"gt": 2020}}}]}}`},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorIn, Values: []string{"alice"}},
+			{Field: "author", Operator: index.OperatorIn, Values: []string{"bob"}},
+		}}, `{"bool": {"should": [{"terms": {"metadata.author.keyword": ["alice"]}}, {"terms": {"metadata.author.keyword": ["bob"]}}], "minimum_should_match": 1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertFilter(tt.filter)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+
+			require.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestConvertFilterUnsupported(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "author", Operator: "like", Value: "al"})
+	require.Error(t, err)
+}
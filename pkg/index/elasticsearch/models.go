@@ -9,6 +9,8 @@ type Document struct {
 	Content string `json:"content"`
 
 	Metadata map[string]string `json:"metadata"`
+
+	Embedding []float32 `json:"embedding,omitempty"`
 }
 
 type SearchResult struct {
@@ -0,0 +1,177 @@
+package index
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type Operator string
+
+const (
+	OperatorEqual        Operator = "eq"
+	OperatorNotEqual     Operator = "ne"
+	OperatorGreater      Operator = "gt"
+	OperatorGreaterEqual Operator = "gte"
+	OperatorLess         Operator = "lt"
+	OperatorLessEqual    Operator = "lte"
+	OperatorIn           Operator = "in"
+	OperatorNotIn        Operator = "nin"
+	OperatorPrefix       Operator = "prefix"
+)
+
+// Filter is a node of a metadata filter expression, either a combination of And or Or filters
+// or a comparison of the metadata Field with Value (or Values for In and NotIn)
+type Filter struct {
+	And []Filter
+	Or  []Filter
+
+	Field    string
+	Operator Operator
+
+	Value  string
+	Values []string
+}
+
+func (f Filter) IsComparison() bool {
+	return f.Field != ""
+}
+
+func (f Filter) IsRange() bool {
+	switch f.Operator {
+	case OperatorGreater, OperatorGreaterEqual, OperatorLess, OperatorLessEqual:
+		return true
+	}
+
+	return false
+}
+
+func (f Filter) Validate() error {
+	if f.IsComparison() {
+		if len(f.And) > 0 || len(f.Or) > 0 {
+			return errors.New("filter must be either a comparison or a combination")
+		}
+
+		switch f.Operator {
+		case OperatorEqual, OperatorNotEqual, OperatorGreater, OperatorGreaterEqual, OperatorLess, OperatorLessEqual, OperatorPrefix:
+			return nil
+
+		case OperatorIn, OperatorNotIn:
+			if len(f.Values) == 0 {
+				return errors.New("filter operator " + string(f.Operator) + " requires values")
+			}
+
+			return nil
+
+		default:
+			return errors.New("invalid filter operator: " + string(f.Operator))
+		}
+	}
+
+	if len(f.And) > 0 && len(f.Or) > 0 {
+		return errors.New("filter must not combine and with or")
+	}
+
+	if len(f.And) == 0 && len(f.Or) == 0 {
+		return errors.New("filter requires a field, and or or")
+	}
+
+	for _, c := range append(f.And, f.Or...) {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Match evaluates the filter against metadata, equality is case-insensitive, ranges compare numbers if
+// both sides are numeric and strings otherwise (which orders ISO dates), and a missing field only matches ne and nin
+func (f Filter) Match(metadata map[string]string) bool {
+	if len(f.And) > 0 {
+		for _, c := range f.And {
+			if !c.Match(metadata) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if len(f.Or) > 0 {
+		for _, c := range f.Or {
+			if c.Match(metadata) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	val, ok := metadata[f.Field]
+
+	if !ok {
+		return f.Operator == OperatorNotEqual || f.Operator == OperatorNotIn
+	}
+
+	switch f.Operator {
+	case OperatorEqual:
+		return strings.EqualFold(val, f.Value)
+
+	case OperatorNotEqual:
+		return !strings.EqualFold(val, f.Value)
+
+	case OperatorGreater:
+		return compare(val, f.Value) > 0
+
+	case OperatorGreaterEqual:
+		return compare(val, f.Value) >= 0
+
+	case OperatorLess:
+		return compare(val, f.Value) < 0
+
+	case OperatorLessEqual:
+		return compare(val, f.Value) <= 0
+
+	case OperatorIn, OperatorNotIn:
+		found := false
+
+		for _, v := range f.Values {
+			if strings.EqualFold(val, v) {
+				found = true
+				break
+			}
+		}
+
+		return found == (f.Operator == OperatorIn)
+
+	case OperatorPrefix:
+		return strings.HasPrefix(strings.ToLower(val), strings.ToLower(f.Value))
+	}
+
+	return false
+}
+
+// Number returns the comparison value as number, if it is numeric
+func (f Filter) Number() (float64, bool) {
+	n, err := strconv.ParseFloat(f.Value, 64)
+	return n, err == nil
+}
+
+func compare(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,118 @@
+package index_test
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter index.Filter
+		valid  bool
+	}{
+		{"comparison", index.Filter{Field: "a", Operator: index.OperatorEqual, Value: "x"}, true},
+		{"prefix", index.Filter{Field: "a", Operator: index.OperatorPrefix, Value: "x"}, true},
+		{"in", index.Filter{Field: "a", Operator: index.OperatorIn, Values: []string{"x"}}, true},
+		{"in without values", index.Filter{Field: "a", Operator: index.OperatorIn}, false},
+		{"nin without values", index.Filter{Field: "a", Operator: index.OperatorNotIn}, false},
+		{"unknown operator", index.Filter{Field: "a", Operator: "like", Value: "x"}, false},
+		{"empty", index.Filter{}, false},
+		{"and", index.Filter{And: []index.Filter{{Field: "a", Operator: index.OperatorEqual, Value: "x"}}}, true},
+		{"and with invalid operand", index.Filter{And: []index.Filter{{Field: "a", Operator: "like"}}}, false},
+		{"or with invalid operand", index.Filter{Or: []index.Filter{{}}}, false},
+		{"and with or", index.Filter{
+			And: []index.Filter{{Field: "a", Operator: index.OperatorEqual, Value: "x"}},
+			Or:  []index.Filter{{Field: "b", Operator: index.OperatorEqual, Value: "y"}},
+		}, false},
+		{"comparison with and", index.Filter{
+			Field: "a", Operator: index.OperatorEqual, Value: "x",
+			And: []index.Filter{{Field: "b", Operator: index.OperatorEqual, Value: "y"}},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	metadata := map[string]string{
+		"author":  "Alice",
+		"year":    "2021",
+		"version": "10",
+		"date":    "2024-03-01",
+	}
+
+	tests := []struct {
+		name   string
+		filter index.Filter
+		match  bool
+	}{
+		{"eq", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: "alice"}, true},
+		{"eq mismatch", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: "bob"}, false},
+		{"eq missing", index.Filter{Field: "missing", Operator: index.OperatorEqual, Value: "x"}, false},
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "bob"}, true},
+		{"ne mismatch", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "ALICE"}, false},
+		{"ne missing", index.Filter{Field: "missing", Operator: index.OperatorNotEqual, Value: "x"}, true},
+
+		{"gt number", index.Filter{Field: "version", Operator: index.OperatorGreater, Value: "9"}, true},
+		{"gt equal", index.Filter{Field: "year", Operator: index.OperatorGreater, Value: "2021"}, false},
+		{"gte", index.Filter{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2021"}, true},
+		{"lt number", index.Filter{Field: "version", Operator: index.OperatorLess, Value: "9"}, false},
+		{"lte", index.Filter{Field: "year", Operator: index.OperatorLessEqual, Value: "2021"}, true},
+		{"gt date", index.Filter{Field: "date", Operator: index.OperatorGreater, Value: "2024-01-15"}, true},
+		{"lt date", index.Filter{Field: "date", Operator: index.OperatorLess, Value: "2024-01-15"}, false},
+		{"range missing", index.Filter{Field: "missing", Operator: index.OperatorLess, Value: "1"}, false},
+
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"bob", "ALICE"}}, true},
+		{"in mismatch", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"bob"}}, false},
+		{"nin", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"bob"}}, true},
+		{"nin mismatch", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"alice"}}, false},
+		{"nin missing", index.Filter{Field: "missing", Operator: index.OperatorNotIn, Values: []string{"x"}}, true},
+
+		{"prefix", index.Filter{Field: "author", Operator: index.OperatorPrefix, Value: "AL"}, true},
+		{"prefix mismatch", index.Filter{Field: "author", Operator: index.OperatorPrefix, Value: "bo"}, false},
+
+		{"and", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2020"},
+		}}, true},
+		{"and mismatch", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "year", Operator: index.OperatorLess, Value: "2020"},
+		}}, false},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+			{Field: "year", Operator: index.OperatorEqual, Value: "2021"},
+		}}, true},
+		{"or mismatch", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+			{Field: "year", Operator: index.OperatorEqual, Value: "2022"},
+		}}, false},
+		{"nested", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorPrefix, Value: "a"},
+			{Or: []index.Filter{
+				{Field: "missing", Operator: index.OperatorEqual, Value: "x"},
+				{Field: "version", Operator: index.OperatorGreater, Value: "2"},
+			}},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.match, tt.filter.Match(metadata))
+		})
+	}
+}
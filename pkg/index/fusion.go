@@ -0,0 +1,53 @@
+package index
+
+import (
+	"sort"
+)
+
+// rrfK dampens the influence of top ranks in reciprocal rank fusion
+const rrfK = 60
+
+// Fuse merges ranked vector and keyword results by weighted reciprocal rank fusion,
+// weight is the share of the vector ranking and scores are scaled so a top hit in both lists scores 1
+func Fuse(vector, keyword []Result, weight float32) []Result {
+	weight = min(max(weight, 0), 1)
+
+	scores := make(map[string]float32)
+	documents := make(map[string]Document)
+
+	add := func(results []Result, weight float32) {
+		if weight == 0 {
+			return
+		}
+
+		for i, r := range results {
+			if _, ok := documents[r.ID]; !ok {
+				documents[r.ID] = r.Document
+			}
+
+			scores[r.ID] += weight * (rrfK + 1) / float32(rrfK+i+1)
+		}
+	}
+
+	add(vector, weight)
+	add(keyword, 1-weight)
+
+	results := make([]Result, 0, len(scores))
+
+	for id, score := range scores {
+		results = append(results, Result{
+			Document: documents[id],
+			Score:    score,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].ID < results[j].ID
+		}
+
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
@@ -0,0 +1,94 @@
+package index_test
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func results(ids ...string) []index.Result {
+	var result []index.Result
+
+	for _, id := range ids {
+		result = append(result, index.Result{
+			Document: index.Document{ID: id},
+		})
+	}
+
+	return result
+}
+
+func resultIDs(results []index.Result) []string {
+	var ids []string
+
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+
+	return ids
+}
+
+func TestFuseMerge(t *testing.T) {
+	vector := []index.Result{
+		{Document: index.Document{ID: "a", Content: "vector"}},
+		{Document: index.Document{ID: "b"}},
+		{Document: index.Document{ID: "c"}},
+	}
+
+	keyword := []index.Result{
+		{Document: index.Document{ID: "c"}},
+		{Document: index.Document{ID: "a", Content: "keyword"}},
+	}
+
+	result := index.Fuse(vector, keyword, 0.5)
+
+	require.Equal(t, []string{"a", "c", "b"}, resultIDs(result))
+
+	require.InDelta(t, 0.5+0.5*61.0/62, result[0].Score, 1e-6)
+	require.InDelta(t, 0.5*61.0/63+0.5, result[1].Score, 1e-6)
+	require.InDelta(t, 0.5*61.0/62, result[2].Score, 1e-6)
+
+	// documents are taken from the first ranking they appear in
+	require.Equal(t, "vector", result[0].Content)
+}
+
+func TestFuseRankConstant(t *testing.T) {
+	result := index.Fuse(results("a", "b", "c", "d"), nil, 1)
+
+	// ranks are dampened by k = 60 and scaled by k + 1, so the top hit scores 1
+	require.InDelta(t, 1, result[0].Score, 1e-6)
+	require.InDelta(t, 61.0/62, result[1].Score, 1e-6)
+	require.InDelta(t, 61.0/63, result[2].Score, 1e-6)
+	require.InDelta(t, 61.0/64, result[3].Score, 1e-6)
+
+	both := index.Fuse(results("a"), results("a"), 0.3)
+	require.InDelta(t, 1, both[0].Score, 1e-6)
+}
+
+func TestFuseTies(t *testing.T) {
+	// equal scores are ordered by id, independent of the input order
+	require.Equal(t, []string{"a", "b"}, resultIDs(index.Fuse(results("a"), results("b"), 0.5)))
+	require.Equal(t, []string{"a", "b"}, resultIDs(index.Fuse(results("b"), results("a"), 0.5)))
+
+	require.Equal(t, []string{"x", "a", "b"}, resultIDs(index.Fuse(results("x", "b"), results("x", "a"), 0.5)))
+}
+
+func TestFuseWeight(t *testing.T) {
+	vector := results("a", "b")
+	keyword := results("c", "a")
+
+	require.Equal(t, []string{"a", "b"}, resultIDs(index.Fuse(vector, keyword, 1)))
+	require.Equal(t, []string{"c", "a"}, resultIDs(index.Fuse(vector, keyword, 0)))
+
+	// weights are clamped to the unit interval
+	require.Equal(t, []string{"a", "b"}, resultIDs(index.Fuse(vector, keyword, 2)))
+	require.Equal(t, []string{"c", "a"}, resultIDs(index.Fuse(vector, keyword, -1)))
+
+	// the hit found by both rankings leads, the weight orders the rest
+	require.Equal(t, []string{"a", "b", "c"}, resultIDs(index.Fuse(vector, keyword, 0.8)))
+	require.Equal(t, []string{"a", "c", "b"}, resultIDs(index.Fuse(vector, keyword, 0.2)))
+
+	require.Empty(t, index.Fuse(nil, nil, 0.5))
+}
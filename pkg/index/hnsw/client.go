@@ -0,0 +1,249 @@
+package hnsw
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/adrianliechti/llama/pkg/index"
+	"github.com/adrianliechti/llama/pkg/index/bm25"
+
+	"github.com/google/uuid"
+)
+
+var _ index.Provider = &Provider{}
+
+type Provider struct {
+	embedder index.Embedder
+
+	m              int
+	efConstruction int
+	efSearch       int
+
+	hybrid *float32
+
+	mu    sync.RWMutex
+	graph *graph
+
+	documents map[string]int
+	keywords  *bm25.Index
+}
+
+func New(options ...Option) (*Provider, error) {
+	p := &Provider{
+		m:              16,
+		efConstruction: 200,
+		efSearch:       64,
+
+		documents: make(map[string]int),
+		keywords:  bm25.New(),
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	if p.embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+
+	if p.m < 2 {
+		return nil, errors.New("m must be at least 2")
+	}
+
+	if p.efConstruction < 1 || p.efSearch < 1 {
+		return nil, errors.New("ef must be at least 1")
+	}
+
+	p.graph = newGraph(p.m, p.efConstruction)
+
+	return p, nil
+}
+
+func (p *Provider) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make([]index.Document, 0, len(p.documents))
+
+	for _, id := range p.documents {
+		result = append(result, p.graph.nodes[id].document)
+	}
+
+	return result, nil
+}
+
+func (p *Provider) Index(ctx context.Context, documents ...index.Document) error {
+	documents, err := index.EmbedDocuments(ctx, p.embedder, documents)
+
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range documents {
+		if d.ID == "" {
+			d.ID = uuid.NewString()
+		}
+
+		if len(d.Embedding) == 0 {
+			continue
+		}
+
+		if id, ok := p.documents[d.ID]; ok {
+			p.graph.delete(id)
+		}
+
+		p.documents[d.ID] = p.graph.insert(d, normalize(d.Embedding))
+		p.keywords.Add(d.ID, d.Title+"\n"+d.Content)
+	}
+
+	p.compact()
+
+	return nil
+}
+
+func (p *Provider) Delete(ctx context.Context, ids ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, id := range ids {
+		if n, ok := p.documents[id]; ok {
+			p.graph.delete(n)
+			delete(p.documents, id)
+
+			p.keywords.Remove(id)
+		}
+	}
+
+	p.compact()
+
+	return nil
+}
+
+func (p *Provider) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if options == nil {
+		options = &index.QueryOptions{}
+	}
+
+	hybrid := p.hybrid
+
+	if options.Hybrid != nil {
+		hybrid = options.Hybrid
+	}
+
+	var embedding []float32
+
+	if hybrid == nil || *hybrid > 0 {
+		result, err := p.embedder.Embed(ctx, []string{query}, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result.Embeddings) == 0 {
+			return nil, errors.New("no embedding returned")
+		}
+
+		embedding = result.Embeddings[0]
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	limit := len(p.documents)
+
+	if options.Limit != nil {
+		limit = min(*options.Limit, limit)
+	}
+
+	matches := func(d index.Document) bool {
+		return options.Filter == nil || options.Filter.Match(d.Metadata)
+	}
+
+	// fusion needs deeper candidate lists than the final limit
+	candidates := limit
+
+	if hybrid != nil {
+		candidates = min(max(limit, p.efSearch), len(p.documents))
+	}
+
+	results := make([]index.Result, 0)
+
+	if embedding != nil {
+		accept := func(n *node) bool {
+			return matches(n.document)
+		}
+
+		for _, c := range p.graph.search(normalize(embedding), candidates, p.efSearch, accept) {
+			results = append(results, index.Result{
+				Score:    1 - c.distance,
+				Document: p.graph.nodes[c.node].document,
+			})
+		}
+	}
+
+	if hybrid != nil {
+		accept := func(id string) bool {
+			return matches(p.graph.nodes[p.documents[id]].document)
+		}
+
+		var keywords []index.Result
+
+		for _, r := range p.keywords.Search(query, candidates, accept) {
+			keywords = append(keywords, index.Result{
+				Score:    r.Score,
+				Document: p.graph.nodes[p.documents[r.ID]].document,
+			})
+		}
+
+		results = index.Fuse(results, keywords, *hybrid)
+
+		if len(results) > limit {
+			results = results[:limit]
+		}
+	}
+
+	return results, nil
+}
+
+// compact rebuilds the graph once more than half of its nodes are deleted
+func (p *Provider) compact() {
+	if p.graph.deleted*2 <= len(p.graph.nodes) {
+		return
+	}
+
+	g := newGraph(p.m, p.efConstruction)
+
+	for id, n := range p.documents {
+		node := p.graph.nodes[n]
+		p.documents[id] = g.insert(node.document, node.vector)
+	}
+
+	p.graph = g
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+
+	result := make([]float32, len(v))
+
+	if sum == 0 {
+		return result
+	}
+
+	norm := float32(math.Sqrt(sum))
+
+	for i, x := range v {
+		result[i] = x / norm
+	}
+
+	return result
+}
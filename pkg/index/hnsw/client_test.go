@@ -0,0 +1,19 @@
+package hnsw_test
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index/hnsw"
+	"github.com/adrianliechti/llama/test"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHNSW(t *testing.T) {
+	context := test.NewContext()
+
+	c, err := hnsw.New(hnsw.WithEmbedder(context.Embedder))
+	require.NoError(t, err)
+
+	test.TestIndex(t, context, c)
+}
@@ -0,0 +1,41 @@
+package hnsw
+
+import (
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+type Option func(*Provider)
+
+func WithEmbedder(embedder index.Embedder) Option {
+	return func(p *Provider) {
+		p.embedder = embedder
+	}
+}
+
+// WithM sets the number of neighbors per node, higher values improve recall at the cost of memory
+func WithM(m int) Option {
+	return func(p *Provider) {
+		p.m = m
+	}
+}
+
+// WithEfConstruction sets the candidate list size while inserting, higher values build a better graph
+func WithEfConstruction(ef int) Option {
+	return func(p *Provider) {
+		p.efConstruction = ef
+	}
+}
+
+// WithEfSearch sets the candidate list size while querying, higher values improve recall
+func WithEfSearch(ef int) Option {
+	return func(p *Provider) {
+		p.efSearch = ef
+	}
+}
+
+// WithHybrid fuses keyword and vector results by default, weight ranges from 0 (keyword only) to 1 (vector only)
+func WithHybrid(weight float32) Option {
+	return func(p *Provider) {
+		p.hybrid = &weight
+	}
+}
@@ -0,0 +1,387 @@
+package hnsw
+
+import (
+	"container/heap"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+// https://arxiv.org/abs/1603.09320
+
+type node struct {
+	document index.Document
+
+	vector  []float32
+	deleted bool
+
+	// neighbors per layer
+	neighbors [][]int
+}
+
+type graph struct {
+	m              int
+	efConstruction int
+
+	levelMult float64
+
+	// random draws the level of inserted nodes
+	random func() float64
+
+	nodes []*node
+
+	entry    int
+	maxLevel int
+
+	deleted int
+}
+
+func newGraph(m, efConstruction int) *graph {
+	return &graph{
+		m:              m,
+		efConstruction: efConstruction,
+
+		levelMult: 1 / math.Log(float64(m)),
+
+		random: rand.Float64,
+
+		entry: -1,
+	}
+}
+
+func (g *graph) maxNeighbors(layer int) int {
+	if layer == 0 {
+		return 2 * g.m
+	}
+
+	return g.m
+}
+
+func (g *graph) distance(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 2
+	}
+
+	var d0, d1, d2, d3 float32
+
+	i := 0
+
+	for ; i+4 <= len(a); i += 4 {
+		d0 += a[i] * b[i]
+		d1 += a[i+1] * b[i+1]
+		d2 += a[i+2] * b[i+2]
+		d3 += a[i+3] * b[i+3]
+	}
+
+	for ; i < len(a); i++ {
+		d0 += a[i] * b[i]
+	}
+
+	return 1 - (d0 + d1 + d2 + d3)
+}
+
+func (g *graph) insert(d index.Document, vector []float32) int {
+	level := int(math.Floor(-math.Log(1-g.random()) * g.levelMult))
+
+	n := &node{
+		document: d,
+
+		vector:    vector,
+		neighbors: make([][]int, level+1),
+	}
+
+	id := len(g.nodes)
+	g.nodes = append(g.nodes, n)
+
+	if g.entry < 0 {
+		g.entry = id
+		g.maxLevel = level
+
+		return id
+	}
+
+	ep := g.entry
+
+	for l := g.maxLevel; l > level; l-- {
+		ep = g.searchLayer(vector, []int{ep}, 1, l)[0].node
+	}
+
+	entries := []int{ep}
+
+	for l := min(level, g.maxLevel); l >= 0; l-- {
+		candidates := g.searchLayer(vector, entries, g.efConstruction, l)
+
+		n.neighbors[l] = g.selectNeighbors(candidates, g.m)
+
+		for _, neighbor := range n.neighbors[l] {
+			g.link(neighbor, id, l)
+		}
+
+		entries = entries[:0]
+
+		for _, c := range candidates {
+			entries = append(entries, c.node)
+		}
+	}
+
+	if level > g.maxLevel {
+		g.entry = id
+		g.maxLevel = level
+	}
+
+	return id
+}
+
+func (g *graph) link(from, to, layer int) {
+	n := g.nodes[from]
+	n.neighbors[layer] = append(n.neighbors[layer], to)
+
+	if len(n.neighbors[layer]) <= g.maxNeighbors(layer) {
+		return
+	}
+
+	candidates := make([]candidate, 0, len(n.neighbors[layer]))
+
+	for _, neighbor := range n.neighbors[layer] {
+		candidates = append(candidates, candidate{
+			node:     neighbor,
+			distance: g.distance(n.vector, g.nodes[neighbor].vector),
+		})
+	}
+
+	slices.SortFunc(candidates, compareCandidates)
+
+	n.neighbors[layer] = g.selectNeighbors(candidates, g.maxNeighbors(layer))
+}
+
+// selectNeighbors applies the diversity heuristic to candidates sorted by distance
+func (g *graph) selectNeighbors(candidates []candidate, m int) []int {
+	result := make([]int, 0, m)
+	var skipped []int
+
+	for _, c := range candidates {
+		if len(result) >= m {
+			break
+		}
+
+		diverse := true
+
+		for _, r := range result {
+			if g.distance(g.nodes[c.node].vector, g.nodes[r].vector) < c.distance {
+				diverse = false
+				break
+			}
+		}
+
+		if diverse {
+			result = append(result, c.node)
+		} else {
+			skipped = append(skipped, c.node)
+		}
+	}
+
+	for _, s := range skipped {
+		if len(result) >= m {
+			break
+		}
+
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// searchLayer returns up to ef nodes closest to the vector, sorted by distance
+func (g *graph) searchLayer(vector []float32, entries []int, ef, layer int) []candidate {
+	visited := visitedPool.Get().(*visitedSet)
+	defer visitedPool.Put(visited)
+
+	visited.reset(len(g.nodes))
+
+	candidates := &candidateHeap{}
+	results := &candidateHeap{max: true}
+
+	for _, e := range entries {
+		visited.visit(e)
+
+		c := candidate{
+			node:     e,
+			distance: g.distance(vector, g.nodes[e].vector),
+		}
+
+		heap.Push(candidates, c)
+		heap.Push(results, c)
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(candidate)
+
+		if results.Len() >= ef && c.distance > results.items[0].distance {
+			break
+		}
+
+		for _, neighbor := range g.nodes[c.node].neighbors[layer] {
+			if !visited.visit(neighbor) {
+				continue
+			}
+
+			distance := g.distance(vector, g.nodes[neighbor].vector)
+
+			if results.Len() < ef || distance < results.items[0].distance {
+				n := candidate{
+					node:     neighbor,
+					distance: distance,
+				}
+
+				heap.Push(candidates, n)
+				heap.Push(results, n)
+
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	result := results.items
+	slices.SortFunc(result, compareCandidates)
+
+	return result
+}
+
+// search returns up to k live nodes accepted by the filter, widening ef until enough are found
+func (g *graph) search(vector []float32, k, ef int, accept func(*node) bool) []candidate {
+	if g.entry < 0 || k <= 0 {
+		return nil
+	}
+
+	ep := g.entry
+
+	for l := g.maxLevel; l > 0; l-- {
+		ep = g.searchLayer(vector, []int{ep}, 1, l)[0].node
+	}
+
+	ef = max(ef, k)
+
+	for {
+		var result []candidate
+
+		for _, c := range g.searchLayer(vector, []int{ep}, ef, 0) {
+			n := g.nodes[c.node]
+
+			if n.deleted || !accept(n) {
+				continue
+			}
+
+			result = append(result, c)
+
+			if len(result) >= k {
+				break
+			}
+		}
+
+		if len(result) >= k || ef >= len(g.nodes) {
+			return result
+		}
+
+		ef *= 2
+	}
+}
+
+func (g *graph) delete(id int) {
+	if g.nodes[id].deleted {
+		return
+	}
+
+	g.nodes[id].deleted = true
+	g.deleted++
+}
+
+var visitedPool = sync.Pool{
+	New: func() any {
+		return &visitedSet{}
+	},
+}
+
+// visitedSet marks nodes with a generation, so it can be reused without clearing
+type visitedSet struct {
+	marks      []uint32
+	generation uint32
+}
+
+func (v *visitedSet) reset(size int) {
+	if len(v.marks) < size {
+		v.marks = make([]uint32, size+size/2)
+		v.generation = 0
+	}
+
+	v.generation++
+
+	if v.generation == 0 {
+		clear(v.marks)
+		v.generation = 1
+	}
+}
+
+// visit marks the node and reports whether it was not visited before
+func (v *visitedSet) visit(node int) bool {
+	if v.marks[node] == v.generation {
+		return false
+	}
+
+	v.marks[node] = v.generation
+
+	return true
+}
+
+type candidate struct {
+	node     int
+	distance float32
+}
+
+func compareCandidates(a, b candidate) int {
+	switch {
+	case a.distance < b.distance:
+		return -1
+	case a.distance > b.distance:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type candidateHeap struct {
+	items []candidate
+	max   bool
+}
+
+func (h candidateHeap) Len() int {
+	return len(h.items)
+}
+
+func (h candidateHeap) Less(i, j int) bool {
+	if h.max {
+		return h.items[i].distance > h.items[j].distance
+	}
+
+	return h.items[i].distance < h.items[j].distance
+}
+
+func (h candidateHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *candidateHeap) Push(x any) {
+	h.items = append(h.items, x.(candidate))
+}
+
+func (h *candidateHeap) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+
+	return item
+}
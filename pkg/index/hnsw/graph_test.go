@@ -0,0 +1,175 @@
+package hnsw
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGraph(t *testing.T, count, dimensions int) (*graph, *rand.Rand) {
+	t.Helper()
+
+	r := rand.New(rand.NewPCG(1, 2))
+
+	g := newGraph(8, 64)
+	g.random = r.Float64
+
+	for i := range count {
+		group := "common"
+
+		if i%25 == 0 {
+			group = "rare"
+		}
+
+		g.insert(index.Document{
+			ID:       strconv.Itoa(i),
+			Metadata: map[string]string{"group": group},
+		}, randomVector(r, dimensions))
+	}
+
+	return g, r
+}
+
+func randomVector(r *rand.Rand, dimensions int) []float32 {
+	v := make([]float32, dimensions)
+
+	for i := range v {
+		v[i] = float32(r.NormFloat64())
+	}
+
+	return normalize(v)
+}
+
+// bruteForce returns the k closest live nodes accepted by the filter
+func bruteForce(g *graph, vector []float32, k int, accept func(*node) bool) []int {
+	var candidates []candidate
+
+	for i, n := range g.nodes {
+		if n.deleted || !accept(n) {
+			continue
+		}
+
+		candidates = append(candidates, candidate{
+			node:     i,
+			distance: g.distance(vector, n.vector),
+		})
+	}
+
+	slices.SortFunc(candidates, compareCandidates)
+
+	var result []int
+
+	for _, c := range candidates[:min(k, len(candidates))] {
+		result = append(result, c.node)
+	}
+
+	return result
+}
+
+func nodes(candidates []candidate) []int {
+	var result []int
+
+	for _, c := range candidates {
+		result = append(result, c.node)
+	}
+
+	return result
+}
+
+func acceptAll(*node) bool {
+	return true
+}
+
+func TestGraphSearchExact(t *testing.T) {
+	g := newGraph(4, 16)
+	g.random = rand.New(rand.NewPCG(1, 2)).Float64
+
+	vectors := [][]float32{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+		normalize([]float32{1, 1, 0, 0}),
+	}
+
+	for i, v := range vectors {
+		g.insert(index.Document{ID: strconv.Itoa(i)}, v)
+	}
+
+	result := g.search(normalize([]float32{0.9, 0.1, 0, 0}), 2, 16, acceptAll)
+
+	require.Equal(t, []int{0, 4}, nodes(result))
+	require.InDelta(t, 1-0.9/0.9055, result[0].distance, 0.001)
+}
+
+func TestGraphRecall(t *testing.T) {
+	g, r := newTestGraph(t, 1000, 16)
+
+	const k = 10
+	const queries = 50
+
+	var found int
+
+	for range queries {
+		vector := randomVector(r, 16)
+
+		expected := bruteForce(g, vector, k, acceptAll)
+		result := nodes(g.search(vector, k, 64, acceptAll))
+
+		require.Len(t, result, k)
+
+		for _, id := range result {
+			if slices.Contains(expected, id) {
+				found++
+			}
+		}
+	}
+
+	recall := float64(found) / float64(queries*k)
+	require.GreaterOrEqual(t, recall, 0.95)
+}
+
+func TestGraphSearchFilter(t *testing.T) {
+	g, r := newTestGraph(t, 1000, 16)
+
+	rare := func(n *node) bool {
+		return n.document.Metadata["group"] == "rare"
+	}
+
+	for range 10 {
+		vector := randomVector(r, 16)
+
+		// 40 of 1000 nodes match, so the initial ef of 10 has to widen to find 5 of them
+		result := g.search(vector, 5, 10, rare)
+
+		require.Equal(t, bruteForce(g, vector, 5, rare), nodes(result))
+	}
+
+	none := g.search(randomVector(r, 16), 5, 10, func(*node) bool { return false })
+	require.Empty(t, none)
+}
+
+func TestGraphDelete(t *testing.T) {
+	g, r := newTestGraph(t, 200, 16)
+
+	vector := randomVector(r, 16)
+
+	nearest := g.search(vector, 1, 32, acceptAll)[0].node
+
+	g.delete(nearest)
+	g.delete(nearest)
+
+	require.Equal(t, 1, g.deleted)
+
+	result := nodes(g.search(vector, 10, 32, acceptAll))
+
+	require.Len(t, result, 10)
+	require.NotContains(t, result, nearest)
+
+	require.Equal(t, bruteForce(g, vector, 10, acceptAll), result)
+}
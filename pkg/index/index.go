@@ -2,6 +2,8 @@ package index
 
 import (
 	"context"
+	"errors"
+	"slices"
 
 	"github.com/adrianliechti/llama/pkg/provider"
 )
@@ -21,7 +23,10 @@ type ListOptions struct {
 type QueryOptions struct {
 	Limit *int
 
-	Filters map[string]string
+	// Hybrid weights vector against keyword relevance, from 0 (keyword only) to 1 (vector only)
+	Hybrid *float32
+
+	Filter *Filter
 }
 
 type Document struct {
@@ -43,3 +48,44 @@ type Result struct {
 
 type Embedder = provider.Embedder
 type Reranker = provider.Reranker
+
+// EmbedDocuments returns the documents with missing embeddings filled in by one batch request
+func EmbedDocuments(ctx context.Context, embedder Embedder, documents []Document) ([]Document, error) {
+	if embedder == nil {
+		return documents, nil
+	}
+
+	var texts []string
+	var indexes []int
+
+	for i, d := range documents {
+		if len(d.Embedding) > 0 {
+			continue
+		}
+
+		texts = append(texts, d.Content)
+		indexes = append(indexes, i)
+	}
+
+	if len(texts) == 0 {
+		return documents, nil
+	}
+
+	embedding, err := embedder.Embed(ctx, texts, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embedding.Embeddings) != len(texts) {
+		return nil, errors.New("invalid embeddings count")
+	}
+
+	result := slices.Clone(documents)
+
+	for i, index := range indexes {
+		result[index].Embedding = embedding.Embeddings[i]
+	}
+
+	return result, nil
+}
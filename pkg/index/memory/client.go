@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"math"
+	"os"
 	"sort"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/adrianliechti/llama/pkg/index"
+	"github.com/adrianliechti/llama/pkg/index/bm25"
 
 	"github.com/google/uuid"
 )
@@ -16,14 +19,28 @@ var _ index.Provider = &Provider{}
 
 type Provider struct {
 	embedder index.Embedder
-	reranker index.Reranker
 
+	hybrid *float32
+
+	mu        sync.RWMutex
 	documents map[string]index.Document
+	keywords  *bm25.Index
+
+	path     string
+	interval time.Duration
+
+	log   *os.File
+	dirty bool
+
+	done chan struct{}
 }
 
 func New(options ...Option) (*Provider, error) {
 	p := &Provider{
 		documents: make(map[string]index.Document),
+		keywords:  bm25.New(),
+
+		interval: 5 * time.Minute,
 	}
 
 	for _, option := range options {
@@ -34,10 +51,24 @@ func New(options ...Option) (*Provider, error) {
 		return nil, errors.New("embedder is required")
 	}
 
+	if p.path != "" {
+		if err := p.open(); err != nil {
+			return nil, err
+		}
+
+		if p.interval > 0 {
+			p.done = make(chan struct{})
+			go p.snapshotLoop(p.done)
+		}
+	}
+
 	return p, nil
 }
 
 func (p *Provider) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	result := make([]index.Document, 0, len(p.documents))
 
 	for _, d := range p.documents {
@@ -48,36 +79,56 @@ func (p *Provider) List(ctx context.Context, options *index.ListOptions) ([]inde
 }
 
 func (p *Provider) Index(ctx context.Context, documents ...index.Document) error {
-	for _, d := range documents {
-		if d.ID == "" {
-			d.ID = uuid.NewString()
-		}
+	documents, err := index.EmbedDocuments(ctx, p.embedder, documents)
 
-		if len(d.Embedding) == 0 && p.embedder != nil {
-			embedding, err := p.embedder.Embed(ctx, d.Content)
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				return err
-			}
+	var result []index.Document
 
-			d.Embedding = embedding.Data
+	for _, d := range documents {
+		if d.ID == "" {
+			d.ID = uuid.NewString()
 		}
 
 		if len(d.Embedding) == 0 {
 			continue
 		}
 
-		p.documents[d.ID] = d
+		result = append(result, d)
+	}
+
+	if len(result) == 0 {
+		return nil
 	}
 
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry := logEntry{Documents: result}
+
+	if err := p.append(entry); err != nil {
+		return err
+	}
+
+	p.apply(entry)
+
 	return nil
 }
 
 func (p *Provider) Delete(ctx context.Context, ids ...string) error {
-	for _, id := range ids {
-		delete(p.documents, id)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry := logEntry{Deletes: ids}
+
+	if err := p.append(entry); err != nil {
+		return err
 	}
 
+	p.apply(entry)
+
 	return nil
 }
 
@@ -90,41 +141,66 @@ func (p *Provider) Query(ctx context.Context, query string, options *index.Query
 		return nil, errors.New("no embedder configured")
 	}
 
-	embedding, err := p.embedder.Embed(ctx, query)
+	hybrid := p.hybrid
 
-	if err != nil {
-		return nil, err
+	if options.Hybrid != nil {
+		hybrid = options.Hybrid
 	}
 
-	results := make([]index.Result, 0)
+	var embedding []float32
 
-DOCUMENTS:
-	for _, d := range p.documents {
-		score := cosineSimilarity(embedding.Data, d.Embedding)
+	if hybrid == nil || *hybrid > 0 {
+		result, err := p.embedder.Embed(ctx, []string{query}, nil)
+
+		if err != nil {
+			return nil, err
+		}
 
-		r := index.Result{
-			Score:    score,
-			Document: d,
+		if len(result.Embeddings) == 0 {
+			return nil, errors.New("no embedding returned")
 		}
 
-		for k, v := range options.Filters {
-			val, ok := d.Metadata[k]
+		embedding = result.Embeddings[0]
+	}
 
-			if !ok {
-				continue DOCUMENTS
-			}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	accept := func(d index.Document) bool {
+		return options.Filter == nil || options.Filter.Match(d.Metadata)
+	}
+
+	results := make([]index.Result, 0)
 
-			if !strings.EqualFold(v, val) {
-				continue DOCUMENTS
+	if embedding != nil {
+		for _, d := range p.documents {
+			if !accept(d) {
+				continue
 			}
+
+			results = append(results, index.Result{
+				Score:    cosineSimilarity(embedding, d.Embedding),
+				Document: d,
+			})
 		}
 
-		results = append(results, r)
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
 	}
 
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
+	if hybrid != nil {
+		var keywords []index.Result
+
+		for _, r := range p.keywords.Search(query, -1, func(id string) bool { return accept(p.documents[id]) }) {
+			keywords = append(keywords, index.Result{
+				Score:    r.Score,
+				Document: p.documents[r.ID],
+			})
+		}
+
+		results = index.Fuse(results, keywords, *hybrid)
+	}
 
 	if options.Limit != nil {
 		limit := *options.Limit
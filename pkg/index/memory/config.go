@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"time"
+
 	"github.com/adrianliechti/llama/pkg/index"
 )
 
@@ -12,8 +14,22 @@ func WithEmbedder(embedder index.Embedder) Option {
 	}
 }
 
-func WithReranker(reranker index.Reranker) Option {
+// WithHybrid fuses keyword and vector results by default, weight ranges from 0 (keyword only) to 1 (vector only)
+func WithHybrid(weight float32) Option {
+	return func(p *Provider) {
+		p.hybrid = &weight
+	}
+}
+
+// WithPath persists the index as snapshot and append-only log in the given directory
+func WithPath(path string) Option {
+	return func(p *Provider) {
+		p.path = path
+	}
+}
+
+func WithSnapshotInterval(interval time.Duration) Option {
 	return func(p *Provider) {
-		p.reranker = reranker
+		p.interval = interval
 	}
 }
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+type logEntry struct {
+	Documents []index.Document `json:"documents,omitempty"`
+	Deletes   []string         `json:"deletes,omitempty"`
+}
+
+func (p *Provider) snapshotPath() string {
+	return filepath.Join(p.path, "snapshot.json")
+}
+
+func (p *Provider) logPath() string {
+	return filepath.Join(p.path, "log.jsonl")
+}
+
+func (p *Provider) open() error {
+	if err := os.MkdirAll(p.path, 0755); err != nil {
+		return err
+	}
+
+	if data, err := os.ReadFile(p.snapshotPath()); err == nil {
+		var documents []index.Document
+
+		if err := json.Unmarshal(data, &documents); err != nil {
+			return err
+		}
+
+		p.apply(logEntry{Documents: documents})
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := p.replay(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	p.log = f
+
+	// compact on startup, which also drops a torn entry left by a crash
+	if p.dirty {
+		return p.snapshot()
+	}
+
+	return nil
+}
+
+func (p *Provider) replay() error {
+	f, err := os.Open(p.logPath())
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+
+	for {
+		data, err := reader.ReadBytes('\n')
+
+		if len(data) > 0 {
+			p.dirty = true
+
+			var entry logEntry
+
+			if json.Unmarshal(data, &entry) != nil {
+				return nil
+			}
+
+			p.apply(entry)
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+func (p *Provider) apply(entry logEntry) {
+	for _, d := range entry.Documents {
+		p.documents[d.ID] = d
+		p.keywords.Add(d.ID, d.Title+"\n"+d.Content)
+	}
+
+	for _, id := range entry.Deletes {
+		delete(p.documents, id)
+		p.keywords.Remove(id)
+	}
+}
+
+// append writes the entry to the log, the caller must hold the write lock
+func (p *Provider) append(entry logEntry) error {
+	if p.log == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.log.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	p.dirty = true
+
+	return p.log.Sync()
+}
+
+// snapshot writes all documents and truncates the log, the caller must hold the write lock
+func (p *Provider) snapshot() error {
+	documents := make([]index.Document, 0, len(p.documents))
+
+	for _, d := range p.documents {
+		documents = append(documents, d)
+	}
+
+	data, err := json.Marshal(documents)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(p.path, ".tmp-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(f.Name(), p.snapshotPath()); err != nil {
+		return err
+	}
+
+	if err := p.log.Truncate(0); err != nil {
+		return err
+	}
+
+	p.dirty = false
+
+	return nil
+}
+
+func (p *Provider) snapshotLoop(done <-chan struct{}) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+
+		if p.dirty && p.log != nil {
+			if err := p.snapshot(); err != nil {
+				slog.Error("failed to snapshot memory index", "path", p.path, "error", err)
+			}
+		}
+
+		p.mu.Unlock()
+	}
+}
+
+// Close stops the periodic snapshots, writes a final one and closes the log, the index must not be used afterwards
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done != nil {
+		close(p.done)
+		p.done = nil
+	}
+
+	if p.log == nil {
+		return nil
+	}
+
+	var err error
+
+	if p.dirty {
+		err = p.snapshot()
+	}
+
+	err = errors.Join(err, p.log.Close())
+	p.log = nil
+
+	return err
+}
@@ -0,0 +1,166 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/adrianliechti/llama/pkg/index"
+	"github.com/adrianliechti/llama/pkg/index/memory"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// letterEmbedder embeds texts as their letter frequencies
+type letterEmbedder struct{}
+
+func (letterEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for _, text := range texts {
+		embedding := make([]float32, 26)
+
+		for _, r := range text {
+			if r = unicode.ToLower(r); r >= 'a' && r <= 'z' {
+				embedding[r-'a']++
+			}
+		}
+
+		result.Embeddings = append(result.Embeddings, embedding)
+	}
+
+	return result, nil
+}
+
+func documentIDs(t *testing.T, p *memory.Provider) []string {
+	documents, err := p.List(context.Background(), nil)
+	require.NoError(t, err)
+
+	var ids []string
+
+	for _, d := range documents {
+		ids = append(ids, d.ID)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func TestReplayOverSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	p, err := memory.New(memory.WithEmbedder(letterEmbedder{}), memory.WithPath(path), memory.WithSnapshotInterval(0))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Index(ctx,
+		index.Document{ID: "a", Content: "apple"},
+		index.Document{ID: "b", Content: "banana"},
+		index.Document{ID: "c", Content: "cherry"},
+	))
+
+	require.NoError(t, p.Close())
+
+	log, err := os.ReadFile(filepath.Join(path, "log.jsonl"))
+	require.NoError(t, err)
+	require.Empty(t, log)
+
+	// changes after the snapshot only reach the log, as if the process crashed before the next snapshot
+	p, err = memory.New(memory.WithEmbedder(letterEmbedder{}), memory.WithPath(path), memory.WithSnapshotInterval(0))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Index(ctx,
+		index.Document{ID: "b", Content: "blueberry"},
+		index.Document{ID: "d", Content: "date"},
+	))
+
+	require.NoError(t, p.Delete(ctx, "a"))
+
+	f, err := os.OpenFile(filepath.Join(path, "log.jsonl"), os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+
+	_, err = f.WriteString(`{"deletes":["c"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	p, err = memory.New(memory.WithEmbedder(letterEmbedder{}), memory.WithPath(path), memory.WithSnapshotInterval(0))
+	require.NoError(t, err)
+
+	defer p.Close()
+
+	require.Equal(t, []string{"b", "c", "d"}, documentIDs(t, p))
+
+	results, err := p.Query(ctx, "blueberry", nil)
+	require.NoError(t, err)
+	require.Equal(t, "b", results[0].ID)
+	require.Equal(t, "blueberry", results[0].Content)
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	p, err := memory.New(memory.WithEmbedder(letterEmbedder{}), memory.WithPath(path), memory.WithSnapshotInterval(time.Millisecond), memory.WithHybrid(0.5))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for w := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range 50 {
+				id := fmt.Sprintf("%d-%d", w, i)
+
+				if err := p.Index(ctx, index.Document{ID: id, Content: "document " + id}); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if _, err := p.Query(ctx, "document", nil); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if i%2 == 1 {
+					if err := p.Delete(ctx, id); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var expected []string
+
+	for w := range 8 {
+		for i := 0; i < 50; i += 2 {
+			expected = append(expected, fmt.Sprintf("%d-%d", w, i))
+		}
+	}
+
+	sort.Strings(expected)
+
+	require.Equal(t, expected, documentIDs(t, p))
+	require.NoError(t, p.Close())
+
+	p, err = memory.New(memory.WithEmbedder(letterEmbedder{}), memory.WithPath(path), memory.WithSnapshotInterval(0))
+	require.NoError(t, err)
+
+	defer p.Close()
+
+	require.Equal(t, expected, documentIDs(t, p))
+}
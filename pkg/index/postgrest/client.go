@@ -20,7 +20,6 @@ type Client struct {
 	namespace string
 
 	embedder index.Embedder
-	reranker index.Reranker
 }
 
 func New(url string, namespace string, options ...Option) (*Client, error) {
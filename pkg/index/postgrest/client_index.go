@@ -9,19 +9,15 @@ import (
 )
 
 func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
-	body := []Document{}
-
-	for _, d := range documents {
-		if len(d.Embedding) == 0 && c.embedder != nil {
-			embedding, err := c.embedder.Embed(ctx, d.Content)
+	documents, err := index.EmbedDocuments(ctx, c.embedder, documents)
 
-			if err != nil {
-				return err
-			}
+	if err != nil {
+		return err
+	}
 
-			d.Embedding = embedding.Data
-		}
+	body := []Document{}
 
+	for _, d := range documents {
 		item := Document{
 			ID: d.ID,
 
@@ -29,6 +25,8 @@ func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
 			Content:  d.Content,
 			Location: d.Location,
 
+			Metadata: d.Metadata,
+
 			Embedding: d.Embedding,
 		}
 
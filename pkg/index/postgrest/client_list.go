@@ -41,7 +41,8 @@ func (c *Client) List(ctx context.Context, options *index.ListOptions) ([]index.
 			Title:    doc.Title,
 			Location: doc.Location,
 
-			Content: doc.Content,
+			Content:  doc.Content,
+			Metadata: doc.Metadata,
 		})
 	}
 
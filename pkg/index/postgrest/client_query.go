@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/adrianliechti/llama/pkg/index"
 )
@@ -20,20 +21,37 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		limit = *options.Limit
 	}
 
-	embedding, err := c.embedder.Embed(ctx, query)
+	embedding, err := c.embedder.Embed(ctx, []string{query}, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	body := map[string]any{
-		"query_embedding": embedding.Data,
+		"query_embedding": embedding.Embeddings[0],
 		"limit_count":     limit,
 	}
 
-	url, _ := url.JoinPath(c.url, "/rpc/find_similar_docs")
+	u, _ := url.JoinPath(c.url, "/rpc/find_similar_docs")
 
-	req, _ := http.NewRequestWithContext(ctx, "POST", url, jsonReader(body))
+	if options.Filter != nil {
+		filter, err := convertFilter(*options.Filter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the filter applies to the rows the function returns, so it fetches more candidates than needed
+		body["limit_count"] = limit * 4
+
+		query := url.Values{}
+		query.Set("and", "("+filter+")")
+		query.Set("limit", strconv.Itoa(limit))
+
+		u += "?" + query.Encode()
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", u, jsonReader(body))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.client.Do(req)
@@ -64,7 +82,8 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 				Title:    doc.Title,
 				Location: doc.Location,
 
-				Content: doc.Content,
+				Content:  doc.Content,
+				Metadata: doc.Metadata,
 			},
 		})
 	}
@@ -0,0 +1,76 @@
+package postgrest
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+var operators = map[index.Operator]string{
+	index.OperatorEqual:        "eq",
+	index.OperatorNotEqual:     "neq",
+	index.OperatorGreater:      "gt",
+	index.OperatorGreaterEqual: "gte",
+	index.OperatorLess:         "lt",
+	index.OperatorLessEqual:    "lte",
+	index.OperatorIn:           "in",
+	index.OperatorNotIn:        "not.in",
+	index.OperatorPrefix:       "like",
+}
+
+// convertFilter translates a filter into a logic tree of the and query parameter on the metadata column
+func convertFilter(f index.Filter) (string, error) {
+	if len(f.And) > 0 || len(f.Or) > 0 {
+		operator, filters := "and", f.And
+
+		if len(f.Or) > 0 {
+			operator, filters = "or", f.Or
+		}
+
+		var conditions []string
+
+		for _, c := range filters {
+			condition, err := convertFilter(c)
+
+			if err != nil {
+				return "", err
+			}
+
+			conditions = append(conditions, condition)
+		}
+
+		return operator + "(" + strings.Join(conditions, ",") + ")", nil
+	}
+
+	operator, ok := operators[f.Operator]
+
+	if !ok {
+		return "", errors.New("unsupported filter operator: " + string(f.Operator))
+	}
+
+	value := quote(f.Value)
+
+	switch f.Operator {
+	case index.OperatorIn, index.OperatorNotIn:
+		var values []string
+
+		for _, v := range f.Values {
+			values = append(values, quote(v))
+		}
+
+		value = "(" + strings.Join(values, ",") + ")"
+
+	case index.OperatorPrefix:
+		// like treats * as wildcard, literal wildcards in the prefix are escaped
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", `\*`).Replace(f.Value)
+		value = quote(escaped + "*")
+	}
+
+	return "metadata->>" + f.Field + "." + operator + "." + value, nil
+}
+
+// quote wraps a value in double quotes, so reserved characters like commas and parentheses are taken literally
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,51 @@
+package postgrest
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   index.Filter
+		expected string
+	}{
+		{"eq", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: "alice"}, `metadata->>author.eq."alice"`},
+		{"eq reserved", index.Filter{Field: "title", Operator: index.OperatorEqual, Value: `a,b "c"`}, `metadata->>title.eq."a,b \"c\""`},
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "alice"}, `metadata->>author.neq."alice"`},
+		{"gt", index.Filter{Field: "year", Operator: index.OperatorGreater, Value: "2020"}, `metadata->>year.gt."2020"`},
+		{"gte", index.Filter{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2020"}, `metadata->>year.gte."2020"`},
+		{"lt", index.Filter{Field: "year", Operator: index.OperatorLess, Value: "2020"}, `metadata->>year.lt."2020"`},
+		{"lte", index.Filter{Field: "year", Operator: index.OperatorLessEqual, Value: "2020"}, `metadata->>year.lte."2020"`},
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"alice", "bob"}}, `metadata->>author.in.("alice","bob")`},
+		{"nin", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"alice"}}, `metadata->>author.not.in.("alice")`},
+		{"prefix", index.Filter{Field: "name", Operator: index.OperatorPrefix, Value: "50%_*"}, `metadata->>name.like."50\\%\\_\\**"`},
+		{"and", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+		}}, `and(metadata->>author.eq."alice",metadata->>year.gt."2020")`},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{And: []index.Filter{
+				{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+			}},
+		}}, `or(metadata->>author.eq."alice",and(metadata->>author.eq."bob"))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertFilter(tt.filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestConvertFilterUnsupported(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "author", Operator: "like", Value: "al"})
+	require.Error(t, err)
+}
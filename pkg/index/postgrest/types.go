@@ -14,6 +14,8 @@ type Document struct {
 
 	Content string `json:"content"`
 
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	Embedding []float32 `json:"embedding"`
 }
 
@@ -26,6 +28,8 @@ func (d *Document) UnmarshalJSON(data []byte) error {
 
 		Content string `json:"content"`
 
+		Metadata map[string]string `json:"metadata"`
+
 		Embedding string `json:"embedding"`
 	}
 
@@ -38,6 +42,7 @@ func (d *Document) UnmarshalJSON(data []byte) error {
 	d.Location = alias.Location
 
 	d.Content = alias.Content
+	d.Metadata = alias.Metadata
 
 	slices := strings.Split(strings.Trim(alias.Embedding, "[]"), ",")
 
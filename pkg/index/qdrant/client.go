@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"net/url"
 
 	"github.com/adrianliechti/llama/pkg/index"
+	"github.com/adrianliechti/llama/pkg/index/bm25"
 	"github.com/adrianliechti/llama/pkg/to"
 
 	"github.com/google/uuid"
@@ -17,6 +19,8 @@ import (
 
 var _ index.Provider = &Client{}
 
+const keywordVector = "bm25"
+
 type Client struct {
 	client *http.Client
 
@@ -25,7 +29,8 @@ type Client struct {
 	namespace string
 
 	embedder index.Embedder
-	reranker index.Reranker
+
+	hybrid *float32
 }
 
 func New(url string, namespace string, options ...Option) (*Client, error) {
@@ -53,7 +58,7 @@ func New(url string, namespace string, options ...Option) (*Client, error) {
 }
 
 func (c *Client) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
-	if err := c.ensureCollection(c.namespace); err != nil {
+	if _, err := c.ensureCollection(c.namespace); err != nil {
 		return nil, err
 	}
 
@@ -119,36 +124,43 @@ func (c *Client) List(ctx context.Context, options *index.ListOptions) ([]index.
 }
 
 func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
+	documents, err := index.EmbedDocuments(ctx, c.embedder, documents)
+
+	if err != nil {
+		return err
+	}
+
 	if len(documents) == 0 {
 		return nil
 	}
 
-	if err := c.ensureCollection(c.namespace); err != nil {
+	sparse, err := c.ensureCollection(c.namespace)
+
+	if err != nil {
 		return err
 	}
 
 	u, _ := url.JoinPath(c.url, "/collections/"+c.namespace+"/points")
 
-	var points []point
+	var points []upsertPoint
 
 	for _, d := range documents {
 		if d.ID == "" {
 			d.ID = uuid.NewString()
 		}
 
-		if len(d.Embedding) == 0 && c.embedder != nil {
-			embedding, err := c.embedder.Embed(ctx, d.Content)
+		var vector any = d.Embedding
 
-			if err != nil {
-				return err
+		if sparse {
+			vector = map[string]any{
+				"":            d.Embedding,
+				keywordVector: convertKeywords(d.Title + "\n" + d.Content),
 			}
-
-			d.Embedding = embedding.Data
 		}
 
-		points = append(points, point{
+		points = append(points, upsertPoint{
 			ID:     convertID(d.ID),
-			Vector: d.Embedding,
+			Vector: vector,
 
 			Payload: payload{
 				Title:    d.Title,
@@ -187,7 +199,7 @@ func (c *Client) Delete(ctx context.Context, ids ...string) error {
 		return nil
 	}
 
-	if err := c.ensureCollection(c.namespace); err != nil {
+	if _, err := c.ensureCollection(c.namespace); err != nil {
 		return err
 	}
 
@@ -223,26 +235,88 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		options.Limit = to.Ptr(10)
 	}
 
-	if err := c.ensureCollection(c.namespace); err != nil {
+	sparse, err := c.ensureCollection(c.namespace)
+
+	if err != nil {
 		return nil, err
 	}
 
-	embedding, err := c.embedder.Embed(ctx, query)
+	hybrid := c.hybrid
 
-	if err != nil {
-		return nil, err
+	if options.Hybrid != nil {
+		hybrid = options.Hybrid
+	}
+
+	// collections created before keyword vectors existed only support dense search
+	if !sparse {
+		hybrid = nil
+	}
+
+	var filter map[string]any
+
+	if options.Filter != nil {
+		if filter, err = convertFilter(*options.Filter); err != nil {
+			return nil, err
+		}
 	}
 
+	limit := *options.Limit
+
+	var results []index.Result
+
+	if hybrid == nil || *hybrid > 0 {
+		embedding, err := c.embedder.Embed(ctx, []string{query}, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hybrid != nil {
+			limit = max(limit, 50)
+		}
+
+		if results, err = c.search(embedding.Embeddings[0], filter, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	if hybrid != nil {
+		vector := map[string]any{
+			"name":   keywordVector,
+			"vector": convertKeywords(query),
+		}
+
+		keywords, err := c.search(vector, filter, limit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = index.Fuse(results, keywords, *hybrid)
+
+		if len(results) > *options.Limit {
+			results = results[:*options.Limit]
+		}
+	}
+
+	return results, nil
+}
+
+func (c *Client) search(vector any, filter map[string]any, limit int) ([]index.Result, error) {
 	u, _ := url.JoinPath(c.url, "collections/"+c.namespace+"/points/search")
 
 	body := map[string]any{
-		"vector": embedding.Data,
-		"limit":  options.Limit,
+		"vector": vector,
+		"limit":  limit,
 
 		"with_vector":  true,
 		"with_payload": true,
 	}
 
+	if filter != nil {
+		body["filter"] = filter
+	}
+
 	req, _ := http.NewRequest("POST", u, jsonReader(body))
 	req.Header.Set("Content-Type", "application/json")
 
@@ -287,46 +361,68 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 	return results, nil
 }
 
-func (c *Client) ensureCollection(name string) error {
+// ensureCollection creates the collection if missing and reports whether it has keyword vectors
+func (c *Client) ensureCollection(name string) (bool, error) {
 	u, _ := url.JoinPath(c.url, "/collections/"+name)
 
 	resp, err := c.client.Get(u)
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	if resp.StatusCode == http.StatusNotFound {
-		embeddings, err := c.embedder.Embed(context.Background(), "init")
+	defer resp.Body.Close()
 
-		if err != nil {
-			return err
+	if resp.StatusCode == http.StatusOK {
+		var result collectionResult
+
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return false, err
 		}
 
-		body := map[string]any{
-			"vectors": map[string]any{
-				"size":     len(embeddings.Data),
-				"distance": "Cosine",
+		_, sparse := result.Result.Config.Params.SparseVectors[keywordVector]
+		return sparse, nil
+	}
+
+	if resp.StatusCode != http.StatusNotFound {
+		return false, errors.New("unable to ensure collection")
+	}
+
+	embeddings, err := c.embedder.Embed(context.Background(), []string{"init"}, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	body := map[string]any{
+		"vectors": map[string]any{
+			"size":     len(embeddings.Embeddings[0]),
+			"distance": "Cosine",
+		},
+
+		"sparse_vectors": map[string]any{
+			keywordVector: map[string]any{
+				"modifier": "idf",
 			},
-		}
+		},
+	}
 
-		req, _ := http.NewRequest("PUT", u, jsonReader(body))
-		req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("PUT", u, jsonReader(body))
+	req.Header.Set("Content-Type", "application/json")
 
-		resp, err = c.client.Do(req)
+	resp, err = c.client.Do(req)
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return false, err
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return errors.New("unable to ensure collection")
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.New("unable to ensure collection")
 	}
 
-	return nil
+	return true, nil
 }
 
 func convertID(id string) string {
@@ -341,6 +437,27 @@ func convertID(id string) string {
 	return uuid.NewMD5(uuid.NameSpaceOID, []byte(id)).String()
 }
 
+// convertKeywords hashes the term frequencies of a text into a sparse vector, qdrant weights them by idf
+func convertKeywords(text string) sparseVector {
+	frequencies := make(map[uint32]float32)
+
+	for _, t := range bm25.Tokenize(text) {
+		h := fnv.New32a()
+		h.Write([]byte(t))
+
+		frequencies[h.Sum32()]++
+	}
+
+	var result sparseVector
+
+	for i, f := range frequencies {
+		result.Indices = append(result.Indices, i)
+		result.Values = append(result.Values, f)
+	}
+
+	return result
+}
+
 func convertError(resp *http.Response) error {
 	data, _ := io.ReadAll(resp.Body)
 	text := string(data)
@@ -20,8 +20,9 @@ func WithEmbedder(embedder index.Embedder) Option {
 	}
 }
 
-func WithReranker(reranker index.Reranker) Option {
+// WithHybrid fuses keyword and vector results by default, weight ranges from 0 (keyword only) to 1 (vector only)
+func WithHybrid(weight float32) Option {
 	return func(c *Client) {
-		c.reranker = reranker
+		c.hybrid = &weight
 	}
 }
@@ -0,0 +1,74 @@
+package qdrant
+
+import (
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+// convertFilter translates a filter into a qdrant filter on the metadata payload,
+// numeric ranges only match numeric payload values and other ranges are compared as datetimes
+func convertFilter(f index.Filter) (map[string]any, error) {
+	condition, err := convertCondition(f)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if f.IsComparison() {
+		return map[string]any{"must": []any{condition}}, nil
+	}
+
+	return condition, nil
+}
+
+func convertCondition(f index.Filter) (map[string]any, error) {
+	if len(f.And) > 0 || len(f.Or) > 0 {
+		key, filters := "must", f.And
+
+		if len(f.Or) > 0 {
+			key, filters = "should", f.Or
+		}
+
+		var conditions []any
+
+		for _, c := range filters {
+			condition, err := convertCondition(c)
+
+			if err != nil {
+				return nil, err
+			}
+
+			conditions = append(conditions, condition)
+		}
+
+		return map[string]any{key: conditions}, nil
+	}
+
+	key := "metadata." + f.Field
+
+	switch f.Operator {
+	case index.OperatorEqual:
+		return map[string]any{"key": key, "match": map[string]any{"value": f.Value}}, nil
+
+	case index.OperatorNotEqual:
+		return map[string]any{"must_not": []any{
+			map[string]any{"key": key, "match": map[string]any{"value": f.Value}},
+		}}, nil
+
+	case index.OperatorIn:
+		return map[string]any{"key": key, "match": map[string]any{"any": f.Values}}, nil
+
+	case index.OperatorNotIn:
+		return map[string]any{"key": key, "match": map[string]any{"except": f.Values}}, nil
+
+	case index.OperatorGreater, index.OperatorGreaterEqual, index.OperatorLess, index.OperatorLessEqual:
+		if n, ok := f.Number(); ok {
+			return map[string]any{"key": key, "range": map[string]any{string(f.Operator): n}}, nil
+		}
+
+		return map[string]any{"key": key, "datetime_range": map[string]any{string(f.Operator): f.Value}}, nil
+	}
+
+	return nil, errors.New("unsupported filter operator: " + string(f.Operator))
+}
@@ -0,0 +1,62 @@
+package qdrant
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   index.Filter
+		expected string
+	}{
+		{"eq", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			`{"must": [{"key": "metadata.author", "match": {"value": "alice"}}]}`},
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "alice"},
+			`{"must": [{"must_not": [{"key": "metadata.author", "match": {"value": "alice"}}]}]}`},
+		{"gt", index.Filter{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+			`{"must": [{"key": "metadata.year", "range": {"gt": 2020}}]}`},
+		{"gte", index.Filter{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2020"},
+			`{"must": [{"key": "metadata.year", "range": {"gte": 2020}}]}`},
+		{"lt", index.Filter{Field: "year", Operator: index.OperatorLess, Value: "2020"},
+			`{"must": [{"key": "metadata.year", "range": {"lt": 2020}}]}`},
+		{"lte date", index.Filter{Field: "date", Operator: index.OperatorLessEqual, Value: "2024-01-15"},
+			`{"must": [{"key": "metadata.date", "datetime_range": {"lte": "2024-01-15"}}]}`},
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"alice", "bob"}},
+			`{"must": [{"key": "metadata.author", "match": {"any": ["alice", "bob"]}}]}`},
+		{"nin", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"alice"}},
+			`{"must": [{"key": "metadata.author", "match": {"except": ["alice"]}}]}`},
+		{"and", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+		}}, `{"must": [{"key": "metadata.author", "match": {"value": "alice"}}, {"key": "metadata.year", "range": {"gt": 2020}}]}`},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{And: []index.Filter{
+				{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+			}},
+		}}, `{"should": [{"key": "metadata.author", "match": {"value": "alice"}}, {"must": [{"key": "metadata.author", "match": {"value": "bob"}}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertFilter(tt.filter)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+
+			require.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestConvertFilterUnsupported(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "author", Operator: index.OperatorPrefix, Value: "al"})
+	require.Error(t, err)
+}
@@ -1,5 +1,9 @@
 package qdrant
 
+import (
+	"encoding/json"
+)
+
 type payload struct {
 	Title    string `json:"title,omitempty"`
 	Content  string `json:"content,omitempty"`
@@ -11,7 +15,15 @@ type payload struct {
 type point struct {
 	ID string `json:"id"`
 
-	Vector []float32 `json:"vector"`
+	Vector vector `json:"vector"`
+
+	Payload payload `json:"payload"`
+}
+
+type upsertPoint struct {
+	ID string `json:"id"`
+
+	Vector any `json:"vector"`
 
 	Payload payload `json:"payload"`
 }
@@ -22,7 +34,7 @@ type result struct {
 	Version int     `json:"version"`
 	Score   float32 `json:"score"`
 
-	Vector []float32 `json:"vector"`
+	Vector vector `json:"vector"`
 
 	Payload payload `json:"payload"`
 }
@@ -38,3 +50,45 @@ type scrollResult struct {
 		NextPageOffset string `json:"next_page_offset"`
 	} `json:"result"`
 }
+
+type collectionResult struct {
+	Result struct {
+		Config struct {
+			Params struct {
+				SparseVectors map[string]any `json:"sparse_vectors"`
+			} `json:"params"`
+		} `json:"config"`
+	} `json:"result"`
+}
+
+type sparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// vector decodes plain dense vectors as well as named vectors, where the dense vector has the empty name
+type vector []float32
+
+func (v *vector) UnmarshalJSON(data []byte) error {
+	var dense []float32
+
+	if err := json.Unmarshal(data, &dense); err == nil {
+		*v = dense
+		return nil
+	}
+
+	var named map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &named); err != nil {
+		return err
+	}
+
+	if val, ok := named[""]; ok {
+		if err := json.Unmarshal(val, &dense); err != nil {
+			return err
+		}
+	}
+
+	*v = dense
+	return nil
+}
@@ -0,0 +1,139 @@
+package reranker
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/index"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+var _ index.Provider = (*Provider)(nil)
+
+// candidates is how many results per requested one are fetched for reranking
+const candidates = 4
+
+type Provider struct {
+	index    index.Provider
+	reranker index.Reranker
+}
+
+// New returns an index that reranks the query results of p with the given reranker
+func New(p index.Provider, reranker index.Reranker) *Provider {
+	return &Provider{
+		index:    p,
+		reranker: reranker,
+	}
+}
+
+func (p *Provider) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
+	return p.index.List(ctx, options)
+}
+
+func (p *Provider) Index(ctx context.Context, documents ...index.Document) error {
+	return p.index.Index(ctx, documents...)
+}
+
+func (p *Provider) Delete(ctx context.Context, ids ...string) error {
+	return p.index.Delete(ctx, ids...)
+}
+
+func (p *Provider) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if options == nil {
+		options = new(index.QueryOptions)
+	}
+
+	fetch := *options
+
+	if options.Limit != nil {
+		limit := *options.Limit * candidates
+		fetch.Limit = &limit
+	}
+
+	results, err := p.index.Query(ctx, query, &fetch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	inputs := make([]string, 0, len(results))
+
+	for _, r := range results {
+		inputs = append(inputs, r.Content)
+	}
+
+	rankings, err := p.reranker.Rerank(ctx, query, inputs, &provider.RerankOptions{
+		Limit: options.Limit,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := rankingPositions(inputs, rankings)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]index.Result, 0, len(rankings))
+
+	for i, r := range rankings {
+		result := results[positions[i]]
+		result.Score = float32(r.Score)
+
+		reranked = append(reranked, result)
+	}
+
+	if options.Limit != nil && len(reranked) > *options.Limit {
+		reranked = reranked[:*options.Limit]
+	}
+
+	return reranked, nil
+}
+
+// rankingPositions returns the input position of every ranking. Rerankers which do not set distinct
+// indexes are matched by content, equal contents are assigned in the order of the inputs.
+func rankingPositions(inputs []string, rankings []provider.Ranking) ([]int, error) {
+	positions := make([]int, len(rankings))
+	seen := make(map[int]bool)
+
+	valid := true
+
+	for i, r := range rankings {
+		if r.Index < 0 || r.Index >= len(inputs) || seen[r.Index] || inputs[r.Index] != r.Content && r.Content != "" {
+			valid = false
+			break
+		}
+
+		seen[r.Index] = true
+		positions[i] = r.Index
+	}
+
+	if valid {
+		return positions, nil
+	}
+
+	unused := make(map[string][]int)
+
+	for i, input := range inputs {
+		unused[input] = append(unused[input], i)
+	}
+
+	for i, r := range rankings {
+		candidates := unused[r.Content]
+
+		if len(candidates) == 0 {
+			return nil, errors.New("reranker returned a ranking that matches no input")
+		}
+
+		positions[i] = candidates[0]
+		unused[r.Content] = candidates[1:]
+	}
+
+	return positions, nil
+}
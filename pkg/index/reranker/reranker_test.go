@@ -0,0 +1,138 @@
+package reranker_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+	"github.com/adrianliechti/llama/pkg/index/reranker"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+type staticIndex struct {
+	index.Provider
+
+	results []index.Result
+	limit   *int
+}
+
+func (i *staticIndex) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	i.limit = options.Limit
+	return i.results, nil
+}
+
+// reverseReranker ranks the inputs in reverse order
+type reverseReranker struct{}
+
+func (reverseReranker) Rerank(ctx context.Context, query string, inputs []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	var result []provider.Ranking
+
+	for i := len(inputs) - 1; i >= 0; i-- {
+		result = append(result, provider.Ranking{
+			Index: i,
+
+			Content: inputs[i],
+			Score:   float64(i + 1),
+		})
+	}
+
+	return result, nil
+}
+
+func TestQuery(t *testing.T) {
+	i := &staticIndex{
+		results: []index.Result{
+			{Document: index.Document{ID: "a", Content: "same"}},
+			{Document: index.Document{ID: "b", Content: "other"}},
+			{Document: index.Document{ID: "c", Content: "same"}},
+		},
+	}
+
+	p := reranker.New(i, reverseReranker{})
+
+	limit := 2
+
+	results, err := p.Query(context.Background(), "query", &index.QueryOptions{
+		Limit: &limit,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 8, *i.limit)
+
+	require.Len(t, results, 2)
+
+	require.Equal(t, "c", results[0].ID)
+	require.Equal(t, float32(3), results[0].Score)
+
+	require.Equal(t, "b", results[1].ID)
+	require.Equal(t, float32(2), results[1].Score)
+}
+
+// contentReranker ranks the inputs by length without setting their index
+type contentReranker struct {
+	extra string
+}
+
+func (r contentReranker) Rerank(ctx context.Context, query string, inputs []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	var result []provider.Ranking
+
+	for _, input := range inputs {
+		result = append(result, provider.Ranking{
+			Content: input,
+			Score:   float64(len(input)),
+		})
+	}
+
+	if r.extra != "" {
+		result = append(result, provider.Ranking{Content: r.extra})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+
+	return result, nil
+}
+
+func TestQueryWithoutIndex(t *testing.T) {
+	i := &staticIndex{
+		results: []index.Result{
+			{Document: index.Document{ID: "a", Content: "short"}},
+			{Document: index.Document{ID: "b", Content: "a bit longer"}},
+			{Document: index.Document{ID: "c", Content: "short"}},
+			{Document: index.Document{ID: "d", Content: "the longest of all"}},
+		},
+	}
+
+	p := reranker.New(i, contentReranker{})
+
+	results, err := p.Query(context.Background(), "query", nil)
+	require.NoError(t, err)
+
+	var ids []string
+
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+
+	// rankings are matched by content, equal contents in the order of the results
+	require.Equal(t, []string{"d", "b", "a", "c"}, ids)
+	require.Equal(t, float32(18), results[0].Score)
+}
+
+func TestQueryUnknownRanking(t *testing.T) {
+	i := &staticIndex{
+		results: []index.Result{
+			{Document: index.Document{ID: "a", Content: "short"}},
+			{Document: index.Document{ID: "b", Content: "a bit longer"}},
+		},
+	}
+
+	p := reranker.New(i, contentReranker{extra: "unknown"})
+
+	_, err := p.Query(context.Background(), "query", nil)
+	require.Error(t, err)
+}
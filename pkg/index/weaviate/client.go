@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/adrianliechti/llama/pkg/index"
@@ -28,7 +29,8 @@ type Client struct {
 	class string
 
 	embedder index.Embedder
-	reranker index.Reranker
+
+	hybrid *float32
 }
 
 func New(url, namespace string, options ...Option) (*Client, error) {
@@ -143,17 +145,13 @@ func (c *Client) List(ctx context.Context, options *index.ListOptions) ([]index.
 }
 
 func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
-	for _, d := range documents {
-		if len(d.Embedding) == 0 && c.embedder != nil {
-			embedding, err := c.embedder.Embed(ctx, d.Content)
-
-			if err != nil {
-				return err
-			}
+	documents, err := index.EmbedDocuments(ctx, c.embedder, documents)
 
-			d.Embedding = embedding.Data
-		}
+	if err != nil {
+		return err
+	}
 
+	for _, d := range documents {
 		if len(d.Embedding) == 0 {
 			continue
 		}
@@ -203,13 +201,13 @@ func (c *Client) Delete(ctx context.Context, ids ...string) error {
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
 	var vector strings.Builder
 
-	embedding, err := c.embedder.Embed(ctx, query)
+	embedding, err := c.embedder.Embed(ctx, []string{query}, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	for i, v := range embedding.Data {
+	for i, v := range embedding.Embeddings[0] {
 		if i > 0 {
 			vector.WriteString(", ")
 		}
@@ -217,14 +215,30 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		vector.WriteString(fmt.Sprintf("%f", v))
 	}
 
+	var where string
+
+	if options.Filter != nil {
+		if where, err = convertFilter(*options.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	alpha := c.hybrid
+
+	if options.Hybrid != nil {
+		alpha = options.Hybrid
+	}
+
 	data := executeQueryTemplate(queryData{
 		Class: c.class,
 
 		Query:  query,
-		Vector: embedding.Data,
+		Vector: embedding.Embeddings[0],
+
+		Alpha: alpha,
 
 		Limit: options.Limit,
-		Where: options.Filters,
+		Where: where,
 	})
 
 	body := map[string]any{
@@ -279,8 +293,15 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 			key = d.Key
 		}
 
+		score := d.Additional.Certainty
+
+		// hybrid queries report a fused score instead of a certainty
+		if val, err := strconv.ParseFloat(d.Additional.Score, 32); err == nil {
+			score = float32(val)
+		}
+
 		r := index.Result{
-			Score: d.Additional.Certainty,
+			Score: score,
 
 			Document: index.Document{
 				ID: key,
@@ -435,6 +456,8 @@ type additional struct {
 	ID        string  `json:"id"`
 	Distance  float32 `json:"distance"`
 	Certainty float32 `json:"certainty"`
+
+	Score string `json:"score"`
 }
 
 func jsonReader(v any) io.Reader {
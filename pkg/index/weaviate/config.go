@@ -20,8 +20,9 @@ func WithEmbedder(embedder index.Embedder) Option {
 	}
 }
 
-func WithReranker(reranker index.Reranker) Option {
+// WithHybrid sets the default alpha of hybrid queries, from 0 (keyword only) to 1 (vector only)
+func WithHybrid(alpha float32) Option {
 	return func(c *Client) {
-		c.reranker = reranker
+		c.hybrid = &alpha
 	}
 }
@@ -0,0 +1,81 @@
+package weaviate
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/adrianliechti/llama/pkg/index"
+)
+
+var operators = map[index.Operator]string{
+	index.OperatorEqual:        "Equal",
+	index.OperatorNotEqual:     "NotEqual",
+	index.OperatorGreater:      "GreaterThan",
+	index.OperatorGreaterEqual: "GreaterThanEqual",
+	index.OperatorLess:         "LessThan",
+	index.OperatorLessEqual:    "LessThanEqual",
+	index.OperatorIn:           "ContainsAny",
+	index.OperatorPrefix:       "Like",
+}
+
+// convertFilter translates a filter into a GraphQL where argument
+func convertFilter(f index.Filter) (string, error) {
+	if len(f.And) > 0 || len(f.Or) > 0 {
+		operator, filters := "And", f.And
+
+		if len(f.Or) > 0 {
+			operator, filters = "Or", f.Or
+		}
+
+		return combineFilters(operator, filters)
+	}
+
+	// weaviate has no negated ContainsAny
+	if f.Operator == index.OperatorNotIn {
+		var filters []index.Filter
+
+		for _, v := range f.Values {
+			filters = append(filters, index.Filter{Field: f.Field, Operator: index.OperatorNotEqual, Value: v})
+		}
+
+		return combineFilters("And", filters)
+	}
+
+	operator, ok := operators[f.Operator]
+
+	if !ok {
+		return "", errors.New("unsupported filter operator: " + string(f.Operator))
+	}
+
+	var value any = f.Value
+
+	switch f.Operator {
+	case index.OperatorIn:
+		value = f.Values
+
+	case index.OperatorPrefix:
+		value = f.Value + "*"
+	}
+
+	path, _ := json.Marshal([]string{f.Field})
+	text, _ := json.Marshal(value)
+
+	return "{ path: " + string(path) + ", operator: " + operator + ", valueText: " + string(text) + " }", nil
+}
+
+func combineFilters(operator string, filters []index.Filter) (string, error) {
+	var operands []string
+
+	for _, c := range filters {
+		operand, err := convertFilter(c)
+
+		if err != nil {
+			return "", err
+		}
+
+		operands = append(operands, operand)
+	}
+
+	return "{ operator: " + operator + ", operands: [" + strings.Join(operands, ", ") + "] }", nil
+}
@@ -0,0 +1,57 @@
+package weaviate
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   index.Filter
+		expected string
+	}{
+		{"eq", index.Filter{Field: "author", Operator: index.OperatorEqual, Value: `al"ice`},
+			`{ path: ["author"], operator: Equal, valueText: "al\"ice" }`},
+		{"ne", index.Filter{Field: "author", Operator: index.OperatorNotEqual, Value: "alice"},
+			`{ path: ["author"], operator: NotEqual, valueText: "alice" }`},
+		{"gt", index.Filter{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+			`{ path: ["year"], operator: GreaterThan, valueText: "2020" }`},
+		{"gte", index.Filter{Field: "year", Operator: index.OperatorGreaterEqual, Value: "2020"},
+			`{ path: ["year"], operator: GreaterThanEqual, valueText: "2020" }`},
+		{"lt", index.Filter{Field: "year", Operator: index.OperatorLess, Value: "2020"},
+			`{ path: ["year"], operator: LessThan, valueText: "2020" }`},
+		{"lte", index.Filter{Field: "year", Operator: index.OperatorLessEqual, Value: "2020"},
+			`{ path: ["year"], operator: LessThanEqual, valueText: "2020" }`},
+		{"in", index.Filter{Field: "author", Operator: index.OperatorIn, Values: []string{"alice", "bob"}},
+			`{ path: ["author"], operator: ContainsAny, valueText: ["alice","bob"] }`},
+		{"nin", index.Filter{Field: "author", Operator: index.OperatorNotIn, Values: []string{"alice", "bob"}},
+			`{ operator: And, operands: [{ path: ["author"], operator: NotEqual, valueText: "alice" }, { path: ["author"], operator: NotEqual, valueText: "bob" }] }`},
+		{"prefix", index.Filter{Field: "author", Operator: index.OperatorPrefix, Value: "al"},
+			`{ path: ["author"], operator: Like, valueText: "al*" }`},
+		{"and", index.Filter{And: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "year", Operator: index.OperatorGreater, Value: "2020"},
+		}}, `{ operator: And, operands: [{ path: ["author"], operator: Equal, valueText: "alice" }, { path: ["year"], operator: GreaterThan, valueText: "2020" }] }`},
+		{"or", index.Filter{Or: []index.Filter{
+			{Field: "author", Operator: index.OperatorEqual, Value: "alice"},
+			{Field: "author", Operator: index.OperatorEqual, Value: "bob"},
+		}}, `{ operator: Or, operands: [{ path: ["author"], operator: Equal, valueText: "alice" }, { path: ["author"], operator: Equal, valueText: "bob" }] }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertFilter(tt.filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestConvertFilterUnsupported(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "author", Operator: "like", Value: "al"})
+	require.Error(t, err)
+}
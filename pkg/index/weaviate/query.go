@@ -18,8 +18,10 @@ type queryData struct {
 	Query  string
 	Vector []float32
 
+	Alpha *float32
+
 	Limit *int
-	Where map[string]string
+	Where string
 }
 
 func executeQueryTemplate(data queryData) string {
@@ -0,0 +1,157 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+var _ provider.Completer = (*Completer)(nil)
+
+type Completer struct {
+	retries int
+
+	completer provider.Completer
+}
+
+type Option func(*Completer)
+
+func NewCompleter(completer provider.Completer, options ...Option) *Completer {
+	c := &Completer{
+		retries: 3,
+
+		completer: completer,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+func WithRetries(retries int) Option {
+	return func(c *Completer) {
+		c.retries = retries
+	}
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil || options.Format != provider.CompletionFormatJSONSchema || options.Schema == nil {
+		return c.completer.Complete(ctx, messages, options)
+	}
+
+	// only the first choice could be validated and retried, so the others would not be guaranteed to match the schema
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("json schema emulation", "multiple choices")
+	}
+
+	schema := options.Schema
+
+	instructions, err := schemaInstructions(schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	input := append([]provider.Message{
+		{
+			Role:    provider.MessageRoleSystem,
+			Content: instructions,
+		},
+	}, messages...)
+
+	inputOptions := *options
+	inputOptions.Stream = nil
+	inputOptions.Format = provider.CompletionFormatJSON
+	inputOptions.Schema = nil
+
+	var usage provider.Usage
+
+	for i := 0; ; i++ {
+		completion, err := c.completer.Complete(ctx, input, &inputOptions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if completion.Usage != nil {
+			usage.InputTokens += completion.Usage.InputTokens
+			usage.OutputTokens += completion.Usage.OutputTokens
+
+			completion.Usage = &usage
+		}
+
+		content := trimCodeBlock(completion.Message.Content)
+
+		verr := Validate(schema.Schema, []byte(content))
+
+		if verr == nil {
+			completion.Message.Content = content
+
+			if options.Stream != nil {
+				if err := options.Stream(ctx, *completion); err != nil {
+					return nil, err
+				}
+			}
+
+			return completion, nil
+		}
+
+		if i >= c.retries {
+			return nil, &provider.Error{
+				StatusCode: http.StatusUnprocessableEntity,
+				Message:    "response does not match schema: " + verr.Error(),
+			}
+		}
+
+		input = append(input, completion.Message, provider.Message{
+			Role:    provider.MessageRoleUser,
+			Content: "Your response does not match the JSON schema (" + verr.Error() + "). Respond again with only the corrected JSON.",
+		})
+	}
+}
+
+func schemaInstructions(schema *provider.Schema) (string, error) {
+	if schema.Schema == nil {
+		return "", &provider.Error{
+			StatusCode: http.StatusBadRequest,
+			Message:    "missing json schema",
+		}
+	}
+
+	data, err := json.MarshalIndent(schema.Schema, "", "  ")
+
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("Respond only with a JSON document that matches the following JSON schema. Do not add any explanations or markdown.\n")
+
+	if schema.Description != "" {
+		sb.WriteString(schema.Description)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.Write(data)
+
+	return sb.String(), nil
+}
+
+func trimCodeBlock(s string) string {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```json")
+		s = strings.TrimPrefix(s, "```")
+		s = strings.TrimSuffix(s, "```")
+	}
+
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,148 @@
+package jsonschema_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/jsonschema"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sequenceCompleter struct {
+	responses []string
+	requests  [][]provider.Message
+}
+
+func (c *sequenceCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.requests = append(c.requests, messages)
+
+	content := c.responses[0]
+
+	if len(c.responses) > 1 {
+		c.responses = c.responses[1:]
+	}
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: content,
+		},
+
+		Usage: &provider.Usage{
+			InputTokens:  10,
+			OutputTokens: 5,
+		},
+	}, nil
+}
+
+func schemaOptions() *provider.CompleteOptions {
+	return &provider.CompleteOptions{
+		Format: provider.CompletionFormatJSONSchema,
+
+		Schema: &provider.Schema{
+			Name: "person",
+
+			Schema: map[string]any{
+				"type":     "object",
+				"required": []any{"name"},
+			},
+		},
+	}
+}
+
+func TestCompleterRetry(t *testing.T) {
+	completer := &sequenceCompleter{
+		responses: []string{`{}`, "```json\n{\"name\": \"Alice\"}\n```"},
+	}
+
+	c := jsonschema.NewCompleter(completer)
+
+	result, err := c.Complete(context.Background(), []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "Who are you?",
+		},
+	}, schemaOptions())
+
+	require.NoError(t, err)
+	require.Equal(t, `{"name": "Alice"}`, result.Message.Content)
+	require.Equal(t, 20, result.Usage.InputTokens)
+
+	require.Len(t, completer.requests, 2)
+	require.Len(t, completer.requests[1], 4)
+}
+
+func TestCompleterRetryExhausted(t *testing.T) {
+	completer := &sequenceCompleter{
+		responses: []string{`{}`},
+	}
+
+	c := jsonschema.NewCompleter(completer, jsonschema.WithRetries(2))
+
+	_, err := c.Complete(context.Background(), []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "Who are you?",
+		},
+	}, schemaOptions())
+
+	var perr *provider.Error
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, 422, perr.StatusCode)
+
+	require.Len(t, completer.requests, 3)
+}
+
+func TestCompleterMultipleChoices(t *testing.T) {
+	completer := &sequenceCompleter{
+		responses: []string{`{"name": "Alice"}`},
+	}
+
+	c := jsonschema.NewCompleter(completer)
+
+	n := 2
+
+	options := schemaOptions()
+	options.N = &n
+
+	_, err := c.Complete(context.Background(), []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "Who are you?",
+		},
+	}, options)
+
+	var perr *provider.Error
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, 400, perr.StatusCode)
+
+	require.Empty(t, completer.requests)
+}
+
+func TestCompleterMissingSchema(t *testing.T) {
+	completer := &sequenceCompleter{
+		responses: []string{`{}`},
+	}
+
+	c := jsonschema.NewCompleter(completer)
+
+	options := schemaOptions()
+	options.Schema.Schema = nil
+
+	_, err := c.Complete(context.Background(), []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "Who are you?",
+		},
+	}, options)
+
+	var perr *provider.Error
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, 400, perr.StatusCode)
+
+	require.Empty(t, completer.requests)
+}
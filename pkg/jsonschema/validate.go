@@ -0,0 +1,404 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks data against the commonly used subset of JSON Schema (types, enum, const,
+// properties, required, additionalProperties, items, bounds, patterns, combinators and local $refs)
+func Validate(schema map[string]any, data []byte) error {
+	var value any
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &ValidationError{Path: "$", Message: "invalid json: " + err.Error()}
+	}
+
+	v := &validator{
+		root: schema,
+	}
+
+	return v.validate("$", schema, value)
+}
+
+type validator struct {
+	root map[string]any
+}
+
+func (v *validator) validate(path string, schema map[string]any, value any) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := v.resolve(ref)
+
+		if err != nil {
+			return &ValidationError{Path: path, Message: err.Error()}
+		}
+
+		return v.validate(path, resolved, value)
+	}
+
+	if t, ok := schema["type"]; ok {
+		if err := v.validateType(path, t, value); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+
+		for _, e := range enum {
+			if equal(e, value) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("value must be one of %v", enum)}
+		}
+	}
+
+	if c, ok := schema["const"]; ok && !equal(c, value) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("value must be %v", c)}
+	}
+
+	for _, s := range schemas(schema["allOf"]) {
+		if err := v.validate(path, s, value); err != nil {
+			return err
+		}
+	}
+
+	if anyOf := schemas(schema["anyOf"]); len(anyOf) > 0 {
+		if v.matches(path, anyOf, value) == 0 {
+			return &ValidationError{Path: path, Message: "value does not match any schema of anyOf"}
+		}
+	}
+
+	if oneOf := schemas(schema["oneOf"]); len(oneOf) > 0 {
+		if v.matches(path, oneOf, value) != 1 {
+			return &ValidationError{Path: path, Message: "value must match exactly one schema of oneOf"}
+		}
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		return v.validateObject(path, schema, val)
+
+	case []any:
+		return v.validateArray(path, schema, val)
+
+	case string:
+		return v.validateString(path, schema, val)
+
+	case float64:
+		return v.validateNumber(path, schema, val)
+	}
+
+	return nil
+}
+
+func (v *validator) validateType(path string, t any, value any) error {
+	var types []string
+
+	switch t := t.(type) {
+	case string:
+		types = []string{t}
+
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	for _, t := range types {
+		if isType(t, value) {
+			return nil
+		}
+	}
+
+	return &ValidationError{Path: path, Message: fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), typeOf(value))}
+}
+
+func (v *validator) validateObject(path string, schema map[string]any, value map[string]any) error {
+	properties, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+
+			if _, ok := value[name]; !ok {
+				return &ValidationError{Path: path, Message: "missing required property " + name}
+			}
+		}
+	}
+
+	for name, val := range value {
+		if s, ok := properties[name].(map[string]any); ok {
+			if err := v.validate(path+"."+name, s, val); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return &ValidationError{Path: path, Message: "unexpected property " + name}
+			}
+
+		case map[string]any:
+			if err := v.validate(path+"."+name, additional, val); err != nil {
+				return err
+			}
+		}
+	}
+
+	if min, ok := number(schema["minProperties"]); ok && float64(len(value)) < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must have at least %v properties", min)}
+	}
+
+	if max, ok := number(schema["maxProperties"]); ok && float64(len(value)) > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must have at most %v properties", max)}
+	}
+
+	return nil
+}
+
+func (v *validator) validateArray(path string, schema map[string]any, value []any) error {
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range value {
+			if err := v.validate(fmt.Sprintf("%s[%d]", path, i), items, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	if min, ok := number(schema["minItems"]); ok && float64(len(value)) < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must have at least %v items", min)}
+	}
+
+	if max, ok := number(schema["maxItems"]); ok && float64(len(value)) > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must have at most %v items", max)}
+	}
+
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range value {
+			for j := i + 1; j < len(value); j++ {
+				if equal(value[i], value[j]) {
+					return &ValidationError{Path: path, Message: "items must be unique"}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateString(path string, schema map[string]any, value string) error {
+	length := float64(utf8.RuneCountInString(value))
+
+	if min, ok := number(schema["minLength"]); ok && length < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be at least %v characters", min)}
+	}
+
+	if max, ok := number(schema["maxLength"]); ok && length > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be at most %v characters", max)}
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+
+		if err != nil {
+			return &ValidationError{Path: path, Message: "invalid pattern: " + pattern}
+		}
+
+		if !re.MatchString(value) {
+			return &ValidationError{Path: path, Message: "must match pattern " + pattern}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateNumber(path string, schema map[string]any, value float64) error {
+	if min, ok := number(schema["minimum"]); ok && value < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be >= %v", min)}
+	}
+
+	if max, ok := number(schema["maximum"]); ok && value > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be <= %v", max)}
+	}
+
+	if min, ok := number(schema["exclusiveMinimum"]); ok && value <= min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be > %v", min)}
+	}
+
+	if max, ok := number(schema["exclusiveMaximum"]); ok && value >= max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be < %v", max)}
+	}
+
+	if multiple, ok := number(schema["multipleOf"]); ok && multiple != 0 {
+		if q := value / multiple; math.Abs(q-math.Round(q)) > 1e-9 {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("must be a multiple of %v", multiple)}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) matches(path string, schemas []map[string]any, value any) int {
+	var count int
+
+	for _, s := range schemas {
+		if v.validate(path, s, value) == nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (v *validator) resolve(ref string) (map[string]any, error) {
+	if ref == "#" {
+		return v.root, nil
+	}
+
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported reference %s", ref)
+	}
+
+	var current any = v.root
+
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(part, "~1", "/")
+		part = strings.ReplaceAll(part, "~0", "~")
+
+		m, ok := current.(map[string]any)
+
+		if !ok {
+			return nil, fmt.Errorf("invalid reference %s", ref)
+		}
+
+		current = m[part]
+	}
+
+	result, ok := current.(map[string]any)
+
+	if !ok {
+		return nil, fmt.Errorf("invalid reference %s", ref)
+	}
+
+	return result, nil
+}
+
+func isType(t string, value any) bool {
+	switch t {
+	case "null":
+		return value == nil
+
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+
+	case "array":
+		_, ok := value.([]any)
+		return ok
+
+	case "string":
+		_, ok := value.(string)
+		return ok
+
+	case "number":
+		_, ok := value.(float64)
+		return ok
+
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	}
+
+	return false
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+
+	case bool:
+		return "boolean"
+
+	case map[string]any:
+		return "object"
+
+	case []any:
+		return "array"
+
+	case string:
+		return "string"
+
+	case float64:
+		return "number"
+	}
+
+	return "unknown"
+}
+
+func schemas(val any) []map[string]any {
+	items, _ := val.([]any)
+
+	var result []map[string]any
+
+	for _, item := range items {
+		if s, ok := item.(map[string]any); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+func number(val any) (float64, bool) {
+	switch n := val.(type) {
+	case float64:
+		return n, true
+
+	case int:
+		return float64(n), true
+
+	case int64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return x == y
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,81 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/jsonschema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		data   string
+		path   string
+	}{
+		{"type string", `{"type": "string"}`, `"hello"`, ""},
+		{"type string mismatch", `{"type": "string"}`, `42`, "$"},
+		{"type integer", `{"type": "integer"}`, `42`, ""},
+		{"type integer fraction", `{"type": "integer"}`, `4.2`, "$"},
+		{"type number", `{"type": "number"}`, `4.2`, ""},
+		{"type boolean", `{"type": "boolean"}`, `true`, ""},
+		{"type null", `{"type": "null"}`, `null`, ""},
+		{"type object mismatch", `{"type": "object"}`, `[]`, "$"},
+		{"type array mismatch", `{"type": "array"}`, `{}`, "$"},
+		{"type union", `{"type": ["string", "null"]}`, `null`, ""},
+		{"type union mismatch", `{"type": ["string", "null"]}`, `false`, "$"},
+
+		{"required", `{"type": "object", "required": ["name"]}`, `{"name": "a"}`, ""},
+		{"required missing", `{"type": "object", "required": ["name"]}`, `{"age": 1}`, "$"},
+
+		{"enum", `{"enum": ["red", "green"]}`, `"green"`, ""},
+		{"enum mismatch", `{"enum": ["red", "green"]}`, `"blue"`, "$"},
+		{"enum number", `{"enum": [1, 2]}`, `2`, ""},
+
+		{"properties", `{"type": "object", "properties": {"age": {"type": "integer"}}}`, `{"age": 1}`, ""},
+		{"properties mismatch", `{"type": "object", "properties": {"age": {"type": "integer"}}}`, `{"age": "1"}`, "$.age"},
+		{"additional properties allowed", `{"type": "object", "properties": {"age": {"type": "integer"}}}`, `{"name": "a"}`, ""},
+		{"additional properties false", `{"type": "object", "properties": {"age": {"type": "integer"}}, "additionalProperties": false}`, `{"name": "a"}`, "$"},
+		{"additional properties schema", `{"type": "object", "additionalProperties": {"type": "string"}}`, `{"name": "a"}`, ""},
+		{"additional properties schema mismatch", `{"type": "object", "additionalProperties": {"type": "string"}}`, `{"name": 1}`, "$.name"},
+
+		{"items", `{"type": "array", "items": {"type": "string"}}`, `["a", "b"]`, ""},
+		{"items mismatch", `{"type": "array", "items": {"type": "string"}}`, `["a", 1]`, "$[1]"},
+		{"items nested", `{"type": "array", "items": {"type": "object", "required": ["id"]}}`, `[{"id": 1}, {}]`, "$[1]"},
+
+		{"ref", `{"$defs": {"name": {"type": "string"}}, "type": "object", "properties": {"name": {"$ref": "#/$defs/name"}}}`, `{"name": "a"}`, ""},
+		{"ref mismatch", `{"$defs": {"name": {"type": "string"}}, "type": "object", "properties": {"name": {"$ref": "#/$defs/name"}}}`, `{"name": 1}`, "$.name"},
+		{"ref nested", `{"$defs": {"person": {"type": "object", "properties": {"address": {"$ref": "#/$defs/address"}}}, "address": {"type": "object", "required": ["city"]}}, "$ref": "#/$defs/person"}`, `{"address": {}}`, "$.address"},
+		{"ref recursive", `{"type": "object", "properties": {"child": {"$ref": "#"}, "name": {"type": "string"}}}`, `{"child": {"child": {"name": 1}}}`, "$.child.child.name"},
+		{"ref unresolved", `{"$ref": "#/$defs/missing"}`, `{}`, "$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tt.schema), &schema))
+
+			err := jsonschema.Validate(schema, []byte(tt.data))
+
+			if tt.path == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *jsonschema.ValidationError
+			require.ErrorAs(t, err, &verr)
+			require.Equal(t, tt.path, verr.Path)
+		})
+	}
+}
+
+func TestValidateInvalidJSON(t *testing.T) {
+	err := jsonschema.Validate(map[string]any{"type": "object"}, []byte(`{"name":`))
+
+	var verr *jsonschema.ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, "$", verr.Path)
+}
@@ -27,6 +27,10 @@ func NewSynthesizer(l *rate.Limiter, p provider.Synthesizer) Synthesizer {
 func (p *limitedSynthesizer) limiterSetup() {
 }
 
+func (p *limitedSynthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	return p.provider.Voices(ctx)
+}
+
 func (p *limitedSynthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
 	if p.limiter != nil {
 		p.limiter.Wait(ctx)
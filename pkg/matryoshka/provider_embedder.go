@@ -0,0 +1,86 @@
+package matryoshka
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+var _ provider.Embedder = (*Embedder)(nil)
+
+type Embedder struct {
+	embedder provider.Embedder
+}
+
+func NewEmbedder(embedder provider.Embedder) *Embedder {
+	return &Embedder{
+		embedder: embedder,
+	}
+}
+
+func (e *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	if options == nil || options.Dimensions == nil {
+		return e.embedder.Embed(ctx, texts, options)
+	}
+
+	dimensions := *options.Dimensions
+
+	if dimensions <= 0 {
+		return nil, &provider.Error{
+			StatusCode: 400,
+			Message:    "dimensions must be greater than 0",
+		}
+	}
+
+	inputOptions := *options
+	inputOptions.Dimensions = nil
+
+	result, err := e.embedder.Embed(ctx, texts, &inputOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, embedding := range result.Embeddings {
+		if dimensions > len(embedding) {
+			return nil, &provider.Error{
+				StatusCode: 400,
+				Message:    fmt.Sprintf("dimensions must not exceed %d", len(embedding)),
+			}
+		}
+
+		result.Embeddings[i] = truncate(embedding, dimensions)
+	}
+
+	return result, nil
+}
+
+// truncate shortens a matryoshka embedding to the given dimensions and normalizes it to unit length
+func truncate(embedding []float32, dimensions int) []float32 {
+	if dimensions >= len(embedding) {
+		return embedding
+	}
+
+	result := make([]float32, dimensions)
+	copy(result, embedding)
+
+	var norm float64
+
+	for _, v := range result {
+		norm += float64(v) * float64(v)
+	}
+
+	if norm == 0 {
+		return result
+	}
+
+	norm = math.Sqrt(norm)
+
+	for i, v := range result {
+		result[i] = float32(float64(v) / norm)
+	}
+
+	return result
+}
@@ -0,0 +1,105 @@
+package matryoshka_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/matryoshka"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// staticEmbedder returns its embedding for every text and records the options it received
+type staticEmbedder struct {
+	embedding []float32
+
+	options *provider.EmbedOptions
+}
+
+func (e *staticEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	e.options = options
+
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, append([]float32(nil), e.embedding...))
+	}
+
+	return result, nil
+}
+
+func dimensions(n int) *provider.EmbedOptions {
+	return &provider.EmbedOptions{Dimensions: &n}
+}
+
+func norm(embedding []float32) float64 {
+	var sum float64
+
+	for _, v := range embedding {
+		sum += float64(v) * float64(v)
+	}
+
+	return math.Sqrt(sum)
+}
+
+func TestEmbedTruncate(t *testing.T) {
+	p := &staticEmbedder{embedding: []float32{3, 4, 12, 84}}
+	e := matryoshka.NewEmbedder(p)
+
+	result, err := e.Embed(context.Background(), []string{"a", "b"}, dimensions(2))
+	require.NoError(t, err)
+
+	// the provider is asked for the full embedding
+	require.Nil(t, p.options.Dimensions)
+
+	require.Len(t, result.Embeddings, 2)
+
+	for _, embedding := range result.Embeddings {
+		require.InDeltaSlice(t, []float32{0.6, 0.8}, embedding, 1e-6)
+		require.InDelta(t, 1, norm(embedding), 1e-6)
+	}
+}
+
+func TestEmbedFullDimensions(t *testing.T) {
+	p := &staticEmbedder{embedding: []float32{3, 4, 12, 84}}
+	e := matryoshka.NewEmbedder(p)
+
+	result, err := e.Embed(context.Background(), []string{"a"}, dimensions(4))
+	require.NoError(t, err)
+
+	// embeddings of the requested size are returned as is
+	require.Equal(t, [][]float32{{3, 4, 12, 84}}, result.Embeddings)
+}
+
+func TestEmbedZeroVector(t *testing.T) {
+	e := matryoshka.NewEmbedder(&staticEmbedder{embedding: []float32{0, 0, 1}})
+
+	result, err := e.Embed(context.Background(), []string{"a"}, dimensions(2))
+	require.NoError(t, err)
+
+	require.Equal(t, [][]float32{{0, 0}}, result.Embeddings)
+}
+
+func TestEmbedInvalidDimensions(t *testing.T) {
+	e := matryoshka.NewEmbedder(&staticEmbedder{embedding: []float32{3, 4}})
+
+	for _, n := range []int{0, -1, 3} {
+		_, err := e.Embed(context.Background(), []string{"a"}, dimensions(n))
+
+		var perr *provider.Error
+		require.ErrorAs(t, err, &perr)
+		require.Equal(t, 400, perr.StatusCode)
+	}
+}
+
+func TestEmbedWithoutDimensions(t *testing.T) {
+	p := &staticEmbedder{embedding: []float32{3, 4}}
+	e := matryoshka.NewEmbedder(p)
+
+	result, err := e.Embed(context.Background(), []string{"a"}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]float32{{3, 4}}, result.Embeddings)
+}
@@ -0,0 +1,55 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func SetEndUser(ctx context.Context, p *authorizer.Principal) {
+	if p == nil {
+		return
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(endUserAttributes(p)...)
+}
+
+func endUserAttributes(p *authorizer.Principal) []attribute.KeyValue {
+	var result []attribute.KeyValue
+
+	if p.ID != "" {
+		result = append(result, semconv.EnduserID(p.ID))
+	}
+
+	if p.Email != "" {
+		result = append(result, attribute.String("enduser.email", p.Email))
+	}
+
+	return result
+}
+
+type endUserProcessor struct {
+}
+
+func (endUserProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	if p, ok := authorizer.PrincipalFromContext(ctx); ok {
+		s.SetAttributes(endUserAttributes(p)...)
+	}
+}
+
+func (endUserProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+}
+
+func (endUserProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (endUserProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
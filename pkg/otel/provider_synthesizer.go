@@ -42,6 +42,10 @@ func NewSynthesizer(provider, model string, p provider.Synthesizer) Synthesizer
 func (p *observableSynthesizer) otelSetup() {
 }
 
+func (p *observableSynthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	return p.synthesizer.Voices(ctx)
+}
+
 func (p *observableSynthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
 	ctx, span := otel.Tracer(p.library).Start(ctx, p.name)
 	defer span.End()
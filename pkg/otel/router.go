@@ -0,0 +1,33 @@
+package otel
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func MeterRouterState(ctx context.Context, router, capability, model string, state, outstanding int64, latency time.Duration) {
+	attributes := metric.WithAttributes(
+		attribute.String("router", strings.ToLower(router)),
+		attribute.String("capability", capability),
+		attribute.String("model", strings.ToLower(model)),
+	)
+
+	meter := otel.Meter("router")
+
+	if gauge, err := meter.Int64Gauge("llm_router_circuit_state"); err == nil {
+		gauge.Record(ctx, state, attributes)
+	}
+
+	if gauge, err := meter.Int64Gauge("llm_router_outstanding_requests"); err == nil {
+		gauge.Record(ctx, outstanding, attributes)
+	}
+
+	if gauge, err := meter.Float64Gauge("llm_router_latency_seconds"); err == nil {
+		gauge.Record(ctx, latency.Seconds(), attributes)
+	}
+}
@@ -2,6 +2,7 @@ package reranker
 
 import (
 	"context"
+	"errors"
 	"math"
 	"sort"
 
@@ -21,24 +22,24 @@ func FromEmbedder(embedder provider.Embedder) *Adapter {
 }
 
 func (a *Adapter) Rerank(ctx context.Context, query string, inputs []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
-	result, err := a.embedder.Embed(ctx, query)
+	embedding, err := a.embedder.Embed(ctx, append([]string{query}, inputs...), nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var results []provider.Ranking
-
-	for _, input := range inputs {
-		embedding, err := a.embedder.Embed(ctx, input)
+	if len(embedding.Embeddings) != len(inputs)+1 {
+		return nil, errors.New("invalid embeddings count")
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	var results []provider.Ranking
 
-		score := cosineSimilarity(result.Data, embedding.Data)
+	for i, input := range inputs {
+		score := cosineSimilarity(embedding.Embeddings[0], embedding.Embeddings[i+1])
 
 		result := provider.Ranking{
+			Index: i,
+
 			Content: input,
 			Score:   float64(score),
 		}
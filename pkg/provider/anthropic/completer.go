@@ -200,6 +200,26 @@ func (c *Completer) convertMessageRequest(input []provider.Message, options *pro
 		req.Temperature = anthropic.F(float64(*options.Temperature))
 	}
 
+	if options.TopP != nil {
+		req.TopP = anthropic.F(float64(*options.TopP))
+	}
+
+	if options.Seed != nil {
+		return nil, provider.UnsupportedOption("anthropic", "seed")
+	}
+
+	if options.PresencePenalty != nil || options.FrequencyPenalty != nil {
+		return nil, provider.UnsupportedOption("anthropic", "presence or frequency penalties")
+	}
+
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("anthropic", "multiple choices")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("anthropic", "logprobs")
+	}
+
 	for _, m := range input {
 		switch m.Role {
 		case provider.MessageRoleSystem:
@@ -272,8 +292,16 @@ func (c *Completer) convertMessageRequest(input []provider.Message, options *pro
 		req.System = anthropic.F(system)
 	}
 
+	if options.ToolChoice != nil && options.ToolChoice.Mode == provider.ToolChoiceModeNone {
+		tools = nil
+	}
+
 	if len(tools) > 0 {
 		req.Tools = anthropic.F(tools)
+
+		if options.ToolChoice != nil {
+			req.ToolChoice = anthropic.F(convertToolChoice(*options.ToolChoice))
+		}
 	}
 
 	if len(messages) > 0 {
@@ -283,6 +311,26 @@ func (c *Completer) convertMessageRequest(input []provider.Message, options *pro
 	return req, nil
 }
 
+func convertToolChoice(choice provider.ToolChoice) anthropic.ToolChoiceUnionParam {
+	switch choice.Mode {
+	case provider.ToolChoiceModeRequired:
+		return anthropic.ToolChoiceAnyParam{
+			Type: anthropic.F(anthropic.ToolChoiceAnyTypeAny),
+		}
+
+	case provider.ToolChoiceModeTool:
+		return anthropic.ToolChoiceToolParam{
+			Type: anthropic.F(anthropic.ToolChoiceToolTypeTool),
+			Name: anthropic.F(choice.Name),
+		}
+
+	default:
+		return anthropic.ToolChoiceAutoParam{
+			Type: anthropic.F(anthropic.ToolChoiceAutoTypeAuto),
+		}
+	}
+}
+
 func toContent(blocks []anthropic.ContentBlock) string {
 	for _, b := range blocks {
 		if b.Type != anthropic.ContentBlockTypeText {
@@ -0,0 +1,119 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func convertFields(t *testing.T, options *provider.CompleteOptions) (map[string]any, error) {
+	t.Helper()
+
+	c, err := NewCompleter("", "claude")
+	require.NoError(t, err)
+
+	req, err := c.convertMessageRequest([]provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "Hi",
+		},
+	}, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	delete(result, "model")
+	delete(result, "messages")
+
+	return result, nil
+}
+
+func TestConvertOptions(t *testing.T) {
+	fields, err := convertFields(t, &provider.CompleteOptions{
+		Stop: []string{"END"},
+
+		MaxTokens:   ptr(100),
+		Temperature: ptr[float32](0.5),
+		TopP:        ptr[float32](0.25),
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]any{
+		"stop_sequences": []any{"END"},
+
+		"max_tokens":  float64(100),
+		"temperature": 0.5,
+		"top_p":       0.25,
+	}, fields)
+}
+
+func TestConvertUnsupportedOptions(t *testing.T) {
+	for name, options := range map[string]*provider.CompleteOptions{
+		"seed":              {Seed: ptr(42)},
+		"presence penalty":  {PresencePenalty: ptr[float32](0.5)},
+		"frequency penalty": {FrequencyPenalty: ptr[float32](0.5)},
+		"choices":           {N: ptr(2)},
+		"logprobs":          {Logprobs: true},
+	} {
+		_, err := convertFields(t, options)
+
+		var perr *provider.Error
+		require.ErrorAs(t, err, &perr, name)
+		require.Equal(t, 400, perr.StatusCode, name)
+	}
+
+	// a single choice is what every request returns anyway
+	_, err := convertFields(t, &provider.CompleteOptions{N: ptr(1)})
+	require.NoError(t, err)
+}
+
+func TestConvertToolChoice(t *testing.T) {
+	tools := []provider.Tool{
+		{
+			Name: "weather",
+
+			Parameters: map[string]any{
+				"type": "object",
+			},
+		},
+	}
+
+	tests := []struct {
+		choice provider.ToolChoice
+		want   any
+	}{
+		{provider.ToolChoice{Mode: provider.ToolChoiceModeAuto}, map[string]any{"type": "auto"}},
+		{provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}, map[string]any{"type": "any"}},
+		{provider.ToolChoice{Mode: provider.ToolChoiceModeTool, Name: "weather"}, map[string]any{"type": "tool", "name": "weather"}},
+	}
+
+	for _, tt := range tests {
+		fields, err := convertFields(t, &provider.CompleteOptions{Tools: tools, ToolChoice: &tt.choice})
+		require.NoError(t, err)
+
+		require.Equal(t, tt.want, fields["tool_choice"], tt.choice.Mode)
+		require.Len(t, fields["tools"], 1)
+	}
+
+	// anthropic has no "none" choice, so the tools are not sent at all
+	fields, err := convertFields(t, &provider.CompleteOptions{Tools: tools, ToolChoice: &provider.ToolChoice{Mode: provider.ToolChoiceModeNone}})
+	require.NoError(t, err)
+
+	require.NotContains(t, fields, "tools")
+	require.NotContains(t, fields, "tool_choice")
+}
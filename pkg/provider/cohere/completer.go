@@ -199,9 +199,31 @@ func convertChatRequest(model string, messages []provider.Message, options *prov
 		MaxTokens:   options.MaxTokens,
 		Temperature: options.Temperature,
 
+		TopP: options.TopP,
+		Seed: options.Seed,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
+
 		StopSequences: options.Stop,
 	}
 
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("cohere", "multiple choices")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("cohere", "logprobs")
+	}
+
+	if options.ToolChoice != nil {
+		switch options.ToolChoice.Mode {
+		case provider.ToolChoiceModeNone, provider.ToolChoiceModeAuto:
+		default:
+			return nil, provider.UnsupportedOption("cohere", "forced tool choice")
+		}
+	}
+
 	for _, m := range messages[:len(messages)-1] {
 		message := Message{
 			Role:    convertMessageRole(m.Role),
@@ -256,6 +278,12 @@ type ChatRequest struct {
 	MaxTokens   *int     `json:"max_tokens,omitempty"`
 	Temperature *float32 `json:"temperature,omitempty"`
 
+	TopP *float32 `json:"p,omitempty"`
+	Seed *int     `json:"seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+
 	StopSequences  []string       `json:"stop_sequences,omitempty"`
 	ResponseFormat ResponseFormat `json:"response_format,omitempty"`
 }
@@ -37,15 +37,17 @@ func NewEmbedder(url, model string, options ...Option) (*Embedder, error) {
 	}, nil
 }
 
-func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embedding, error) {
+func (e *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	return provider.EmbedBatches(ctx, texts, options, 96, e.embed)
+}
+
+func (e *Embedder) embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
 	url, _ := url.JoinPath(e.url, "/v1/embed")
 
 	body := map[string]any{
 		"model": e.model,
 
-		"texts": []string{
-			content,
-		},
+		"texts": texts,
 
 		"input_type": "search_document",
 
@@ -54,6 +56,10 @@ func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embeddi
 		},
 	}
 
+	if options != nil && options.Dimensions != nil {
+		body["output_dimension"] = *options.Dimensions
+	}
+
 	req, _ := http.NewRequestWithContext(ctx, "POST", url, jsonReader(body))
 	req.Header.Set("Authorization", "Bearer "+e.token)
 	req.Header.Set("Content-Type", "application/json")
@@ -78,15 +84,27 @@ func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embeddi
 
 	floats := result.Embeddings["float"]
 
-	if len(floats) == 0 {
+	if len(floats) != len(texts) {
 		return nil, errors.New("invalid embeddings")
 	}
 
 	return &provider.Embedding{
-		Data: floats[0],
+		Embeddings: floats,
+
+		Usage: &provider.Usage{
+			InputTokens: result.Meta.BilledUnits.InputTokens,
+		},
 	}, nil
 }
 
 type EmbedResponse struct {
 	Embeddings map[string][][]float32 `json:"embeddings"`
+
+	Meta EmbedMeta `json:"meta"`
+}
+
+type EmbedMeta struct {
+	BilledUnits struct {
+		InputTokens int `json:"input_tokens"`
+	} `json:"billed_units"`
 }
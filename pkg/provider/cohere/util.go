@@ -3,19 +3,26 @@ package cohere
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"io"
 	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/provider"
 )
 
 func convertError(resp *http.Response) error {
 	data, _ := io.ReadAll(resp.Body)
 
 	if len(data) == 0 {
-		return errors.New(http.StatusText(resp.StatusCode))
+		return &provider.Error{
+			StatusCode: resp.StatusCode,
+			Message:    http.StatusText(resp.StatusCode),
+		}
 	}
 
-	return errors.New(string(data))
+	return &provider.Error{
+		StatusCode: resp.StatusCode,
+		Message:    string(data),
+	}
 }
 
 func jsonReader(v any) io.Reader {
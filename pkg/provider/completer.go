@@ -30,6 +30,9 @@ const (
 type ToolCall struct {
 	ID string
 
+	// Index is the position of the call a streamed delta belongs to, if the provider reports it
+	Index *int
+
 	Name      string
 	Arguments string
 }
@@ -42,28 +45,89 @@ type CompleteOptions struct {
 	Stop  []string
 	Tools []Tool
 
+	ToolChoice *ToolChoice
+
 	MaxTokens   *int
 	Temperature *float32
+	TopP        *float32
+
+	Seed *int
+
+	PresencePenalty  *float32
+	FrequencyPenalty *float32
+
+	N *int
+
+	Logprobs    bool
+	TopLogprobs *int
 
 	Format CompletionFormat
+	Schema *Schema
+
+	// Suffix requests a fill-in-the-middle completion between the last message (prompt) and the suffix
+	Suffix string
 }
 
+type ToolChoice struct {
+	Mode ToolChoiceMode
+
+	// Name of the tool to call if Mode is ToolChoiceModeTool
+	Name string
+}
+
+type ToolChoiceMode string
+
+const (
+	ToolChoiceModeNone     ToolChoiceMode = "none"
+	ToolChoiceModeAuto     ToolChoiceMode = "auto"
+	ToolChoiceModeRequired ToolChoiceMode = "required"
+	ToolChoiceModeTool     ToolChoiceMode = "tool"
+)
+
 type Completion struct {
 	ID string
 
 	Reason CompletionReason
 
-	Message Message
+	Message  Message
+	Logprobs []Logprob
+
+	// all choices if more than one was requested (N > 1); the first one is mirrored in Reason, Message and Logprobs
+	Choices []CompletionChoice
 
 	Usage *Usage
 }
 
+type CompletionChoice struct {
+	Reason CompletionReason
+
+	Message  Message
+	Logprobs []Logprob
+}
+
+type Logprob struct {
+	Token   string
+	Logprob float64
+
+	TopLogprobs []Logprob
+}
+
 type CompletionFormat string
 
 const (
-	CompletionFormatJSON CompletionFormat = "json"
+	CompletionFormatJSON       CompletionFormat = "json"
+	CompletionFormatJSONSchema CompletionFormat = "json_schema"
 )
 
+type Schema struct {
+	Name        string
+	Description string
+
+	Strict bool
+
+	Schema map[string]any
+}
+
 type CompletionReason string
 
 const (
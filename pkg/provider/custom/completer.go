@@ -72,6 +72,30 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		req.Temperature = options.Temperature
 	}
 
+	if options.TopP != nil {
+		return nil, provider.UnsupportedOption("custom", "top_p")
+	}
+
+	if options.Seed != nil {
+		return nil, provider.UnsupportedOption("custom", "seed")
+	}
+
+	if options.PresencePenalty != nil || options.FrequencyPenalty != nil {
+		return nil, provider.UnsupportedOption("custom", "presence or frequency penalties")
+	}
+
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("custom", "multiple choices")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("custom", "logprobs")
+	}
+
+	if options.ToolChoice != nil && options.ToolChoice.Mode != provider.ToolChoiceModeNone && options.ToolChoice.Mode != provider.ToolChoiceModeAuto {
+		return nil, provider.UnsupportedOption("custom", "forced tool choice")
+	}
+
 	stream, err := c.client.Complete(ctx, req)
 
 	if err != nil {
@@ -2,6 +2,7 @@ package elevenlabs
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strings"
@@ -21,10 +22,14 @@ func NewSynthesizer(url, model string, options ...Option) (*Synthesizer, error)
 	cfg := &Config{
 		client: http.DefaultClient,
 
-		url:   "https://api.elevenlabs.io",
+		url:   url,
 		model: model,
 	}
 
+	if cfg.url == "" {
+		cfg.url = "https://api.elevenlabs.io"
+	}
+
 	for _, option := range options {
 		option(cfg)
 	}
@@ -34,19 +39,94 @@ func NewSynthesizer(url, model string, options ...Option) (*Synthesizer, error)
 	}, nil
 }
 
+func (s *Synthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	u, _ := url.JoinPath(s.url, "/v1/voices")
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", u, nil)
+	req.Header.Set("xi-api-key", s.token)
+
+	resp, err := s.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, convertError(resp)
+	}
+
+	var voices VoicesResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&voices); err != nil {
+		return nil, err
+	}
+
+	var result []provider.Voice
+
+	for _, v := range voices.Voices {
+		result = append(result, provider.Voice{
+			ID:   v.ID,
+			Name: v.Name,
+		})
+	}
+
+	return result, nil
+}
+
 func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
 	if options == nil {
 		options = new(provider.SynthesizeOptions)
 	}
 
-	u, _ := url.Parse(strings.TrimRight(s.url, "/") + "/v1/text-to-speech/" + s.model)
+	voice := s.model
+
+	if options.Voice != "" {
+		voice = options.Voice
+	}
+
+	format := provider.SpeechFormatMP3
+
+	if options.Format != "" {
+		format = options.Format
+	}
+
+	var outputFormat string
+
+	switch format {
+	case provider.SpeechFormatMP3:
+		outputFormat = "mp3_44100_128"
+
+	case provider.SpeechFormatOpus:
+		outputFormat = "opus_48000_128"
+
+	case provider.SpeechFormatPCM, provider.SpeechFormatWAV:
+		outputFormat = "pcm_24000"
+
+	default:
+		return nil, provider.UnsupportedOption("elevenlabs", "format "+string(format))
+	}
+
+	u, _ := url.Parse(strings.TrimRight(s.url, "/") + "/v1/text-to-speech/" + voice + "/stream")
+
+	query := u.Query()
+	query.Set("output_format", outputFormat)
+	u.RawQuery = query.Encode()
 
 	body := map[string]any{
 		"text":     content,
 		"model_id": "eleven_multilingual_v2",
 	}
 
+	if options.Speed != nil {
+		body["voice_settings"] = map[string]any{
+			"speed": *options.Speed,
+		}
+	}
+
 	req, _ := http.NewRequestWithContext(ctx, "POST", u.String(), jsonReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("xi-api-key", s.token)
 
 	resp, err := s.client.Do(req)
@@ -56,15 +136,29 @@ func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *p
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 		return nil, convertError(resp)
 	}
 
 	id := uuid.NewString()
 
-	return &provider.Synthesis{
+	result := &provider.Synthesis{
 		ID: id,
 
-		Name:    id + ".mp3",
+		Name:    id + "." + string(format),
 		Content: resp.Body,
-	}, nil
+	}
+
+	if format == provider.SpeechFormatWAV {
+		result.Content = wavReader(resp.Body, 24000)
+	}
+
+	return result, nil
+}
+
+type VoicesResponse struct {
+	Voices []struct {
+		ID   string `json:"voice_id"`
+		Name string `json:"name"`
+	} `json:"voices"`
 }
@@ -2,20 +2,28 @@ package elevenlabs
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
-	"errors"
 	"io"
 	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/provider"
 )
 
 func convertError(resp *http.Response) error {
 	data, _ := io.ReadAll(resp.Body)
 
 	if len(data) == 0 {
-		return errors.New(http.StatusText(resp.StatusCode))
+		return &provider.Error{
+			StatusCode: resp.StatusCode,
+			Message:    http.StatusText(resp.StatusCode),
+		}
 	}
 
-	return errors.New(string(data))
+	return &provider.Error{
+		StatusCode: resp.StatusCode,
+		Message:    string(data),
+	}
 }
 
 func jsonReader(v any) io.Reader {
@@ -27,3 +35,32 @@ func jsonReader(v any) io.Reader {
 	enc.Encode(v)
 	return b
 }
+
+// wavReader prefixes 16-bit mono pcm audio with a wav header of unknown length
+func wavReader(pcm io.ReadCloser, sampleRate int) io.ReadCloser {
+	header := new(bytes.Buffer)
+
+	header.WriteString("RIFF")
+	binary.Write(header, binary.LittleEndian, uint32(0xFFFFFFFF))
+	header.WriteString("WAVE")
+
+	header.WriteString("fmt ")
+	binary.Write(header, binary.LittleEndian, uint32(16))
+	binary.Write(header, binary.LittleEndian, uint16(1))
+	binary.Write(header, binary.LittleEndian, uint16(1))
+	binary.Write(header, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(header, binary.LittleEndian, uint32(sampleRate*2))
+	binary.Write(header, binary.LittleEndian, uint16(2))
+	binary.Write(header, binary.LittleEndian, uint16(16))
+
+	header.WriteString("data")
+	binary.Write(header, binary.LittleEndian, uint32(0xFFFFFFFF))
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		io.MultiReader(header, pcm),
+		pcm,
+	}
+}
@@ -5,11 +5,49 @@ import (
 )
 
 type Embedder interface {
-	Embed(ctx context.Context, content string) (*Embedding, error)
+	Embed(ctx context.Context, texts []string, options *EmbedOptions) (*Embedding, error)
+}
+
+type EmbedOptions struct {
+	Dimensions *int
 }
 
 type Embedding struct {
-	Data []float32
+	Embeddings [][]float32
 
 	Usage *Usage
 }
+
+// EmbedBatches splits texts into batches of at most size and embeds them one after another
+func EmbedBatches(ctx context.Context, texts []string, options *EmbedOptions, size int, embed func(ctx context.Context, texts []string, options *EmbedOptions) (*Embedding, error)) (*Embedding, error) {
+	if size <= 0 || len(texts) <= size {
+		return embed(ctx, texts, options)
+	}
+
+	result := &Embedding{
+		Embeddings: make([][]float32, 0, len(texts)),
+	}
+
+	for i := 0; i < len(texts); i += size {
+		j := min(i+size, len(texts))
+
+		embedding, err := embed(ctx, texts[i:j], options)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Embeddings = append(result.Embeddings, embedding.Embeddings...)
+
+		if embedding.Usage != nil {
+			if result.Usage == nil {
+				result.Usage = &Usage{}
+			}
+
+			result.Usage.InputTokens += embedding.Usage.InputTokens
+			result.Usage.OutputTokens += embedding.Usage.OutputTokens
+		}
+	}
+
+	return result, nil
+}
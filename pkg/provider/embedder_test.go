@@ -0,0 +1,106 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingEmbedder embeds texts as their number and fails on the batch of failAt, if set
+type countingEmbedder struct {
+	batches [][]string
+
+	failAt int
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	e.batches = append(e.batches, texts)
+
+	if e.failAt > 0 && len(e.batches) == e.failAt {
+		return nil, errors.New("batch failed")
+	}
+
+	result := &provider.Embedding{
+		Usage: &provider.Usage{
+			InputTokens: len(texts),
+		},
+	}
+
+	for _, text := range texts {
+		n, _ := strconv.Atoi(text)
+		result.Embeddings = append(result.Embeddings, []float32{float32(n)})
+	}
+
+	return result, nil
+}
+
+func numberTexts(n int) []string {
+	var result []string
+
+	for i := range n {
+		result = append(result, strconv.Itoa(i))
+	}
+
+	return result
+}
+
+func TestEmbedBatches(t *testing.T) {
+	e := &countingEmbedder{}
+
+	result, err := provider.EmbedBatches(context.Background(), numberTexts(7), nil, 3, e.Embed)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{{"0", "1", "2"}, {"3", "4", "5"}, {"6"}}, e.batches)
+
+	require.Len(t, result.Embeddings, 7)
+
+	for i, embedding := range result.Embeddings {
+		require.Equal(t, []float32{float32(i)}, embedding)
+	}
+
+	require.Equal(t, 7, result.Usage.InputTokens)
+}
+
+func TestEmbedBatchesWithinLimit(t *testing.T) {
+	for _, size := range []int{0, 3, 4} {
+		e := &countingEmbedder{}
+
+		result, err := provider.EmbedBatches(context.Background(), numberTexts(3), nil, size, e.Embed)
+		require.NoError(t, err)
+
+		require.Len(t, e.batches, 1)
+		require.Len(t, result.Embeddings, 3)
+	}
+}
+
+func TestEmbedBatchesOptions(t *testing.T) {
+	dimensions := 256
+	options := &provider.EmbedOptions{Dimensions: &dimensions}
+
+	var received []*provider.EmbedOptions
+
+	_, err := provider.EmbedBatches(context.Background(), numberTexts(4), options, 2, func(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+		received = append(received, options)
+		return &provider.Embedding{Embeddings: make([][]float32, len(texts))}, nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []*provider.EmbedOptions{options, options}, received)
+}
+
+func TestEmbedBatchesFailure(t *testing.T) {
+	e := &countingEmbedder{failAt: 2}
+
+	result, err := provider.EmbedBatches(context.Background(), numberTexts(7), nil, 3, e.Embed)
+
+	require.EqualError(t, err, "batch failed")
+	require.Nil(t, result)
+
+	// batches after the failed one are not sent
+	require.Len(t, e.batches, 2)
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type Error struct {
+	StatusCode int
+
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func UnsupportedOption(provider, option string) error {
+	return &Error{
+		StatusCode: http.StatusBadRequest,
+		Message:    fmt.Sprintf("%s does not support %s", provider, option),
+	}
+}
@@ -80,19 +80,40 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 			return nil, err
 		}
 
-		candidate := response.Candidates[0]
+		result := &provider.Completion{
+			ID: uuid.New().String(),
+		}
 
-		return &provider.Completion{
-			ID:     uuid.New().String(),
-			Reason: toCompletionResult(candidate.FinishReason),
+		for _, candidate := range response.Candidates {
+			choice := provider.CompletionChoice{
+				Reason: toCompletionResult(candidate.FinishReason),
 
-			Message: provider.Message{
-				Role:    provider.MessageRoleAssistant,
-				Content: toContent(candidate.Content),
+				Message: provider.Message{
+					Role:    provider.MessageRoleAssistant,
+					Content: toContent(candidate.Content),
 
-				ToolCalls: toToolCalls(candidate.Content),
-			},
-		}, nil
+					ToolCalls: toToolCalls(candidate.Content),
+				},
+
+				Logprobs: toLogprobs(candidate.Logprobs),
+			}
+
+			result.Choices = append(result.Choices, choice)
+		}
+
+		if len(result.Choices) > 0 {
+			result.Reason = result.Choices[0].Reason
+			result.Message = result.Choices[0].Message
+			result.Logprobs = result.Choices[0].Logprobs
+		}
+
+		if len(result.Choices) < 2 {
+			result.Choices = nil
+		}
+
+		result.Usage = toUsage(response.UsageMetadata)
+
+		return result, nil
 	} else {
 		url, _ := url.JoinPath(c.url, "/v1beta/models/"+c.model+":streamGenerateContent")
 		url += "?alt=sse"
@@ -124,8 +145,6 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 			Message: provider.Message{
 				Role: provider.MessageRoleAssistant,
 			},
-
-			//Usage: &provider.Usage{},
 		}
 
 		resultToolCalls := map[string]provider.ToolCall{}
@@ -160,6 +179,15 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 				return nil, err
 			}
 
+			// every event reports the usage so far
+			if usage := toUsage(event.UsageMetadata); usage != nil {
+				result.Usage = usage
+			}
+
+			if len(event.Candidates) == 0 {
+				continue
+			}
+
 			candidate := event.Candidates[0]
 
 			content := toContent(candidate.Content)
@@ -290,6 +318,63 @@ func convertGenerateRequest(messages []provider.Message, options *provider.Compl
 		}
 	}
 
+	if options.ToolChoice != nil {
+		config := &FunctionCallingConfig{
+			Mode: FunctionCallingModeAuto,
+		}
+
+		switch options.ToolChoice.Mode {
+		case provider.ToolChoiceModeNone:
+			config.Mode = FunctionCallingModeNone
+
+		case provider.ToolChoiceModeRequired:
+			config.Mode = FunctionCallingModeAny
+
+		case provider.ToolChoiceModeTool:
+			config.Mode = FunctionCallingModeAny
+			config.AllowedFunctionNames = []string{options.ToolChoice.Name}
+		}
+
+		req.ToolConfig = &ToolConfig{
+			FunctionCallingConfig: config,
+		}
+	}
+
+	if options.N != nil && *options.N > 1 && options.Stream != nil {
+		return nil, provider.UnsupportedOption("google", "multiple choices when streaming")
+	}
+
+	config := &GenerationConfig{
+		StopSequences: options.Stop,
+
+		MaxOutputTokens: options.MaxTokens,
+		Temperature:     options.Temperature,
+		TopP:            options.TopP,
+
+		Seed: options.Seed,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
+
+		CandidateCount: options.N,
+	}
+
+	if options.Logprobs {
+		config.ResponseLogprobs = true
+		config.Logprobs = options.TopLogprobs
+	}
+
+	if options.Format == provider.CompletionFormatJSON {
+		config.ResponseMimeType = "application/json"
+	}
+
+	if options.Format == provider.CompletionFormatJSONSchema && options.Schema != nil {
+		config.ResponseMimeType = "application/json"
+		config.ResponseSchema = convertSchema(options.Schema.Schema, options.Schema.Schema)
+	}
+
+	req.GenerationConfig = config
+
 	return req, nil
 }
 
@@ -304,7 +389,49 @@ var (
 type GenerateRequest struct {
 	Contents []Content `json:"contents"`
 
-	Tools []Tool `json:"tools,omitempty"`
+	Tools      []Tool      `json:"tools,omitempty"`
+	ToolConfig *ToolConfig `json:"toolConfig,omitempty"`
+
+	GenerationConfig *GenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type GenerationConfig struct {
+	StopSequences []string `json:"stopSequences,omitempty"`
+
+	MaxOutputTokens *int     `json:"maxOutputTokens,omitempty"`
+	Temperature     *float32 `json:"temperature,omitempty"`
+	TopP            *float32 `json:"topP,omitempty"`
+
+	Seed *int `json:"seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presencePenalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequencyPenalty,omitempty"`
+
+	CandidateCount *int `json:"candidateCount,omitempty"`
+
+	ResponseLogprobs bool `json:"responseLogprobs,omitempty"`
+	Logprobs         *int `json:"logprobs,omitempty"`
+
+	ResponseMimeType string         `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]any `json:"responseSchema,omitempty"`
+}
+
+type ToolConfig struct {
+	FunctionCallingConfig *FunctionCallingConfig `json:"functionCallingConfig,omitempty"`
+}
+
+type FunctionCallingMode string
+
+var (
+	FunctionCallingModeAuto FunctionCallingMode = "AUTO"
+	FunctionCallingModeAny  FunctionCallingMode = "ANY"
+	FunctionCallingModeNone FunctionCallingMode = "NONE"
+)
+
+type FunctionCallingConfig struct {
+	Mode FunctionCallingMode `json:"mode"`
+
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
 }
 
 type Content struct {
@@ -350,6 +477,8 @@ type FunctionDeclaration struct {
 
 type GenerateResponse struct {
 	Candidates []Candidate `json:"candidates"`
+
+	UsageMetadata *UsageMetadata `json:"usageMetadata,omitempty"`
 }
 
 type Candidate struct {
@@ -358,6 +487,22 @@ type Candidate struct {
 	FinishReason FinishReason `json:"finishReason"`
 
 	Content Content `json:"content"`
+
+	Logprobs *LogprobsResult `json:"logprobsResult,omitempty"`
+}
+
+type LogprobsResult struct {
+	TopCandidates    []TopCandidates     `json:"topCandidates"`
+	ChosenCandidates []LogprobsCandidate `json:"chosenCandidates"`
+}
+
+type TopCandidates struct {
+	Candidates []LogprobsCandidate `json:"candidates"`
+}
+
+type LogprobsCandidate struct {
+	Token          string  `json:"token"`
+	LogProbability float64 `json:"logProbability"`
 }
 
 type FinishReason string
@@ -372,6 +517,17 @@ type UsageMetadata struct {
 	TotalTokenCount      int `json:"totalTokenCount"`
 }
 
+func toUsage(metadata *UsageMetadata) *provider.Usage {
+	if metadata == nil {
+		return nil
+	}
+
+	return &provider.Usage{
+		InputTokens:  metadata.PromptTokenCount,
+		OutputTokens: metadata.CandidatesTokenCount,
+	}
+}
+
 func toContent(content Content) string {
 	for _, p := range content.Parts {
 		if p.Text == "" {
@@ -407,6 +563,94 @@ func toToolCalls(content Content) []provider.ToolCall {
 	return result
 }
 
+// https://ai.google.dev/api/caching#Schema
+// Gemini accepts an OpenAPI subset only: local references are inlined and unknown keywords dropped
+func convertSchema(root, schema map[string]any) map[string]any {
+	if ref, ok := schema["$ref"].(string); ok {
+		var current any = root
+
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			if m, ok := current.(map[string]any); ok {
+				current = m[part]
+			}
+		}
+
+		if resolved, ok := current.(map[string]any); ok {
+			return convertSchema(root, resolved)
+		}
+	}
+
+	result := map[string]any{}
+
+	for key, val := range schema {
+		switch key {
+		case "type":
+			if types, ok := val.([]any); ok {
+				for _, t := range types {
+					if t == "null" {
+						result["nullable"] = true
+					} else {
+						result["type"] = t
+					}
+				}
+			} else {
+				result["type"] = val
+			}
+
+		case "format", "description", "nullable", "enum", "required", "minItems", "maxItems", "propertyOrdering":
+			result[key] = val
+
+		case "items":
+			if items, ok := val.(map[string]any); ok {
+				result[key] = convertSchema(root, items)
+			}
+
+		case "properties":
+			if properties, ok := val.(map[string]any); ok {
+				converted := map[string]any{}
+
+				for name, p := range properties {
+					if p, ok := p.(map[string]any); ok {
+						converted[name] = convertSchema(root, p)
+					}
+				}
+
+				result[key] = converted
+			}
+		}
+	}
+
+	return result
+}
+
+func toLogprobs(result *LogprobsResult) []provider.Logprob {
+	if result == nil {
+		return nil
+	}
+
+	var logprobs []provider.Logprob
+
+	for i, c := range result.ChosenCandidates {
+		logprob := provider.Logprob{
+			Token:   c.Token,
+			Logprob: c.LogProbability,
+		}
+
+		if i < len(result.TopCandidates) {
+			for _, t := range result.TopCandidates[i].Candidates {
+				logprob.TopLogprobs = append(logprob.TopLogprobs, provider.Logprob{
+					Token:   t.Token,
+					Logprob: t.LogProbability,
+				})
+			}
+		}
+
+		logprobs = append(logprobs, logprob)
+	}
+
+	return logprobs
+}
+
 func toCompletionResult(val FinishReason) provider.CompletionReason {
 	switch val {
 	case FinishReasonStop:
@@ -3,6 +3,7 @@ package google
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
 
@@ -32,20 +33,28 @@ func NewEmbedder(url, model string, options ...Option) (*Embedder, error) {
 	}, nil
 }
 
-func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embedding, error) {
-	body := EmbedRequest{
-		Model: e.model,
+func (e *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	return provider.EmbedBatches(ctx, texts, options, 100, e.embed)
+}
+
+func (e *Embedder) embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	var body BatchEmbedRequest
+
+	for _, text := range texts {
+		body.Requests = append(body.Requests, EmbedRequest{
+			Model: "models/" + e.model,
 
-		Content: Content{
-			Parts: []ContentPart{
-				{
-					Text: content,
+			Content: Content{
+				Parts: []ContentPart{
+					{
+						Text: text,
+					},
 				},
 			},
-		},
+		})
 	}
 
-	url, _ := url.JoinPath(e.url, "/v1beta/models/"+e.model+":embedContent")
+	url, _ := url.JoinPath(e.url, "/v1beta/models/"+e.model+":batchEmbedContents")
 
 	if e.token != "" {
 		url += "?key=" + e.token
@@ -66,24 +75,38 @@ func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embeddi
 		return nil, convertError(resp)
 	}
 
-	var result EmbedResponse
+	var result BatchEmbedResponse
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
+	if len(result.Embeddings) != len(texts) {
+		return nil, errors.New("invalid embeddings")
+	}
+
+	var embeddings [][]float32
+
+	for _, d := range result.Embeddings {
+		embeddings = append(embeddings, d.Values)
+	}
+
 	return &provider.Embedding{
-		Data: result.Embedding.Values,
+		Embeddings: embeddings,
 	}, nil
 }
 
+type BatchEmbedRequest struct {
+	Requests []EmbedRequest `json:"requests"`
+}
+
 type EmbedRequest struct {
 	Model   string  `json:"model"`
 	Content Content `json:"content"`
 }
 
-type EmbedResponse struct {
-	Embedding Embedding `json:"embedding"`
+type BatchEmbedResponse struct {
+	Embeddings []Embedding `json:"embeddings"`
 }
 
 type Embedding struct {
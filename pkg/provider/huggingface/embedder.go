@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
 	"strings"
 
@@ -42,9 +41,19 @@ func NewEmbedder(url, model string, options ...Option) (*Embedder, error) {
 	}, nil
 }
 
-func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embedding, error) {
+func (e *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	return provider.EmbedBatches(ctx, texts, options, 32, e.embed)
+}
+
+func (e *Embedder) embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	inputs := make([]string, len(texts))
+
+	for i, text := range texts {
+		inputs[i] = strings.TrimSpace(text)
+	}
+
 	body := map[string]any{
-		"inputs": strings.TrimSpace(content),
+		"inputs": inputs,
 	}
 
 	req, _ := http.NewRequestWithContext(ctx, "POST", e.url, jsonReader(body))
@@ -66,27 +75,13 @@ func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embeddi
 		return nil, convertError(resp)
 	}
 
-	data, err := io.ReadAll(resp.Body)
-
-	if err != nil {
-		return nil, err
-	}
-
-	var result1 []float32
+	var result [][]float32
 
-	if err := json.Unmarshal(data, &result1); err == nil {
-		return &provider.Embedding{
-			Data: result1,
-		}, nil
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result) != len(texts) {
+		return nil, errors.New("unable to embed input")
 	}
 
-	var result2 [][]float32
-
-	if err := json.Unmarshal(data, &result2); err == nil && len(result2) > 0 {
-		return &provider.Embedding{
-			Data: result2[0],
-		}, nil
-	}
-
-	return nil, errors.New("unable to embed input")
+	return &provider.Embedding{
+		Embeddings: result,
+	}, nil
 }
@@ -45,11 +45,23 @@ func NewEmbedder(url string, model string, options ...Option) (*Embedder, error)
 	}, nil
 }
 
-func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embedding, error) {
+func (e *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	return provider.EmbedBatches(ctx, texts, options, 2048, e.embed)
+}
+
+func (e *Embedder) embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	input := make([]string, len(texts))
+
+	for i, text := range texts {
+		input[i] = strings.TrimSpace(text)
+	}
+
 	body := map[string]any{
-		"input": []string{
-			strings.TrimSpace(content),
-		},
+		"input": input,
+	}
+
+	if options != nil && options.Dimensions != nil {
+		body["dimensions"] = *options.Dimensions
 	}
 
 	u, _ := url.JoinPath(e.url, "/v1/embeddings")
@@ -79,12 +91,24 @@ func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embeddi
 		return nil, err
 	}
 
-	if len(result.Data) == 0 {
+	if len(result.Data) != len(texts) {
 		return nil, errors.New("no embeddings found")
 	}
 
+	embeddings := make([][]float32, len(texts))
+
+	for _, d := range result.Data {
+		if d.Index < len(embeddings) {
+			embeddings[d.Index] = d.Embedding
+		}
+	}
+
 	return &provider.Embedding{
-		Data: result.Data[0].Embedding,
+		Embeddings: embeddings,
+
+		Usage: &provider.Usage{
+			InputTokens: result.Usage.PromptTokens,
+		},
 	}, nil
 }
 
@@ -93,6 +117,13 @@ type EmbeddingList struct {
 
 	Model string      `json:"model"`
 	Data  []Embedding `json:"data"`
+
+	Usage Usage `json:"usage"`
+}
+
+type Usage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
 }
 
 type Embedding struct {
@@ -96,6 +96,8 @@ func (r *Reranker) Rerank(ctx context.Context, query string, inputs []string, op
 
 	for _, r := range data.Results {
 		result = append(result, provider.Ranking{
+			Index: r.Index,
+
 			Content: inputs[r.Index],
 			Score:   r.Score,
 		})
@@ -1,13 +1,27 @@
 package llama
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
 	"strings"
 
+	"github.com/adrianliechti/llama/pkg/provider"
 	"github.com/adrianliechti/llama/pkg/provider/openai"
 )
 
-type Completer = openai.Completer
+var _ provider.Completer = (*Completer)(nil)
+
+type Completer struct {
+	url    string
+	client *http.Client
+
+	completer *openai.Completer
+}
 
 func NewCompleter(url, model string, options ...Option) (*Completer, error) {
 	if url == "" {
@@ -17,17 +31,242 @@ func NewCompleter(url, model string, options ...Option) (*Completer, error) {
 	url = strings.TrimRight(url, "/")
 	url = strings.TrimSuffix(url, "/v1")
 
-	cfg := &Config{}
+	cfg := &Config{
+		client: http.DefaultClient,
+	}
 
 	for _, option := range options {
 		option(cfg)
 	}
 
-	opts := []openai.Option{}
+	opts := []openai.Option{
+		openai.WithClient(cfg.client),
+	}
+
+	completer, err := openai.NewCompleter(url+"/v1", model, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completer{
+		url:    url,
+		client: cfg.client,
+
+		completer: completer,
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil || options.Suffix == "" {
+		return c.completer.Complete(ctx, messages, options)
+	}
+
+	body, err := convertInfillRequest(messages, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", c.url+"/infill", jsonReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, convertError(resp)
+	}
+
+	if options.Stream == nil {
+		var infill InfillResponse
+
+		if err := json.NewDecoder(resp.Body).Decode(&infill); err != nil {
+			return nil, err
+		}
+
+		return toCompletion(infill, infill.Content), nil
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	var content strings.Builder
+	var last InfillResponse
+
+	for {
+		data, err := reader.ReadString('\n')
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		data = strings.TrimSpace(data)
+
+		if !strings.HasPrefix(data, "data:") {
+			continue
+		}
+
+		data = strings.TrimSpace(strings.TrimPrefix(data, "data:"))
+
+		if len(data) == 0 {
+			continue
+		}
+
+		var infill InfillResponse
+
+		if err := json.Unmarshal([]byte(data), &infill); err != nil {
+			return nil, err
+		}
+
+		last = infill
+
+		content.WriteString(infill.Content)
+
+		completion := provider.Completion{
+			Message: provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: infill.Content,
+			},
+		}
+
+		if infill.Stop {
+			final := toCompletion(infill, infill.Content)
+
+			completion.Reason = final.Reason
+			completion.Usage = final.Usage
+		} else if infill.Content == "" {
+			continue
+		}
+
+		if err := options.Stream(ctx, completion); err != nil {
+			return nil, err
+		}
+	}
+
+	return toCompletion(last, content.String()), nil
+}
+
+func convertInfillRequest(messages []provider.Message, options *provider.CompleteOptions) (*InfillRequest, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("missing prompt")
+	}
+
+	if len(options.Tools) > 0 {
+		return nil, provider.UnsupportedOption("llama", "tools with suffix")
+	}
+
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("llama", "multiple choices with suffix")
+	}
 
-	if cfg.client != nil {
-		opts = append(opts, openai.WithClient(cfg.client))
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("llama", "logprobs with suffix")
 	}
 
-	return openai.NewCompleter(url+"/v1", model, opts...)
+	req := &InfillRequest{
+		InputPrefix: messages[len(messages)-1].Content,
+		InputSuffix: options.Suffix,
+
+		Stream: options.Stream != nil,
+
+		Stop: options.Stop,
+
+		MaxTokens:   options.MaxTokens,
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		Seed: options.Seed,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
+	}
+
+	return req, nil
+}
+
+type InfillRequest struct {
+	InputPrefix string `json:"input_prefix"`
+	InputSuffix string `json:"input_suffix"`
+
+	Stream bool `json:"stream"`
+
+	Stop []string `json:"stop,omitempty"`
+
+	MaxTokens   *int     `json:"n_predict,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+
+	Seed *int `json:"seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+}
+
+type InfillResponse struct {
+	Content string `json:"content"`
+
+	Stop         bool `json:"stop"`
+	StoppedLimit bool `json:"stopped_limit"`
+
+	TokensEvaluated int `json:"tokens_evaluated"`
+	TokensPredicted int `json:"tokens_predicted"`
+}
+
+func toCompletion(infill InfillResponse, content string) *provider.Completion {
+	result := &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: content,
+		},
+	}
+
+	if infill.StoppedLimit {
+		result.Reason = provider.CompletionReasonLength
+	}
+
+	if infill.TokensEvaluated > 0 || infill.TokensPredicted > 0 {
+		result.Usage = &provider.Usage{
+			InputTokens:  infill.TokensEvaluated,
+			OutputTokens: infill.TokensPredicted,
+		}
+	}
+
+	return result
+}
+
+func convertError(resp *http.Response) error {
+	data, _ := io.ReadAll(resp.Body)
+
+	if len(data) == 0 {
+		return &provider.Error{
+			StatusCode: resp.StatusCode,
+			Message:    http.StatusText(resp.StatusCode),
+		}
+	}
+
+	return &provider.Error{
+		StatusCode: resp.StatusCode,
+		Message:    string(data),
+	}
+}
+
+func jsonReader(v any) io.Reader {
+	b := new(bytes.Buffer)
+
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(v)
+	return b
 }
@@ -53,8 +53,9 @@ func TestEmbedder(t *testing.T) {
 	e, err := llama.NewEmbedder("http://"+url, "default")
 	require.NoError(t, err)
 
-	result, err := e.Embed(ctx, "Hallo!")
+	result, err := e.Embed(ctx, []string{"Hallo!", "Hello!"}, nil)
 	require.NoError(t, err)
 
-	require.NotEmpty(t, result.Data)
+	require.Len(t, result.Embeddings, 2)
+	require.NotEmpty(t, result.Embeddings[0])
 }
@@ -42,13 +42,14 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		options = new(provider.CompleteOptions)
 	}
 
-	url, _ := url.JoinPath(c.url, "/v1/chat/completions")
-	body, err := convertCompletionRequest(c.model, messages, options)
+	path, body, err := convertRequest(c.model, messages, options)
 
 	if err != nil {
 		return nil, err
 	}
 
+	url, _ := url.JoinPath(c.url, path)
+
 	if options.Stream == nil {
 		req, _ := http.NewRequestWithContext(ctx, "POST", url, jsonReader(body))
 		req.Header.Set("Authorization", "Bearer "+c.token)
@@ -72,22 +73,38 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 			return nil, err
 		}
 
-		choice := completion.Choices[0]
-
-		return &provider.Completion{
-			ID:     completion.ID,
-			Reason: toCompletionReason(choice.FinishReason),
-
-			Message: provider.Message{
-				Role:    provider.MessageRoleAssistant,
-				Content: choice.Message.Content,
-			},
+		result := &provider.Completion{
+			ID: completion.ID,
 
 			Usage: &provider.Usage{
 				InputTokens:  completion.Usage.PromptTokens,
 				OutputTokens: completion.Usage.CompletionTokens,
 			},
-		}, nil
+		}
+
+		for _, c := range completion.Choices {
+			choice := provider.CompletionChoice{
+				Reason: toCompletionReason(c.FinishReason),
+
+				Message: provider.Message{
+					Role:    provider.MessageRoleAssistant,
+					Content: c.Message.Content,
+				},
+			}
+
+			result.Choices = append(result.Choices, choice)
+		}
+
+		if len(result.Choices) > 0 {
+			result.Reason = result.Choices[0].Reason
+			result.Message = result.Choices[0].Message
+		}
+
+		if len(result.Choices) < 2 {
+			result.Choices = nil
+		}
+
+		return result, nil
 	} else {
 		req, _ := http.NewRequestWithContext(ctx, "POST", url, jsonReader(body))
 		req.Header.Set("Authorization", "Bearer "+c.token)
@@ -188,6 +205,53 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 	}
 }
 
+func convertRequest(model string, messages []provider.Message, options *provider.CompleteOptions) (string, any, error) {
+	if options.Suffix != "" {
+		req, err := convertFIMRequest(model, messages, options)
+		return "/v1/fim/completions", req, err
+	}
+
+	req, err := convertCompletionRequest(model, messages, options)
+	return "/v1/chat/completions", req, err
+}
+
+func convertFIMRequest(model string, messages []provider.Message, options *provider.CompleteOptions) (*FIMCompletionRequest, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("missing prompt")
+	}
+
+	if len(options.Tools) > 0 {
+		return nil, provider.UnsupportedOption("mistral", "tools with suffix")
+	}
+
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("mistral", "multiple choices with suffix")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("mistral", "logprobs")
+	}
+
+	req := &FIMCompletionRequest{
+		Model: model,
+
+		Prompt: messages[len(messages)-1].Content,
+		Suffix: options.Suffix,
+
+		Stream: options.Stream != nil,
+
+		Stop: options.Stop,
+
+		MaxTokens:   options.MaxTokens,
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		Seed: options.Seed,
+	}
+
+	return req, nil
+}
+
 func convertCompletionRequest(model string, messages []provider.Message, options *provider.CompleteOptions) (*ChatCompletionRequest, error) {
 	if options == nil {
 		options = new(provider.CompleteOptions)
@@ -200,6 +264,52 @@ func convertCompletionRequest(model string, messages []provider.Message, options
 
 		MaxTokens:   options.MaxTokens,
 		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		Seed: options.Seed,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
+
+		N: options.N,
+	}
+
+	if options.N != nil && *options.N > 1 && options.Stream != nil {
+		return nil, provider.UnsupportedOption("mistral", "multiple choices when streaming")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("mistral", "logprobs")
+	}
+
+	if options.Format == provider.CompletionFormatJSON {
+		req.ResponseFormat = &ResponseFormat{
+			Type: ResponseFormatJSON,
+		}
+	}
+
+	if options.Format == provider.CompletionFormatJSONSchema && options.Schema != nil {
+		req.ResponseFormat = &ResponseFormat{
+			Type: ResponseFormatJSONSchema,
+
+			JSONSchema: &JSONSchema{
+				Name:        options.Schema.Name,
+				Description: options.Schema.Description,
+
+				Strict: options.Schema.Strict,
+				Schema: options.Schema.Schema,
+			},
+		}
+	}
+
+	if options.ToolChoice != nil {
+		switch options.ToolChoice.Mode {
+		case provider.ToolChoiceModeNone, provider.ToolChoiceModeAuto:
+			req.ToolChoice = string(options.ToolChoice.Mode)
+
+		default:
+			return nil, provider.UnsupportedOption("mistral", "forced tool choice")
+		}
 	}
 
 	for _, m := range messages {
@@ -223,6 +333,57 @@ type ChatCompletionRequest struct {
 
 	MaxTokens   *int     `json:"max_tokens,omitempty"`
 	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+
+	Seed *int `json:"random_seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+
+	N *int `json:"n,omitempty"`
+
+	ToolChoice string `json:"tool_choice,omitempty"`
+
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+type FIMCompletionRequest struct {
+	Model string `json:"model"`
+
+	Prompt string `json:"prompt"`
+	Suffix string `json:"suffix,omitempty"`
+
+	Stream bool `json:"stream"`
+
+	Stop []string `json:"stop,omitempty"`
+
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+
+	Seed *int `json:"random_seed,omitempty"`
+}
+
+type ResponseFormatType string
+
+var (
+	ResponseFormatText       ResponseFormatType = "text"
+	ResponseFormatJSON       ResponseFormatType = "json_object"
+	ResponseFormatJSONSchema ResponseFormatType = "json_schema"
+)
+
+type ResponseFormat struct {
+	Type ResponseFormatType `json:"type"`
+
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+type JSONSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Strict bool           `json:"strict,omitempty"`
+	Schema map[string]any `json:"schema"`
 }
 
 type ChatCompletionResponse struct {
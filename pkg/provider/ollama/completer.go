@@ -1,7 +1,13 @@
 package ollama
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 	"strings"
 
 	"github.com/adrianliechti/llama/pkg/provider"
@@ -11,6 +17,10 @@ import (
 var _ provider.Completer = (*Completer)(nil)
 
 type Completer struct {
+	url    string
+	model  string
+	client *http.Client
+
 	completer *openai.Completer
 }
 
@@ -22,16 +32,16 @@ func NewCompleter(url, model string, options ...Option) (*Completer, error) {
 	url = strings.TrimRight(url, "/")
 	url = strings.TrimSuffix(url, "/v1")
 
-	cfg := &Config{}
+	cfg := &Config{
+		client: http.DefaultClient,
+	}
 
 	for _, option := range options {
 		option(cfg)
 	}
 
-	opts := []openai.Option{}
-
-	if cfg.client != nil {
-		opts = append(opts, openai.WithClient(cfg.client))
+	opts := []openai.Option{
+		openai.WithClient(cfg.client),
 	}
 
 	completer, err := openai.NewCompleter(url+"/v1", model, opts...)
@@ -41,6 +51,10 @@ func NewCompleter(url, model string, options ...Option) (*Completer, error) {
 	}
 
 	return &Completer{
+		url:    url,
+		model:  model,
+		client: cfg.client,
+
 		completer: completer,
 	}, nil
 }
@@ -50,19 +64,54 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		options = new(provider.CompleteOptions)
 	}
 
+	if options.N != nil && *options.N > 1 {
+		return nil, provider.UnsupportedOption("ollama", "multiple choices")
+	}
+
+	if options.Logprobs {
+		return nil, provider.UnsupportedOption("ollama", "logprobs")
+	}
+
+	tools := options.Tools
+
+	if options.ToolChoice != nil {
+		switch options.ToolChoice.Mode {
+		case provider.ToolChoiceModeAuto:
+			// default behavior
+
+		case provider.ToolChoiceModeNone:
+			// ollama has no tool choice, not offering any tools has the same effect
+			tools = nil
+
+		default:
+			return nil, provider.UnsupportedOption("ollama", "tool choice")
+		}
+	}
+
+	if options.Suffix != "" {
+		return c.generate(ctx, messages, tools, options)
+	}
+
 	inputOptions := &provider.CompleteOptions{
 		Stream: options.Stream,
 
 		Stop:  options.Stop,
-		Tools: options.Tools,
+		Tools: tools,
 
 		MaxTokens:   options.MaxTokens,
 		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		Seed: options.Seed,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
 
 		Format: options.Format,
+		Schema: options.Schema,
 	}
 
-	if len(options.Tools) > 0 {
+	if len(tools) > 0 {
 		inputOptions.Stream = nil
 	}
 
@@ -80,3 +129,190 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 
 	return result, nil
 }
+
+func (c *Completer) generate(ctx context.Context, messages []provider.Message, tools []provider.Tool, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("missing prompt")
+	}
+
+	if len(tools) > 0 {
+		return nil, provider.UnsupportedOption("ollama", "tools with suffix")
+	}
+
+	body := &GenerateRequest{
+		Model: c.model,
+
+		Prompt: messages[len(messages)-1].Content,
+		Suffix: options.Suffix,
+
+		Stream: options.Stream != nil,
+
+		Options: &GenerateOptions{
+			Stop: options.Stop,
+
+			MaxTokens:   options.MaxTokens,
+			Temperature: options.Temperature,
+			TopP:        options.TopP,
+
+			Seed: options.Seed,
+
+			PresencePenalty:  options.PresencePenalty,
+			FrequencyPenalty: options.FrequencyPenalty,
+		},
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", c.url+"/api/generate", jsonReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, convertError(resp)
+	}
+
+	result := &provider.Completion{
+		Message: provider.Message{
+			Role: provider.MessageRoleAssistant,
+		},
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	for {
+		data, err := reader.ReadBytes('\n')
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			var chunk GenerateResponse
+
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return nil, err
+			}
+
+			if chunk.Error != "" {
+				return nil, errors.New(chunk.Error)
+			}
+
+			result.Message.Content += chunk.Response
+
+			if chunk.Response != "" && options.Stream != nil {
+				completion := provider.Completion{
+					Message: provider.Message{
+						Role:    provider.MessageRoleAssistant,
+						Content: chunk.Response,
+					},
+				}
+
+				if err := options.Stream(ctx, completion); err != nil {
+					return nil, err
+				}
+			}
+
+			if chunk.Done {
+				result.Reason = provider.CompletionReasonStop
+
+				if chunk.DoneReason == "length" {
+					result.Reason = provider.CompletionReasonLength
+				}
+
+				result.Usage = &provider.Usage{
+					InputTokens:  chunk.PromptEvalCount,
+					OutputTokens: chunk.EvalCount,
+				}
+
+				if options.Stream != nil {
+					completion := provider.Completion{
+						Reason: result.Reason,
+
+						Message: provider.Message{
+							Role: provider.MessageRoleAssistant,
+						},
+
+						Usage: result.Usage,
+					}
+
+					if err := options.Stream(ctx, completion); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+type GenerateRequest struct {
+	Model string `json:"model"`
+
+	Prompt string `json:"prompt"`
+	Suffix string `json:"suffix,omitempty"`
+
+	Stream bool `json:"stream"`
+
+	Options *GenerateOptions `json:"options,omitempty"`
+}
+
+type GenerateOptions struct {
+	Stop []string `json:"stop,omitempty"`
+
+	MaxTokens   *int     `json:"num_predict,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+
+	Seed *int `json:"seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+}
+
+type GenerateResponse struct {
+	Response string `json:"response"`
+
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason"`
+
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+
+	Error string `json:"error"`
+}
+
+func convertError(resp *http.Response) error {
+	data, _ := io.ReadAll(resp.Body)
+
+	if len(data) == 0 {
+		return &provider.Error{
+			StatusCode: resp.StatusCode,
+			Message:    http.StatusText(resp.StatusCode),
+		}
+	}
+
+	return &provider.Error{
+		StatusCode: resp.StatusCode,
+		Message:    string(data),
+	}
+}
+
+func jsonReader(v any) io.Reader {
+	b := new(bytes.Buffer)
+
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(v)
+	return b
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/to"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/shared"
@@ -53,24 +54,7 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 			return nil, convertError(err)
 		}
 
-		choice := completion.Choices[0]
-
-		return &provider.Completion{
-			ID:     completion.ID,
-			Reason: toCompletionResult(choice.FinishReason),
-
-			Message: provider.Message{
-				Role:    provider.MessageRoleAssistant,
-				Content: choice.Message.Content,
-
-				ToolCalls: toToolCalls(choice.Message.ToolCalls),
-			},
-
-			Usage: &provider.Usage{
-				InputTokens:  int(completion.Usage.PromptTokens),
-				OutputTokens: int(completion.Usage.CompletionTokens),
-			},
-		}, nil
+		return toCompletion(completion), nil
 	} else {
 		stream := c.completions.NewStreaming(ctx, *req)
 
@@ -94,6 +78,8 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 
 					ToolCalls: toDeltaToolCalls(chunk.Choices[0].Delta.ToolCalls),
 				},
+
+				Logprobs: toLogprobs(chunk.Choices[0].Logprobs.Content),
 			}
 
 			if err := options.Stream(ctx, completion); err != nil {
@@ -105,25 +91,50 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 			return nil, convertError(err)
 		}
 
-		choice := completion.Choices[0]
+		return toCompletion(&completion.ChatCompletion), nil
+	}
+}
+
+func toCompletion(completion *openai.ChatCompletion) *provider.Completion {
+	var choices []provider.CompletionChoice
 
-		return &provider.Completion{
-			ID:     completion.ID,
-			Reason: toCompletionResult(choice.FinishReason),
+	for _, c := range completion.Choices {
+		choice := provider.CompletionChoice{
+			Reason: toCompletionResult(c.FinishReason),
 
 			Message: provider.Message{
 				Role:    provider.MessageRoleAssistant,
-				Content: choice.Message.Content,
+				Content: c.Message.Content,
 
-				ToolCalls: toToolCalls(choice.Message.ToolCalls),
+				ToolCalls: toToolCalls(c.Message.ToolCalls),
 			},
 
-			Usage: &provider.Usage{
-				InputTokens:  int(completion.Usage.PromptTokens),
-				OutputTokens: int(completion.Usage.CompletionTokens),
-			},
-		}, nil
+			Logprobs: toLogprobs(c.Logprobs.Content),
+		}
+
+		choices = append(choices, choice)
+	}
+
+	result := &provider.Completion{
+		ID: completion.ID,
+
+		Usage: &provider.Usage{
+			InputTokens:  int(completion.Usage.PromptTokens),
+			OutputTokens: int(completion.Usage.CompletionTokens),
+		},
 	}
+
+	if len(choices) > 0 {
+		result.Reason = choices[0].Reason
+		result.Message = choices[0].Message
+		result.Logprobs = choices[0].Logprobs
+	}
+
+	if len(choices) > 1 {
+		result.Choices = choices
+	}
+
+	return result
 }
 
 func (c *Completer) convertCompletionRequest(input []provider.Message, options *provider.CompleteOptions) (*openai.ChatCompletionNewParams, error) {
@@ -135,6 +146,12 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 		Model: openai.F(c.model),
 	}
 
+	if options.Stream != nil && c.streamUsage {
+		req.StreamOptions = openai.F(openai.ChatCompletionStreamOptionsParam{
+			IncludeUsage: openai.F(true),
+		})
+	}
+
 	var tools []openai.ChatCompletionToolParam
 	var messages []openai.ChatCompletionMessageParamUnion
 
@@ -144,6 +161,23 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 		})
 	}
 
+	if options.Format == provider.CompletionFormatJSONSchema && options.Schema != nil {
+		schema := shared.ResponseFormatJSONSchemaJSONSchemaParam{
+			Name:   openai.F(options.Schema.Name),
+			Schema: openai.F[any](options.Schema.Schema),
+			Strict: openai.F(options.Schema.Strict),
+		}
+
+		if options.Schema.Description != "" {
+			schema.Description = openai.F(options.Schema.Description)
+		}
+
+		req.ResponseFormat = openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](shared.ResponseFormatJSONSchemaParam{
+			Type:       openai.F(shared.ResponseFormatJSONSchemaTypeJSONSchema),
+			JSONSchema: openai.F(schema),
+		})
+	}
+
 	if options.Stop != nil {
 		stops := openai.ChatCompletionNewParamsStopArray(options.Stop)
 		req.Stop = openai.F[openai.ChatCompletionNewParamsStopUnion](stops)
@@ -157,6 +191,34 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 		req.Temperature = openai.F(float64(*options.Temperature))
 	}
 
+	if options.TopP != nil {
+		req.TopP = openai.F(float64(*options.TopP))
+	}
+
+	if options.Seed != nil {
+		req.Seed = openai.F(int64(*options.Seed))
+	}
+
+	if options.PresencePenalty != nil {
+		req.PresencePenalty = openai.F(float64(*options.PresencePenalty))
+	}
+
+	if options.FrequencyPenalty != nil {
+		req.FrequencyPenalty = openai.F(float64(*options.FrequencyPenalty))
+	}
+
+	if options.N != nil {
+		req.N = openai.F(int64(*options.N))
+	}
+
+	if options.Logprobs {
+		req.Logprobs = openai.F(true)
+
+		if options.TopLogprobs != nil {
+			req.TopLogprobs = openai.F(int64(*options.TopLogprobs))
+		}
+	}
+
 	for _, m := range input {
 		switch m.Role {
 		case provider.MessageRoleSystem:
@@ -242,6 +304,10 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 		req.Tools = openai.F(tools)
 	}
 
+	if options.ToolChoice != nil {
+		req.ToolChoice = openai.F(convertToolChoice(*options.ToolChoice))
+	}
+
 	if len(messages) > 0 {
 		req.Messages = openai.F(messages)
 	}
@@ -249,12 +315,57 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 	return req, nil
 }
 
+func convertToolChoice(choice provider.ToolChoice) openai.ChatCompletionToolChoiceOptionUnionParam {
+	switch choice.Mode {
+	case provider.ToolChoiceModeNone:
+		return openai.ChatCompletionToolChoiceOptionStringNone
+
+	case provider.ToolChoiceModeRequired:
+		return openai.ChatCompletionToolChoiceOptionStringRequired
+
+	case provider.ToolChoiceModeTool:
+		return openai.ChatCompletionNamedToolChoiceParam{
+			Type: openai.F(openai.ChatCompletionNamedToolChoiceTypeFunction),
+
+			Function: openai.F(openai.ChatCompletionNamedToolChoiceFunctionParam{
+				Name: openai.F(choice.Name),
+			}),
+		}
+
+	default:
+		return openai.ChatCompletionToolChoiceOptionStringAuto
+	}
+}
+
+func toLogprobs(logprobs []openai.ChatCompletionTokenLogprob) []provider.Logprob {
+	var result []provider.Logprob
+
+	for _, l := range logprobs {
+		logprob := provider.Logprob{
+			Token:   l.Token,
+			Logprob: l.Logprob,
+		}
+
+		for _, t := range l.TopLogprobs {
+			logprob.TopLogprobs = append(logprob.TopLogprobs, provider.Logprob{
+				Token:   t.Token,
+				Logprob: t.Logprob,
+			})
+		}
+
+		result = append(result, logprob)
+	}
+
+	return result
+}
+
 func toDeltaToolCalls(calls []openai.ChatCompletionChunkChoicesDeltaToolCall) []provider.ToolCall {
 	var result []provider.ToolCall
 
 	for _, c := range calls {
 		call := provider.ToolCall{
-			ID: c.ID,
+			ID:    c.ID,
+			Index: to.Ptr(int(c.Index)),
 
 			Name:      c.Function.Name,
 			Arguments: c.Function.Arguments,
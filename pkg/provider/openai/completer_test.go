@@ -0,0 +1,115 @@
+package openai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func convertFields(t *testing.T, options *provider.CompleteOptions) map[string]any {
+	t.Helper()
+
+	c, err := NewCompleter("", "gpt-4o")
+	require.NoError(t, err)
+
+	req, err := c.convertCompletionRequest([]provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "Hi",
+		},
+	}, options)
+
+	require.NoError(t, err)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	delete(result, "model")
+	delete(result, "messages")
+
+	return result
+}
+
+func TestConvertOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *provider.CompleteOptions
+		want    map[string]any
+	}{
+		{
+			"none",
+			nil,
+			map[string]any{},
+		},
+		{
+			"stop",
+			&provider.CompleteOptions{Stop: []string{"\n", "END"}},
+			map[string]any{"stop": []any{"\n", "END"}},
+		},
+		{
+			"seed",
+			&provider.CompleteOptions{Seed: ptr(42)},
+			map[string]any{"seed": float64(42)},
+		},
+		{
+			"penalties",
+			&provider.CompleteOptions{PresencePenalty: ptr[float32](0.5), FrequencyPenalty: ptr[float32](-1)},
+			map[string]any{"presence_penalty": 0.5, "frequency_penalty": float64(-1)},
+		},
+		{
+			"choices",
+			&provider.CompleteOptions{N: ptr(2), Logprobs: true, TopLogprobs: ptr(3)},
+			map[string]any{"n": float64(2), "logprobs": true, "top_logprobs": float64(3)},
+		},
+		{
+			"top logprobs without logprobs",
+			&provider.CompleteOptions{TopLogprobs: ptr(3)},
+			map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, convertFields(t, tt.options))
+		})
+	}
+}
+
+func TestConvertToolChoice(t *testing.T) {
+	tools := []provider.Tool{
+		{
+			Name: "weather",
+
+			Parameters: map[string]any{
+				"type": "object",
+			},
+		},
+	}
+
+	tests := []struct {
+		choice provider.ToolChoice
+		want   any
+	}{
+		{provider.ToolChoice{Mode: provider.ToolChoiceModeNone}, "none"},
+		{provider.ToolChoice{Mode: provider.ToolChoiceModeAuto}, "auto"},
+		{provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}, "required"},
+		{provider.ToolChoice{Mode: provider.ToolChoiceModeTool, Name: "weather"}, map[string]any{"type": "function", "function": map[string]any{"name": "weather"}}},
+	}
+
+	for _, tt := range tests {
+		fields := convertFields(t, &provider.CompleteOptions{Tools: tools, ToolChoice: &tt.choice})
+
+		require.Equal(t, tt.want, fields["tool_choice"], tt.choice.Mode)
+		require.Len(t, fields["tools"], 1)
+	}
+}
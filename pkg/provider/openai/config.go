@@ -15,6 +15,8 @@ type Config struct {
 	model string
 
 	client *http.Client
+
+	streamUsage bool
 }
 
 type Option func(*Config)
@@ -31,6 +33,13 @@ func WithToken(token string) Option {
 	}
 }
 
+// WithStreamUsage requests token usage at the end of streamed completions, which not every compatible server accepts
+func WithStreamUsage() Option {
+	return func(c *Config) {
+		c.streamUsage = true
+	}
+}
+
 func (c *Config) Options() []option.RequestOption {
 	if c.url == "" {
 		c.url = "https://api.openai.com/v1/"
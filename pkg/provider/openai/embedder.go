@@ -2,11 +2,11 @@ package openai
 
 import (
 	"context"
+	"errors"
 
 	"github.com/adrianliechti/llama/pkg/provider"
 
 	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/shared"
 )
 
 var _ provider.Embedder = (*Embedder)(nil)
@@ -32,19 +32,45 @@ func NewEmbedder(url, model string, options ...Option) (*Embedder, error) {
 	}, nil
 }
 
-func (e *Embedder) Embed(ctx context.Context, content string) (*provider.Embedding, error) {
-	result, err := e.embeddings.New(ctx, openai.EmbeddingNewParams{
+func (e *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	return provider.EmbedBatches(ctx, texts, options, 2048, e.embed)
+}
+
+func (e *Embedder) embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	if options == nil {
+		options = new(provider.EmbedOptions)
+	}
+
+	req := openai.EmbeddingNewParams{
 		Model:          openai.F(e.model),
-		Input:          openai.F[openai.EmbeddingNewParamsInputUnion](shared.UnionString(content)),
+		Input:          openai.F[openai.EmbeddingNewParamsInputUnion](openai.EmbeddingNewParamsInputArrayOfStrings(texts)),
 		EncodingFormat: openai.F(openai.EmbeddingNewParamsEncodingFormatFloat),
-	})
+	}
+
+	if options.Dimensions != nil {
+		req.Dimensions = openai.F(int64(*options.Dimensions))
+	}
+
+	result, err := e.embeddings.New(ctx, req)
 
 	if err != nil {
 		return nil, convertError(err)
 	}
 
+	if len(result.Data) != len(texts) {
+		return nil, errors.New("invalid embeddings count")
+	}
+
+	embeddings := make([][]float32, len(texts))
+
+	for _, d := range result.Data {
+		if int(d.Index) < len(embeddings) {
+			embeddings[d.Index] = toFloat32(d.Embedding)
+		}
+	}
+
 	return &provider.Embedding{
-		Data: toFloat32(result.Data[0].Embedding),
+		Embeddings: embeddings,
 
 		Usage: &provider.Usage{
 			InputTokens:  int(result.Usage.PromptTokens),
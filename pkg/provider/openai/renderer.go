@@ -36,34 +36,119 @@ func NewRenderer(url, model string, options ...Option) (*Renderer, error) {
 	}, nil
 }
 
-func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Image, error) {
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
 	if options == nil {
 		options = new(provider.RenderOptions)
 	}
 
-	image, err := r.images.Generate(ctx, openai.ImageGenerateParams{
+	var images *openai.ImagesResponse
+	var err error
+
+	switch {
+	case options.Image != nil && input != "":
+		images, err = r.edit(ctx, input, options)
+
+	case options.Image != nil:
+		images, err = r.variation(ctx, options)
+
+	default:
+		images, err = r.generate(ctx, input, options)
+	}
+
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	result := &provider.Rendering{}
+
+	for _, image := range images.Data {
+		data, err := base64.StdEncoding.DecodeString(image.B64JSON)
+
+		if err != nil {
+			return nil, errors.New("invalid image data")
+		}
+
+		id := uuid.NewString()
+
+		result.Images = append(result.Images, provider.Image{
+			ID:   id,
+			Name: id + ".png",
+
+			Content: io.NopCloser(bytes.NewReader(data)),
+		})
+	}
+
+	return result, nil
+}
+
+func (r *Renderer) generate(ctx context.Context, input string, options *provider.RenderOptions) (*openai.ImagesResponse, error) {
+	req := openai.ImageGenerateParams{
 		Model:  openai.F(r.model),
 		Prompt: openai.F(input),
 
 		ResponseFormat: openai.F(openai.ImageGenerateParamsResponseFormatB64JSON),
-	})
+	}
 
-	if err != nil {
-		return nil, convertError(err)
+	if options.Size != "" {
+		req.Size = openai.F(openai.ImageGenerateParamsSize(options.Size))
 	}
 
-	data, err := base64.StdEncoding.DecodeString(image.Data[0].B64JSON)
+	if options.Quality != "" {
+		req.Quality = openai.F(openai.ImageGenerateParamsQuality(options.Quality))
+	}
 
-	if err != nil {
-		return nil, errors.New("invalid image data")
+	if options.Style != "" {
+		req.Style = openai.F(openai.ImageGenerateParamsStyle(options.Style))
+	}
+
+	if options.N != nil {
+		req.N = openai.F(int64(*options.N))
 	}
 
-	id := uuid.NewString()
+	return r.images.Generate(ctx, req)
+}
+
+func (r *Renderer) edit(ctx context.Context, input string, options *provider.RenderOptions) (*openai.ImagesResponse, error) {
+	req := openai.ImageEditParams{
+		Model:  openai.F(r.model),
+		Prompt: openai.F(input),
 
-	return &provider.Image{
-		ID:   id,
-		Name: id + ".png",
+		Image: openai.FileParam(options.Image.Content, options.Image.Name, "image/png"),
 
-		Content: io.NopCloser(bytes.NewReader(data)),
-	}, nil
+		ResponseFormat: openai.F(openai.ImageEditParamsResponseFormatB64JSON),
+	}
+
+	if options.Mask != nil {
+		req.Mask = openai.FileParam(options.Mask.Content, options.Mask.Name, "image/png")
+	}
+
+	if options.Size != "" {
+		req.Size = openai.F(openai.ImageEditParamsSize(options.Size))
+	}
+
+	if options.N != nil {
+		req.N = openai.F(int64(*options.N))
+	}
+
+	return r.images.Edit(ctx, req)
+}
+
+func (r *Renderer) variation(ctx context.Context, options *provider.RenderOptions) (*openai.ImagesResponse, error) {
+	req := openai.ImageNewVariationParams{
+		Model: openai.F(r.model),
+
+		Image: openai.FileParam(options.Image.Content, options.Image.Name, "image/png"),
+
+		ResponseFormat: openai.F(openai.ImageNewVariationParamsResponseFormatB64JSON),
+	}
+
+	if options.Size != "" {
+		req.Size = openai.F(openai.ImageNewVariationParamsSize(options.Size))
+	}
+
+	if options.N != nil {
+		req.N = openai.F(int64(*options.N))
+	}
+
+	return r.images.NewVariation(ctx, req)
 }
@@ -32,19 +32,59 @@ func NewSynthesizer(url, model string, options ...Option) (*Synthesizer, error)
 	}, nil
 }
 
+func (s *Synthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	voices := []openai.AudioSpeechNewParamsVoice{
+		openai.AudioSpeechNewParamsVoiceAlloy,
+		openai.AudioSpeechNewParamsVoiceEcho,
+		openai.AudioSpeechNewParamsVoiceFable,
+		openai.AudioSpeechNewParamsVoiceOnyx,
+		openai.AudioSpeechNewParamsVoiceNova,
+		openai.AudioSpeechNewParamsVoiceShimmer,
+	}
+
+	var result []provider.Voice
+
+	for _, v := range voices {
+		result = append(result, provider.Voice{
+			ID:   string(v),
+			Name: string(v),
+		})
+	}
+
+	return result, nil
+}
+
 func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
 	if options == nil {
 		options = new(provider.SynthesizeOptions)
 	}
 
-	result, err := s.speech.New(ctx, openai.AudioSpeechNewParams{
+	voice := openai.AudioSpeechNewParamsVoiceAlloy
+
+	if options.Voice != "" {
+		voice = openai.AudioSpeechNewParamsVoice(options.Voice)
+	}
+
+	format := provider.SpeechFormatMP3
+
+	if options.Format != "" {
+		format = options.Format
+	}
+
+	req := openai.AudioSpeechNewParams{
 		Model: openai.F(s.model),
 		Input: openai.F(content),
 
-		Voice: openai.F(openai.AudioSpeechNewParamsVoiceAlloy),
+		Voice: openai.F(voice),
+
+		ResponseFormat: openai.F(openai.AudioSpeechNewParamsResponseFormat(format)),
+	}
+
+	if options.Speed != nil {
+		req.Speed = openai.F(float64(*options.Speed))
+	}
 
-		ResponseFormat: openai.F(openai.AudioSpeechNewParamsResponseFormatWAV),
-	})
+	result, err := s.speech.New(ctx, req)
 
 	if err != nil {
 		return nil, convertError(err)
@@ -55,7 +95,7 @@ func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *p
 	return &provider.Synthesis{
 		ID: id,
 
-		Name:    id + ".wav",
+		Name:    id + "." + string(format),
 		Content: result.Body,
 	}, nil
 }
@@ -1,13 +1,18 @@
 package openai
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
+	"mime/multipart"
+	"strconv"
 
 	"github.com/adrianliechti/llama/pkg/provider"
 
 	"github.com/google/uuid"
 	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
 )
 
 var _ provider.Transcriber = (*Transcriber)(nil)
@@ -40,32 +45,99 @@ func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, optio
 
 	id := uuid.NewString()
 
-	transcription, err := t.transcriptions.New(ctx, openai.AudioTranscriptionNewParams{
-		Model: openai.F(t.model),
-		File:  openai.FileParam(input.Content, input.Name, ""),
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("model", t.model)
+	w.WriteField("response_format", string(openai.AudioResponseFormatVerboseJSON))
 
-		ResponseFormat: openai.F(openai.AudioResponseFormatVerboseJSON),
-	})
+	if options.Language != "" {
+		w.WriteField("language", options.Language)
+	}
+
+	if options.Temperature != nil {
+		w.WriteField("temperature", strconv.FormatFloat(float64(*options.Temperature), 'f', -1, 32))
+	}
+
+	// the sdk encodes arrays as "timestamp_granularities.0", which the api rejects
+	w.WriteField("timestamp_granularities[]", string(openai.AudioTranscriptionNewParamsTimestampGranularitySegment))
+
+	if options.Words {
+		w.WriteField("timestamp_granularities[]", string(openai.AudioTranscriptionNewParamsTimestampGranularityWord))
+	}
+
+	file, err := w.CreateFormFile("file", input.Name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(file, input.Content); err != nil {
+		return nil, err
+	}
+
+	w.Close()
+
+	transcription, err := t.transcriptions.New(ctx, openai.AudioTranscriptionNewParams{}, option.WithRequestBody(w.FormDataContentType(), &body))
 
 	if err != nil {
 		return nil, convertError(err)
 	}
 
+	var verbose TranscriptionResponse
+
+	if err := json.Unmarshal([]byte(transcription.JSON.RawJSON()), &verbose); err != nil {
+		return nil, err
+	}
+
 	result := provider.Transcription{
 		ID: id,
 
+		Language: verbose.Language,
+		Duration: verbose.Duration,
+
 		Content: transcription.Text,
 	}
 
-	var metadata struct {
-		Language string  `json:"language"`
-		Duration float64 `json:"duration"`
+	for _, s := range verbose.Segments {
+		result.Segments = append(result.Segments, provider.TranscriptionSegment{
+			ID: s.ID,
+
+			Start: s.Start,
+			End:   s.End,
+
+			Text: s.Text,
+		})
 	}
 
-	if err := json.Unmarshal([]byte(transcription.JSON.RawJSON()), &metadata); err == nil {
-		result.Language = metadata.Language
-		result.Duration = metadata.Duration
+	for _, w := range verbose.Words {
+		result.Words = append(result.Words, provider.TranscriptionWord{
+			Start: w.Start,
+			End:   w.End,
+
+			Text: w.Word,
+		})
 	}
 
 	return &result, nil
 }
+
+type TranscriptionResponse struct {
+	Language string  `json:"language"`
+	Duration float64 `json:"duration"`
+
+	Segments []struct {
+		ID int `json:"id"`
+
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+
+		Text string `json:"text"`
+	} `json:"segments"`
+
+	Words []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+
+		Word string `json:"word"`
+	} `json:"words"`
+}
@@ -3,6 +3,8 @@ package openai
 import (
 	"errors"
 
+	"github.com/adrianliechti/llama/pkg/provider"
+
 	"github.com/openai/openai-go"
 )
 
@@ -12,6 +14,11 @@ func convertError(err error) error {
 	if errors.As(err, &apierr) {
 		//println(string(apierr.DumpRequest(true)))  // Prints the serialized HTTP request
 		//println(string(apierr.DumpResponse(true))) // Prints the serialized HTTP response
+
+		return &provider.Error{
+			StatusCode: apierr.StatusCode,
+			Message:    err.Error(),
+		}
 	}
 
 	return err
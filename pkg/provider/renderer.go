@@ -6,10 +6,25 @@ import (
 )
 
 type Renderer interface {
-	Render(ctx context.Context, input string, options *RenderOptions) (*Image, error)
+	Render(ctx context.Context, input string, options *RenderOptions) (*Rendering, error)
 }
 
 type RenderOptions struct {
+	Size    string
+	Quality string
+	Style   string
+
+	N *int
+
+	// Image requests an edit (with prompt) or a variation (without prompt) of an existing image
+	Image *File
+
+	// Mask marks the transparent areas of Image to be edited
+	Mask *File
+}
+
+type Rendering struct {
+	Images []Image
 }
 
 type Image struct {
@@ -25,6 +25,20 @@ var (
 		FluxDevRealism,
 	}
 
+	AspectRatios = []string{
+		"1:1",
+		"16:9",
+		"21:9",
+		"3:2",
+		"2:3",
+		"4:5",
+		"5:4",
+		"3:4",
+		"4:3",
+		"9:16",
+		"9:21",
+	}
+
 	ModelVersion = map[string]string{
 		FluxDevRealism: "39b3434f194f87a900d1bc2b6d4b983e90f0dde1d5022c27b52c143d670758fa",
 	}
@@ -2,8 +2,12 @@ package flux
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
@@ -42,7 +46,7 @@ func NewRenderer(url, model string, options ...Option) (*Renderer, error) {
 	}, nil
 }
 
-func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Image, error) {
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
 	if options == nil {
 		options = new(provider.RenderOptions)
 	}
@@ -110,7 +114,7 @@ func (r *Renderer) Render(ctx context.Context, input string, options *provider.R
 			return nil, errors.New("prediction " + string(prediction.Status))
 		}
 
-		output, err := r.convertPredictionResponse(prediction)
+		output, err := r.convertPredictionResponse(ctx, prediction)
 
 		if err != nil {
 			return nil, err
@@ -190,6 +194,74 @@ func (r *Renderer) convertPredictionRequest(prompt string, options *provider.Ren
 		return nil, errors.New("unsupported model")
 	}
 
+	if options.Style != "" {
+		return nil, provider.UnsupportedOption("flux", "style")
+	}
+
+	if options.Mask != nil {
+		return nil, provider.UnsupportedOption("flux", "mask")
+	}
+
+	if options.Size != "" {
+		width, height, err := parseSize(options.Size)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ratio, exact := aspectRatio(width, height)
+
+		if !exact && (r.model == FluxPro || r.model == FluxPro11) {
+			input["aspect_ratio"] = "custom"
+
+			input["width"] = width
+			input["height"] = height
+		} else {
+			input["aspect_ratio"] = ratio
+		}
+	}
+
+	if options.Quality == "hd" {
+		switch r.model {
+		case FluxDev:
+			input["num_inference_steps"] = 50
+
+		case FluxPro:
+			input["steps"] = 50
+		}
+	}
+
+	if options.N != nil && *options.N > 1 {
+		switch r.model {
+		case FluxSchnell, FluxDev, FluxDevRealism:
+			input["num_outputs"] = min(*options.N, 4)
+
+		default:
+			return nil, provider.UnsupportedOption("flux", "multiple images")
+		}
+	}
+
+	if options.Image != nil {
+		data, err := io.ReadAll(options.Image.Content)
+
+		if err != nil {
+			return nil, err
+		}
+
+		image := "data:" + http.DetectContentType(data) + ";base64," + base64.StdEncoding.EncodeToString(data)
+
+		switch r.model {
+		case FluxDev:
+			input["image"] = image
+
+		case FluxPro, FluxPro11:
+			input["image_prompt"] = image
+
+		default:
+			return nil, provider.UnsupportedOption("flux", "input image")
+		}
+	}
+
 	return &CreatePredictionRequest{
 		Version: ModelVersion[r.model],
 
@@ -197,36 +269,83 @@ func (r *Renderer) convertPredictionRequest(prompt string, options *provider.Ren
 	}, nil
 }
 
-func (r *Renderer) convertPredictionResponse(prediction PredictionResponse) (*provider.Image, error) {
+func (r *Renderer) convertPredictionResponse(ctx context.Context, prediction PredictionResponse) (*provider.Rendering, error) {
 	var url string
 	var urls []string
 
 	json.Unmarshal(prediction.Output, &url)
 	json.Unmarshal(prediction.Output, &urls)
 
-	if len(urls) > 0 {
-		url = urls[0]
+	if url != "" {
+		urls = []string{url}
 	}
 
-	if url == "" {
+	if len(urls) == 0 {
 		return nil, errors.New("invalid output")
 	}
 
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("Authorization", "Bearer "+r.token)
+	result := &provider.Rendering{}
 
-	resp, err := r.client.Do(req)
+	for i, url := range urls {
+		req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+		req.Header.Set("Authorization", "Bearer "+r.token)
 
-	if err != nil {
-		return nil, err
+		resp, err := r.client.Do(req)
+
+		if err != nil {
+			for _, image := range result.Images {
+				image.Content.Close()
+			}
+
+			return nil, err
+		}
+
+		id := prediction.ID
+
+		if i > 0 {
+			id = fmt.Sprintf("%s-%d", prediction.ID, i)
+		}
+
+		result.Images = append(result.Images, provider.Image{
+			ID: id,
+
+			Name:    path.Base(url),
+			Content: resp.Body,
+		})
 	}
 
-	return &provider.Image{
-		ID: prediction.ID,
+	return result, nil
+}
 
-		Name:    path.Base(url),
-		Content: resp.Body,
-	}, nil
+func parseSize(size string) (int, int, error) {
+	var width, height int
+
+	if _, err := fmt.Sscanf(size, "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return 0, 0, errors.New("invalid size: " + size)
+	}
+
+	return width, height, nil
+}
+
+func aspectRatio(width, height int) (string, bool) {
+	target := float64(width) / float64(height)
+
+	var result string
+	var delta float64
+
+	for _, ratio := range AspectRatios {
+		var w, h int
+		fmt.Sscanf(ratio, "%d:%d", &w, &h)
+
+		d := math.Abs(float64(w)/float64(h) - target)
+
+		if result == "" || d < delta {
+			result = ratio
+			delta = d
+		}
+	}
+
+	return result, delta < 0.001
 }
 
 type CreatePredictionRequest struct {
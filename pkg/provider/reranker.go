@@ -13,6 +13,9 @@ type RerankOptions struct {
 }
 
 type Ranking struct {
+	// Index is the position of the ranked input
+	Index int
+
 	Content string
 	Score   float64
 }
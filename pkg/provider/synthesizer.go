@@ -6,16 +6,39 @@ import (
 )
 
 type Synthesizer interface {
+	Voices(ctx context.Context) ([]Voice, error)
+
 	Synthesize(ctx context.Context, content string, options *SynthesizeOptions) (*Synthesis, error)
 }
 
 type SynthesizeOptions struct {
 	Voice string
+
+	Format SpeechFormat
+	Speed  *float32
+}
+
+type SpeechFormat string
+
+const (
+	SpeechFormatMP3  SpeechFormat = "mp3"
+	SpeechFormatOpus SpeechFormat = "opus"
+	SpeechFormatAAC  SpeechFormat = "aac"
+	SpeechFormatFLAC SpeechFormat = "flac"
+	SpeechFormatWAV  SpeechFormat = "wav"
+	SpeechFormatPCM  SpeechFormat = "pcm"
+)
+
+type Voice struct {
+	ID   string
+	Name string
 }
 
 type Synthesis struct {
 	ID string
 
-	Name    string
+	Name string
+
+	// Content streams the audio while it is being generated
 	Content io.ReadCloser
 }
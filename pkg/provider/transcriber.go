@@ -11,6 +11,9 @@ type Transcriber interface {
 type TranscribeOptions struct {
 	Language    string
 	Temperature *float32
+
+	// Words requests word-level timestamps in addition to segments
+	Words bool
 }
 
 type Transcription struct {
@@ -20,4 +23,23 @@ type Transcription struct {
 	Duration float64
 
 	Content string
+
+	Segments []TranscriptionSegment
+	Words    []TranscriptionWord
+}
+
+type TranscriptionSegment struct {
+	ID int
+
+	Start float64
+	End   float64
+
+	Text string
+}
+
+type TranscriptionWord struct {
+	Start float64
+	End   float64
+
+	Text string
 }
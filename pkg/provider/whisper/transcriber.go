@@ -9,6 +9,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/adrianliechti/llama/pkg/provider"
@@ -61,6 +62,10 @@ func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, optio
 	w.WriteField("language", options.Language)
 	w.WriteField("response_format", "verbose_json")
 
+	if options.Temperature != nil {
+		w.WriteField("temperature", strconv.FormatFloat(float64(*options.Temperature), 'f', -1, 32))
+	}
+
 	file, err := w.CreateFormFile("file", input.Name)
 
 	if err != nil {
@@ -109,6 +114,50 @@ func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, optio
 		Content: content,
 	}
 
+	for _, s := range inference.Segments {
+		text := strings.TrimSpace(s.Text)
+
+		if text == "" || strings.EqualFold(text, "[BLANK_AUDIO]") {
+			continue
+		}
+
+		result.Segments = append(result.Segments, provider.TranscriptionSegment{
+			ID: s.ID,
+
+			Start: s.Start,
+			End:   s.End,
+
+			Text: text,
+		})
+
+		if !options.Words {
+			continue
+		}
+
+		// whisper.cpp reports tokens, which are merged into words at leading spaces
+		for i, token := range s.Words {
+			if token.Word == "" {
+				continue
+			}
+
+			if i > 0 && len(result.Words) > 0 && !strings.HasPrefix(token.Word, " ") {
+				last := &result.Words[len(result.Words)-1]
+
+				last.Text += token.Word
+				last.End = token.End
+
+				continue
+			}
+
+			result.Words = append(result.Words, provider.TranscriptionWord{
+				Start: token.Start,
+				End:   token.End,
+
+				Text: strings.TrimSpace(token.Word),
+			})
+		}
+	}
+
 	return &result, nil
 }
 
@@ -119,4 +168,22 @@ type InferenceResponse struct {
 	Duration float64 `json:"duration"`
 
 	Text string `json:"text"`
+
+	Segments []InferenceSegment `json:"segments"`
+}
+
+type InferenceSegment struct {
+	ID int `json:"id"`
+
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+
+	Text string `json:"text"`
+
+	Words []struct {
+		Word string `json:"word"`
+
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	} `json:"words"`
 }
@@ -0,0 +1,98 @@
+package whisper_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/provider/whisper"
+
+	"github.com/stretchr/testify/require"
+)
+
+const inference = `{
+	"language": "en",
+	"duration": 3.2,
+	"text": " Hello world. [BLANK_AUDIO]",
+	"segments": [
+		{
+			"id": 0,
+			"start": 0,
+			"end": 1.5,
+			"text": " Hello world.",
+			"words": [
+				{"word": " Hel", "start": 0.1, "end": 0.3},
+				{"word": "lo", "start": 0.3, "end": 0.5},
+				{"word": "", "start": 0.5, "end": 0.5},
+				{"word": " world", "start": 0.6, "end": 1.0},
+				{"word": ".", "start": 1.0, "end": 1.1}
+			]
+		},
+		{
+			"id": 1,
+			"start": 1.5,
+			"end": 3.2,
+			"text": " [BLANK_AUDIO]"
+		}
+	]
+}`
+
+func newTestTranscriber(t *testing.T) *whisper.Transcriber {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(inference))
+	}))
+
+	t.Cleanup(server.Close)
+
+	transcriber, err := whisper.NewTranscriber(server.URL, "")
+	require.NoError(t, err)
+
+	return transcriber
+}
+
+func TestTranscribeWords(t *testing.T) {
+	transcriber := newTestTranscriber(t)
+
+	result, err := transcriber.Transcribe(context.Background(), provider.File{
+		Name:    "audio.wav",
+		Content: strings.NewReader("wav"),
+	}, &provider.TranscribeOptions{
+		Words: true,
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, "Hello world. [BLANK_AUDIO]", result.Content)
+	require.Equal(t, 3.2, result.Duration)
+
+	// blank segments are dropped
+	require.Equal(t, []provider.TranscriptionSegment{
+		{ID: 0, Start: 0, End: 1.5, Text: "Hello world."},
+	}, result.Segments)
+
+	// tokens without a leading space continue the previous word
+	require.Equal(t, []provider.TranscriptionWord{
+		{Start: 0.1, End: 0.5, Text: "Hello"},
+		{Start: 0.6, End: 1.1, Text: "world."},
+	}, result.Words)
+}
+
+func TestTranscribeWithoutWords(t *testing.T) {
+	transcriber := newTestTranscriber(t)
+
+	result, err := transcriber.Transcribe(context.Background(), provider.File{
+		Name:    "audio.wav",
+		Content: strings.NewReader("wav"),
+	}, nil)
+
+	require.NoError(t, err)
+
+	require.Len(t, result.Segments, 1)
+	require.Empty(t, result.Words)
+}
@@ -0,0 +1,23 @@
+package quota
+
+import (
+	"time"
+)
+
+type ErrorType string
+
+const (
+	ErrorTypeTokens ErrorType = "tokens"
+	ErrorTypeQuota  ErrorType = "insufficient_quota"
+)
+
+type Error struct {
+	Type    ErrorType
+	Message string
+
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
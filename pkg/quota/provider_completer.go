@@ -0,0 +1,71 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Completer interface {
+	Quota
+	provider.Completer
+}
+
+type quotaCompleter struct {
+	manager *Manager
+	pricing *Pricing
+
+	provider provider.Completer
+}
+
+func NewCompleter(m *Manager, pricing *Pricing, p provider.Completer) Completer {
+	return &quotaCompleter{
+		manager: m,
+		pricing: pricing,
+
+		provider: p,
+	}
+}
+
+func (p *quotaCompleter) quotaSetup() {
+}
+
+func (p *quotaCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if err := p.manager.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := p.provider.Complete(ctx, messages, options)
+
+	if result != nil {
+		usage := result.Usage
+
+		// completions of providers not reporting usage are estimated, so they still count against token quotas
+		if usage == nil {
+			usage = estimateCompletionUsage(messages, result)
+		}
+
+		p.manager.Record(ctx, p.pricing, usage)
+	}
+
+	return result, err
+}
+
+func estimateCompletionUsage(messages []provider.Message, completion *provider.Completion) *provider.Usage {
+	var input []string
+
+	for _, m := range messages {
+		input = append(input, m.Content)
+	}
+
+	output := []string{completion.Message.Content}
+
+	for _, c := range completion.Message.ToolCalls {
+		output = append(output, c.Name, c.Arguments)
+	}
+
+	return &provider.Usage{
+		InputTokens:  estimateTokens(input...),
+		OutputTokens: estimateTokens(output...),
+	}
+}
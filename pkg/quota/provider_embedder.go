@@ -0,0 +1,54 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Embedder interface {
+	Quota
+	provider.Embedder
+}
+
+type quotaEmbedder struct {
+	manager *Manager
+	pricing *Pricing
+
+	provider provider.Embedder
+}
+
+func NewEmbedder(m *Manager, pricing *Pricing, p provider.Embedder) Embedder {
+	return &quotaEmbedder{
+		manager: m,
+		pricing: pricing,
+
+		provider: p,
+	}
+}
+
+func (p *quotaEmbedder) quotaSetup() {
+}
+
+func (p *quotaEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	if err := p.manager.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := p.provider.Embed(ctx, texts, options)
+
+	if result != nil {
+		usage := result.Usage
+
+		// embeddings of providers not reporting usage are estimated, so they still count against token quotas
+		if usage == nil {
+			usage = &provider.Usage{
+				InputTokens: estimateTokens(texts...),
+			}
+		}
+
+		p.manager.Record(ctx, p.pricing, usage)
+	}
+
+	return result, err
+}
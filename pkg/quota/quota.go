@@ -0,0 +1,191 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Quota interface {
+	quotaSetup()
+}
+
+type Limits struct {
+	TokensPerMinute int
+	TokensPerDay    int
+
+	SpendPerMonth float64
+}
+
+type Pricing struct {
+	Input  float64
+	Output float64
+}
+
+type Manager struct {
+	mu sync.Mutex
+
+	now func() time.Time
+
+	limits map[string]Limits
+	usage  map[string]*usage
+}
+
+type usage struct {
+	minute      time.Time
+	minuteCount int
+
+	day      time.Time
+	dayCount int
+
+	month      time.Time
+	monthSpend float64
+}
+
+func New(limits map[string]Limits) *Manager {
+	return &Manager{
+		now: time.Now,
+
+		limits: limits,
+		usage:  make(map[string]*usage),
+	}
+}
+
+func (m *Manager) Check(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+
+	id := principalID(ctx)
+
+	limits, ok := m.lookup(id)
+
+	if !ok {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now().UTC()
+	u := m.current(id, now)
+
+	if limits.TokensPerMinute > 0 && u.minuteCount >= limits.TokensPerMinute {
+		return &Error{
+			Type:       ErrorTypeTokens,
+			Message:    fmt.Sprintf("rate limit reached for tokens per minute: limit %d, used %d", limits.TokensPerMinute, u.minuteCount),
+			RetryAfter: u.minute.Add(time.Minute).Sub(now),
+		}
+	}
+
+	if limits.TokensPerDay > 0 && u.dayCount >= limits.TokensPerDay {
+		return &Error{
+			Type:       ErrorTypeTokens,
+			Message:    fmt.Sprintf("rate limit reached for tokens per day: limit %d, used %d", limits.TokensPerDay, u.dayCount),
+			RetryAfter: u.day.AddDate(0, 0, 1).Sub(now),
+		}
+	}
+
+	if limits.SpendPerMonth > 0 && u.monthSpend >= limits.SpendPerMonth {
+		return &Error{
+			Type:       ErrorTypeQuota,
+			Message:    fmt.Sprintf("monthly spend limit reached: limit %.2f, used %.2f", limits.SpendPerMonth, u.monthSpend),
+			RetryAfter: u.month.AddDate(0, 1, 0).Sub(now),
+		}
+	}
+
+	return nil
+}
+
+// Record accounts the usage of a request, which may exceed the remaining limits: they are soft and only block the next request
+func (m *Manager) Record(ctx context.Context, pricing *Pricing, val *provider.Usage) {
+	if m == nil || val == nil {
+		return
+	}
+
+	id := principalID(ctx)
+
+	if _, ok := m.lookup(id); !ok {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u := m.current(id, m.now().UTC())
+
+	tokens := val.InputTokens + val.OutputTokens
+
+	u.minuteCount += tokens
+	u.dayCount += tokens
+
+	if pricing != nil {
+		u.monthSpend += float64(val.InputTokens)*pricing.Input/1e6 + float64(val.OutputTokens)*pricing.Output/1e6
+	}
+}
+
+func (m *Manager) lookup(id string) (Limits, bool) {
+	if l, ok := m.limits[id]; ok {
+		return l, true
+	}
+
+	if l, ok := m.limits["*"]; ok {
+		return l, true
+	}
+
+	return Limits{}, false
+}
+
+func (m *Manager) current(id string, now time.Time) *usage {
+	u, ok := m.usage[id]
+
+	if !ok {
+		u = &usage{}
+		m.usage[id] = u
+	}
+
+	minute := now.Truncate(time.Minute)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	if !u.minute.Equal(minute) {
+		u.minute = minute
+		u.minuteCount = 0
+	}
+
+	if !u.day.Equal(day) {
+		u.day = day
+		u.dayCount = 0
+	}
+
+	if !u.month.Equal(month) {
+		u.month = month
+		u.monthSpend = 0
+	}
+
+	return u
+}
+
+func principalID(ctx context.Context) string {
+	if p, ok := authorizer.PrincipalFromContext(ctx); ok {
+		return p.ID
+	}
+
+	return ""
+}
+
+// estimateTokens approximates the token count of texts by the common rule of four characters per token
+func estimateTokens(texts ...string) int {
+	var count int
+
+	for _, text := range texts {
+		count += (utf8.RuneCountInString(text) + 3) / 4
+	}
+
+	return count
+}
@@ -0,0 +1,173 @@
+package quota
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestManager(limits map[string]Limits, now time.Time) (*Manager, *testClock) {
+	clock := &testClock{now: now}
+
+	m := New(limits)
+	m.now = clock.Now
+
+	return m, clock
+}
+
+func principalContext(id string) context.Context {
+	return authorizer.WithPrincipal(context.Background(), &authorizer.Principal{ID: id})
+}
+
+func requireQuotaError(t *testing.T, err error, errorType ErrorType, retryAfter time.Duration) {
+	t.Helper()
+
+	var quotaErr *Error
+	require.ErrorAs(t, err, &quotaErr)
+
+	require.Equal(t, errorType, quotaErr.Type)
+	require.Equal(t, retryAfter, quotaErr.RetryAfter)
+}
+
+func TestMinuteRollover(t *testing.T) {
+	m, clock := newTestManager(map[string]Limits{
+		"alice": {TokensPerMinute: 100},
+	}, time.Date(2024, 5, 10, 12, 30, 15, 0, time.UTC))
+
+	ctx := principalContext("alice")
+
+	m.Record(ctx, nil, &provider.Usage{InputTokens: 60, OutputTokens: 40})
+	requireQuotaError(t, m.Check(ctx), ErrorTypeTokens, 45*time.Second)
+
+	clock.now = time.Date(2024, 5, 10, 12, 30, 59, 0, time.UTC)
+	requireQuotaError(t, m.Check(ctx), ErrorTypeTokens, time.Second)
+
+	clock.now = time.Date(2024, 5, 10, 12, 31, 0, 0, time.UTC)
+	require.NoError(t, m.Check(ctx))
+}
+
+func TestDayRollover(t *testing.T) {
+	m, clock := newTestManager(map[string]Limits{
+		"*": {TokensPerMinute: 1000, TokensPerDay: 150},
+	}, time.Date(2024, 5, 10, 23, 0, 0, 0, time.UTC))
+
+	ctx := principalContext("bob")
+
+	m.Record(ctx, nil, &provider.Usage{InputTokens: 100})
+
+	clock.now = clock.now.Add(30 * time.Minute)
+	require.NoError(t, m.Check(ctx))
+
+	m.Record(ctx, nil, &provider.Usage{InputTokens: 50})
+	requireQuotaError(t, m.Check(ctx), ErrorTypeTokens, 30*time.Minute)
+
+	clock.now = time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, m.Check(ctx))
+}
+
+func TestMonthRollover(t *testing.T) {
+	m, clock := newTestManager(map[string]Limits{
+		"alice": {SpendPerMonth: 1},
+	}, time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC))
+
+	ctx := principalContext("alice")
+
+	pricing := &Pricing{
+		Input:  2,
+		Output: 10,
+	}
+
+	// 250k input tokens at 2 and 50k output tokens at 10 per million
+	m.Record(ctx, pricing, &provider.Usage{InputTokens: 250_000, OutputTokens: 50_000})
+	requireQuotaError(t, m.Check(ctx), ErrorTypeQuota, 12*time.Hour)
+
+	clock.now = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, m.Check(ctx))
+}
+
+func TestSoftLimit(t *testing.T) {
+	m, _ := newTestManager(map[string]Limits{
+		"alice": {TokensPerMinute: 100},
+	}, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))
+
+	ctx := principalContext("alice")
+
+	m.Record(ctx, nil, &provider.Usage{InputTokens: 90})
+	require.NoError(t, m.Check(ctx))
+
+	// the request admitted below the limit is accounted in full, even though it exceeds the limit
+	m.Record(ctx, nil, &provider.Usage{InputTokens: 20, OutputTokens: 30})
+
+	err := m.Check(ctx)
+	requireQuotaError(t, err, ErrorTypeTokens, time.Minute)
+	require.Contains(t, err.Error(), "used 140")
+}
+
+func TestPrincipals(t *testing.T) {
+	m, _ := newTestManager(map[string]Limits{
+		"alice": {TokensPerMinute: 10},
+	}, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))
+
+	alice := principalContext("alice")
+	bob := principalContext("bob")
+
+	m.Record(alice, nil, &provider.Usage{InputTokens: 10})
+	m.Record(bob, nil, &provider.Usage{InputTokens: 10})
+
+	require.Error(t, m.Check(alice))
+	require.NoError(t, m.Check(bob))
+}
+
+type staticCompleter struct {
+	completion provider.Completion
+}
+
+func (c *staticCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	completion := c.completion
+	return &completion, nil
+}
+
+func TestCompleterEstimatesMissingUsage(t *testing.T) {
+	m, _ := newTestManager(map[string]Limits{
+		"alice": {TokensPerMinute: 200},
+	}, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))
+
+	ctx := principalContext("alice")
+
+	c := NewCompleter(m, nil, &staticCompleter{
+		completion: provider.Completion{
+			Message: provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: strings.Repeat("a", 400),
+			},
+		},
+	})
+
+	messages := []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: strings.Repeat("q", 400),
+		},
+	}
+
+	_, err := c.Complete(ctx, messages, nil)
+	require.NoError(t, err)
+
+	_, err = c.Complete(ctx, messages, nil)
+	requireQuotaError(t, err, ErrorTypeTokens, time.Minute)
+	require.Contains(t, err.Error(), "used 200")
+}
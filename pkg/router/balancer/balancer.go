@@ -0,0 +1,206 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/otel"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router"
+)
+
+type Backend[T any] struct {
+	Name   string
+	Weight int
+
+	Provider T
+}
+
+type backend[T any] struct {
+	Backend[T]
+
+	breaker breaker
+
+	current     int
+	outstanding int
+}
+
+type balancer[T any] struct {
+	*Config
+
+	// capability distinguishes the meters of routers sharing a name
+	capability string
+
+	now func() time.Time
+
+	mu sync.Mutex
+
+	counter  int
+	backends []*backend[T]
+}
+
+func newBalancer[T any](capability string, backends []Backend[T], options ...Option) (*balancer[T], error) {
+	if len(backends) == 0 {
+		return nil, errors.New("no backends configured")
+	}
+
+	cfg := &Config{
+		strategy: StrategyRoundRobin,
+
+		threshold:   DefaultThreshold,
+		minRequests: DefaultMinRequests,
+
+		cooldown: DefaultCooldown,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	switch cfg.strategy {
+	case StrategyRoundRobin, StrategyLeastRequests, StrategyWeighted:
+	default:
+		return nil, errors.New("invalid balancer strategy: " + string(cfg.strategy))
+	}
+
+	if cfg.threshold <= 0 || cfg.threshold > 1 {
+		return nil, errors.New("invalid balancer threshold: must be greater than 0 and at most 1")
+	}
+
+	if cfg.minRequests < 1 || cfg.minRequests > breakerWindow {
+		return nil, errors.New("invalid balancer min requests: must be between 1 and " + strconv.Itoa(breakerWindow))
+	}
+
+	if cfg.cooldown < 0 {
+		return nil, errors.New("invalid balancer cooldown: must not be negative")
+	}
+
+	b := &balancer[T]{
+		Config: cfg,
+
+		capability: capability,
+
+		now: time.Now,
+	}
+
+	for _, be := range backends {
+		if be.Weight <= 0 {
+			be.Weight = 1
+		}
+
+		b.backends = append(b.backends, &backend[T]{
+			Backend: be,
+		})
+	}
+
+	return b, nil
+}
+
+func (b *balancer[T]) acquire(ctx context.Context) (*backend[T], error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	var candidates []*backend[T]
+
+	for _, be := range b.backends {
+		if be.breaker.available(now, b.cooldown) {
+			candidates = append(candidates, be)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, &provider.Error{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "no healthy backend available",
+		}
+	}
+
+	var result *backend[T]
+
+	switch b.strategy {
+	case StrategyLeastRequests:
+		result = b.selectLeastRequests(candidates)
+
+	case StrategyWeighted:
+		result = b.selectWeighted(candidates)
+
+	default:
+		result = b.selectRoundRobin(candidates)
+	}
+
+	result.breaker.acquire()
+	result.outstanding++
+
+	b.meter(ctx, result)
+
+	return result, nil
+}
+
+func (b *balancer[T]) release(ctx context.Context, be *backend[T], latency time.Duration, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	be.outstanding--
+
+	if router.IsCallerError(ctx, err) {
+		// errors of the caller say nothing about the health of the backend
+		be.breaker.release()
+	} else {
+		success := err == nil || !router.IsRetryable(err)
+		be.breaker.record(b.now(), success, latency, b.threshold, b.minRequests)
+	}
+
+	b.meter(ctx, be)
+}
+
+func (b *balancer[T]) meter(ctx context.Context, be *backend[T]) {
+	otel.MeterRouterState(ctx, b.name, b.capability, be.Name, int64(be.breaker.state), int64(be.outstanding), be.breaker.latency)
+}
+
+func (b *balancer[T]) selectRoundRobin(candidates []*backend[T]) *backend[T] {
+	result := candidates[b.counter%len(candidates)]
+	b.counter++
+
+	return result
+}
+
+func (b *balancer[T]) selectLeastRequests(candidates []*backend[T]) *backend[T] {
+	result := candidates[0]
+
+	for _, be := range candidates[1:] {
+		if be.outstanding < result.outstanding {
+			result = be
+			continue
+		}
+
+		if be.outstanding == result.outstanding && be.breaker.latency < result.breaker.latency {
+			result = be
+		}
+	}
+
+	return result
+}
+
+func (b *balancer[T]) selectWeighted(candidates []*backend[T]) *backend[T] {
+	var total int
+	var result *backend[T]
+
+	for _, be := range candidates {
+		be.current += be.Weight
+		total += be.Weight
+
+		if result == nil || be.current > result.current {
+			result = be
+		}
+	}
+
+	result.current -= total
+
+	return result
+}
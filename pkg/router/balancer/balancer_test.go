@@ -0,0 +1,210 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBalancer(t *testing.T, weights map[string]int, options ...Option) *balancer[string] {
+	t.Helper()
+
+	var backends []Backend[string]
+
+	for _, name := range []string{"a", "b", "c"} {
+		weight, ok := weights[name]
+
+		if !ok {
+			continue
+		}
+
+		backends = append(backends, Backend[string]{
+			Name:   name,
+			Weight: weight,
+
+			Provider: name,
+		})
+	}
+
+	b, err := newBalancer("completer", backends, options...)
+	require.NoError(t, err)
+
+	return b
+}
+
+// picks acquires and immediately releases n backends
+func picks(t *testing.T, b *balancer[string], n int) []string {
+	t.Helper()
+
+	var result []string
+
+	for range n {
+		be, err := b.acquire(context.Background())
+		require.NoError(t, err)
+
+		b.release(context.Background(), be, time.Second, nil)
+
+		result = append(result, be.Name)
+	}
+
+	return result
+}
+
+func TestRoundRobin(t *testing.T) {
+	b := newTestBalancer(t, map[string]int{"a": 1, "b": 1, "c": 1})
+
+	require.Equal(t, []string{"a", "b", "c", "a", "b"}, picks(t, b, 5))
+}
+
+func TestWeighted(t *testing.T) {
+	b := newTestBalancer(t, map[string]int{"a": 3, "b": 1}, WithStrategy(StrategyWeighted))
+
+	require.Equal(t, []string{"a", "a", "b", "a", "a", "a", "b", "a"}, picks(t, b, 8))
+}
+
+func TestLeastRequests(t *testing.T) {
+	ctx := context.Background()
+
+	b := newTestBalancer(t, map[string]int{"a": 1, "b": 1}, WithStrategy(StrategyLeastRequests))
+
+	a, err := b.acquire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", a.Name)
+
+	next, err := b.acquire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "b", next.Name)
+
+	// equally loaded backends are picked by lower latency
+	b.release(ctx, a, 2*time.Second, nil)
+	b.release(ctx, next, time.Second, nil)
+
+	next, err = b.acquire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "b", next.Name)
+
+	next, err = b.acquire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", next.Name)
+}
+
+func TestUnhealthyBackendSkipped(t *testing.T) {
+	ctx := context.Background()
+	now := start
+
+	b := newTestBalancer(t, map[string]int{"a": 1, "b": 1}, WithMinRequests(2), WithCooldown(time.Minute))
+	b.now = func() time.Time { return now }
+
+	failure := &provider.Error{StatusCode: http.StatusInternalServerError}
+
+	// fail a twice, so its circuit opens
+	for range 4 {
+		be, err := b.acquire(ctx)
+		require.NoError(t, err)
+
+		var result error
+
+		if be.Name == "a" {
+			result = failure
+		}
+
+		b.release(ctx, be, time.Second, result)
+	}
+
+	require.Equal(t, []string{"b", "b", "b"}, picks(t, b, 3))
+
+	// after the cooldown a single probe is routed to a again
+	now = now.Add(time.Minute)
+
+	require.Contains(t, picks(t, b, 2), "a")
+	require.Equal(t, StateClosed, b.backends[0].breaker.state)
+}
+
+func TestNoHealthyBackend(t *testing.T) {
+	ctx := context.Background()
+
+	b := newTestBalancer(t, map[string]int{"a": 1}, WithMinRequests(1))
+
+	be, err := b.acquire(ctx)
+	require.NoError(t, err)
+
+	b.release(ctx, be, time.Second, errors.New("connection refused"))
+
+	_, err = b.acquire(ctx)
+
+	var perr *provider.Error
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, http.StatusServiceUnavailable, perr.StatusCode)
+}
+
+func TestCallerErrorsIgnored(t *testing.T) {
+	ctx := context.Background()
+
+	b := newTestBalancer(t, map[string]int{"a": 1, "b": 1}, WithMinRequests(1))
+
+	failure := &quota.Error{Type: quota.ErrorTypeTokens, Message: "quota exceeded"}
+
+	for range 10 {
+		be, err := b.acquire(ctx)
+		require.NoError(t, err)
+
+		b.release(ctx, be, time.Second, failure)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	be, err := b.acquire(canceled)
+	require.NoError(t, err)
+
+	b.release(canceled, be, time.Second, context.Canceled)
+
+	for _, be := range b.backends {
+		require.Equal(t, StateClosed, be.breaker.state)
+		require.Empty(t, be.breaker.results)
+	}
+}
+
+func TestCallerErrorReleasesProbe(t *testing.T) {
+	ctx := context.Background()
+
+	b := newTestBalancer(t, map[string]int{"a": 1})
+	b.backends[0].breaker.trip(start.Add(-time.Hour))
+
+	be, err := b.acquire(ctx)
+	require.NoError(t, err)
+
+	b.release(ctx, be, time.Second, &quota.Error{Type: quota.ErrorTypeQuota, Message: "quota exceeded"})
+
+	// the probe did not reach the backend, so the next request probes again
+	require.Equal(t, StateHalfOpen, b.backends[0].breaker.state)
+
+	be, err = b.acquire(ctx)
+	require.NoError(t, err)
+
+	b.release(ctx, be, time.Second, nil)
+	require.Equal(t, StateClosed, b.backends[0].breaker.state)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	backends := []Backend[string]{{Name: "a", Provider: "a"}}
+
+	for _, option := range []Option{
+		WithStrategy("random"),
+		WithThreshold(0),
+		WithThreshold(1.5),
+		WithMinRequests(0),
+		WithMinRequests(breakerWindow + 1),
+		WithCooldown(-time.Second),
+	} {
+		_, err := newBalancer("completer", backends, option)
+		require.Error(t, err)
+	}
+}
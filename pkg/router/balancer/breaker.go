@@ -0,0 +1,137 @@
+package balancer
+
+import (
+	"time"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+
+	case StateHalfOpen:
+		return "half-open"
+
+	case StateOpen:
+		return "open"
+
+	default:
+		return "unknown"
+	}
+}
+
+const breakerWindow = 20
+
+type breaker struct {
+	state State
+
+	opened  time.Time
+	probing bool
+
+	results []bool
+	cursor  int
+
+	latency time.Duration
+}
+
+func (b *breaker) available(now time.Time, cooldown time.Duration) bool {
+	switch b.state {
+	case StateClosed:
+		return true
+
+	case StateHalfOpen:
+		return !b.probing
+
+	case StateOpen:
+		return now.Sub(b.opened) >= cooldown
+	}
+
+	return false
+}
+
+func (b *breaker) acquire() {
+	if b.state == StateOpen {
+		b.state = StateHalfOpen
+	}
+
+	if b.state == StateHalfOpen {
+		b.probing = true
+	}
+}
+
+// release ends a request without a result, so a probe can be sent again
+func (b *breaker) release() {
+	b.probing = false
+}
+
+func (b *breaker) record(now time.Time, success bool, latency time.Duration, threshold float64, minRequests int) {
+	if b.latency == 0 {
+		b.latency = latency
+	} else {
+		b.latency = (b.latency*4 + latency) / 5
+	}
+
+	if b.state == StateHalfOpen {
+		b.probing = false
+
+		if success {
+			b.reset()
+		} else {
+			b.trip(now)
+		}
+
+		return
+	}
+
+	if b.state != StateClosed {
+		return
+	}
+
+	if len(b.results) < breakerWindow {
+		b.results = append(b.results, success)
+	} else {
+		b.results[b.cursor] = success
+		b.cursor = (b.cursor + 1) % breakerWindow
+	}
+
+	if len(b.results) < minRequests {
+		return
+	}
+
+	var failures int
+
+	for _, r := range b.results {
+		if !r {
+			failures++
+		}
+	}
+
+	if float64(failures)/float64(len(b.results)) >= threshold {
+		b.trip(now)
+	}
+}
+
+func (b *breaker) trip(now time.Time) {
+	b.state = StateOpen
+	b.opened = now
+	b.probing = false
+
+	b.results = nil
+	b.cursor = 0
+}
+
+func (b *breaker) reset() {
+	b.state = StateClosed
+	b.probing = false
+
+	b.results = nil
+	b.cursor = 0
+}
@@ -0,0 +1,102 @@
+package balancer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func TestBreakerOpensAtThreshold(t *testing.T) {
+	var b breaker
+
+	b.record(start, false, time.Second, 0.5, 4)
+	b.record(start, true, time.Second, 0.5, 4)
+	b.record(start, false, time.Second, 0.5, 4)
+
+	// below min requests the circuit stays closed at any failure rate
+	require.Equal(t, StateClosed, b.state)
+
+	b.record(start, true, time.Second, 0.5, 4)
+	require.Equal(t, StateOpen, b.state)
+	require.Equal(t, start, b.opened)
+}
+
+func TestBreakerStaysClosedBelowThreshold(t *testing.T) {
+	var b breaker
+
+	for i := range 20 {
+		b.record(start, i%3 != 0, time.Second, 0.5, 5)
+	}
+
+	require.Equal(t, StateClosed, b.state)
+	require.True(t, b.available(start, time.Minute))
+}
+
+func TestBreakerSlidingWindow(t *testing.T) {
+	var b breaker
+
+	for range breakerWindow {
+		b.record(start, true, time.Second, 0.5, 5)
+	}
+
+	// failures replace the oldest successes, the circuit opens once half of the window failed
+	for range breakerWindow/2 - 1 {
+		b.record(start, false, time.Second, 0.5, 5)
+	}
+
+	require.Equal(t, StateClosed, b.state)
+	require.Len(t, b.results, breakerWindow)
+
+	b.record(start, false, time.Second, 0.5, 5)
+	require.Equal(t, StateOpen, b.state)
+}
+
+func TestBreakerCooldownAndProbe(t *testing.T) {
+	var b breaker
+	b.trip(start)
+
+	cooldown := 30 * time.Second
+
+	require.False(t, b.available(start.Add(29*time.Second), cooldown))
+	require.True(t, b.available(start.Add(30*time.Second), cooldown))
+
+	b.acquire()
+
+	require.Equal(t, StateHalfOpen, b.state)
+	require.False(t, b.available(start.Add(31*time.Second), cooldown), "only a single probe is allowed")
+
+	b.record(start.Add(32*time.Second), true, time.Second, 0.5, 5)
+
+	require.Equal(t, StateClosed, b.state)
+	require.True(t, b.available(start.Add(32*time.Second), cooldown))
+	require.Empty(t, b.results)
+}
+
+func TestBreakerFailedProbe(t *testing.T) {
+	var b breaker
+	b.trip(start)
+
+	probe := start.Add(time.Minute)
+
+	b.acquire()
+	b.record(probe, false, time.Second, 0.5, 5)
+
+	require.Equal(t, StateOpen, b.state)
+	require.Equal(t, probe, b.opened)
+
+	require.False(t, b.available(probe.Add(29*time.Second), 30*time.Second))
+	require.True(t, b.available(probe.Add(30*time.Second), 30*time.Second))
+}
+
+func TestBreakerLatency(t *testing.T) {
+	var b breaker
+
+	b.record(start, true, 10*time.Second, 0.5, 5)
+	require.Equal(t, 10*time.Second, b.latency)
+
+	b.record(start, true, 5*time.Second, 0.5, 5)
+	require.Equal(t, 9*time.Second, b.latency)
+}
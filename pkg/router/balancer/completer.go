@@ -0,0 +1,40 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Completer struct {
+	balancer *balancer[provider.Completer]
+}
+
+func NewCompleter(backends []Backend[provider.Completer], options ...Option) (provider.Completer, error) {
+	b, err := newBalancer("completer", backends, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completer{
+		balancer: b,
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	b, err := c.balancer.acquire(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := b.Provider.Complete(ctx, messages, options)
+
+	c.balancer.release(ctx, b, time.Since(start), err)
+
+	return result, err
+}
@@ -0,0 +1,66 @@
+package balancer
+
+import (
+	"time"
+)
+
+type Strategy string
+
+const (
+	StrategyRoundRobin    Strategy = "roundrobin"
+	StrategyLeastRequests Strategy = "least"
+	StrategyWeighted      Strategy = "weighted"
+)
+
+const (
+	// DefaultThreshold is the failure rate within the recent requests that opens a circuit
+	DefaultThreshold = 0.5
+
+	// DefaultMinRequests is the number of recent requests needed before a circuit can open
+	DefaultMinRequests = 5
+
+	// DefaultCooldown is how long an open circuit waits before a probe request
+	DefaultCooldown = 30 * time.Second
+)
+
+type Config struct {
+	name     string
+	strategy Strategy
+
+	threshold   float64
+	minRequests int
+
+	cooldown time.Duration
+}
+
+type Option func(*Config)
+
+func WithName(name string) Option {
+	return func(c *Config) {
+		c.name = name
+	}
+}
+
+func WithStrategy(strategy Strategy) Option {
+	return func(c *Config) {
+		c.strategy = strategy
+	}
+}
+
+func WithThreshold(threshold float64) Option {
+	return func(c *Config) {
+		c.threshold = threshold
+	}
+}
+
+func WithMinRequests(val int) Option {
+	return func(c *Config) {
+		c.minRequests = val
+	}
+}
+
+func WithCooldown(val time.Duration) Option {
+	return func(c *Config) {
+		c.cooldown = val
+	}
+}
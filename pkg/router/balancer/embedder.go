@@ -0,0 +1,40 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Embedder struct {
+	balancer *balancer[provider.Embedder]
+}
+
+func NewEmbedder(backends []Backend[provider.Embedder], options ...Option) (provider.Embedder, error) {
+	b, err := newBalancer("embedder", backends, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Embedder{
+		balancer: b,
+	}, nil
+}
+
+func (r *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	b, err := r.balancer.acquire(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := b.Provider.Embed(ctx, texts, options)
+
+	r.balancer.release(ctx, b, time.Since(start), err)
+
+	return result, err
+}
@@ -0,0 +1,40 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Renderer struct {
+	balancer *balancer[provider.Renderer]
+}
+
+func NewRenderer(backends []Backend[provider.Renderer], options ...Option) (provider.Renderer, error) {
+	b, err := newBalancer("renderer", backends, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		balancer: b,
+	}, nil
+}
+
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
+	b, err := r.balancer.acquire(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := b.Provider.Render(ctx, input, options)
+
+	r.balancer.release(ctx, b, time.Since(start), err)
+
+	return result, err
+}
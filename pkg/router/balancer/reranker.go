@@ -0,0 +1,40 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Reranker struct {
+	balancer *balancer[provider.Reranker]
+}
+
+func NewReranker(backends []Backend[provider.Reranker], options ...Option) (provider.Reranker, error) {
+	b, err := newBalancer("reranker", backends, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reranker{
+		balancer: b,
+	}, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, inputs []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	b, err := r.balancer.acquire(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := b.Provider.Rerank(ctx, query, inputs, options)
+
+	r.balancer.release(ctx, b, time.Since(start), err)
+
+	return result, err
+}
@@ -0,0 +1,51 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router"
+)
+
+type Synthesizer struct {
+	balancer *balancer[provider.Synthesizer]
+}
+
+func NewSynthesizer(backends []Backend[provider.Synthesizer], options ...Option) (provider.Synthesizer, error) {
+	b, err := newBalancer("synthesizer", backends, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Synthesizer{
+		balancer: b,
+	}, nil
+}
+
+func (r *Synthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	var synthesizers []provider.Synthesizer
+
+	for _, b := range r.balancer.backends {
+		synthesizers = append(synthesizers, b.Provider)
+	}
+
+	return router.Voices(ctx, synthesizers...)
+}
+
+func (r *Synthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	b, err := r.balancer.acquire(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := b.Provider.Synthesize(ctx, content, options)
+
+	r.balancer.release(ctx, b, time.Since(start), err)
+
+	return result, err
+}
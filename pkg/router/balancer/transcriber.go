@@ -0,0 +1,40 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Transcriber struct {
+	balancer *balancer[provider.Transcriber]
+}
+
+func NewTranscriber(backends []Backend[provider.Transcriber], options ...Option) (provider.Transcriber, error) {
+	b, err := newBalancer("transcriber", backends, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transcriber{
+		balancer: b,
+	}, nil
+}
+
+func (r *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	b, err := r.balancer.acquire(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := b.Provider.Transcribe(ctx, input, options)
+
+	r.balancer.release(ctx, b, time.Since(start), err)
+
+	return result, err
+}
@@ -0,0 +1,60 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router"
+)
+
+type Completer struct {
+	completers []provider.Completer
+}
+
+func NewCompleter(completer ...provider.Completer) (provider.Completer, error) {
+	if len(completer) == 0 {
+		return nil, errors.New("no completers configured")
+	}
+
+	c := &Completer{
+		completers: completer,
+	}
+
+	return c, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil {
+		options = new(provider.CompleteOptions)
+	}
+
+	var errs []error
+
+	for _, p := range c.completers {
+		var streamed bool
+
+		input := *options
+
+		if options.Stream != nil {
+			input.Stream = func(ctx context.Context, completion provider.Completion) error {
+				streamed = true
+				return options.Stream(ctx, completion)
+			}
+		}
+
+		result, err := p.Complete(ctx, messages, &input)
+
+		if err == nil {
+			return result, nil
+		}
+
+		if streamed || router.IsCallerError(ctx, err) || !router.IsRetryable(err) {
+			return nil, err
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
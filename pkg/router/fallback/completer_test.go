@@ -0,0 +1,170 @@
+package fallback_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+	"github.com/adrianliechti/llama/pkg/router/fallback"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCompleter struct {
+	name string
+
+	chunks []string
+	err    error
+
+	calls int
+}
+
+func (c *testCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.calls++
+
+	for _, chunk := range c.chunks {
+		if options.Stream == nil {
+			break
+		}
+
+		if err := options.Stream(ctx, provider.Completion{
+			Message: provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: chunk,
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &provider.Completion{
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: c.name,
+		},
+	}, nil
+}
+
+func complete(t *testing.T, completers ...*testCompleter) (*provider.Completion, error) {
+	t.Helper()
+
+	var providers []provider.Completer
+
+	for _, c := range completers {
+		providers = append(providers, c)
+	}
+
+	completer, err := fallback.NewCompleter(providers...)
+	require.NoError(t, err)
+
+	return completer.Complete(context.Background(), nil, nil)
+}
+
+func TestCompleterOrder(t *testing.T) {
+	a := &testCompleter{name: "a", err: &provider.Error{StatusCode: http.StatusServiceUnavailable}}
+	b := &testCompleter{name: "b", err: errors.New("connection refused")}
+	c := &testCompleter{name: "c"}
+	d := &testCompleter{name: "d"}
+
+	result, err := complete(t, a, b, c, d)
+	require.NoError(t, err)
+
+	require.Equal(t, "c", result.Message.Content)
+	require.Equal(t, []int{1, 1, 1, 0}, []int{a.calls, b.calls, c.calls, d.calls})
+}
+
+func TestCompleterAllFailed(t *testing.T) {
+	a := &testCompleter{err: &provider.Error{StatusCode: http.StatusTooManyRequests, Message: "rate limited"}}
+	b := &testCompleter{err: &provider.Error{StatusCode: http.StatusBadGateway, Message: "bad gateway"}}
+
+	_, err := complete(t, a, b)
+
+	require.ErrorContains(t, err, "rate limited")
+	require.ErrorContains(t, err, "bad gateway")
+}
+
+func TestCompleterNotRetryable(t *testing.T) {
+	for _, failure := range []error{
+		&provider.Error{StatusCode: http.StatusBadRequest, Message: "invalid request"},
+		&quota.Error{Type: quota.ErrorTypeTokens, Message: "quota exceeded"},
+		context.Canceled,
+	} {
+		a := &testCompleter{err: failure}
+		b := &testCompleter{name: "b"}
+
+		_, err := complete(t, a, b)
+
+		require.Equal(t, failure, err)
+		require.Equal(t, 0, b.calls)
+	}
+}
+
+func TestCompleterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a := &testCompleter{err: errors.New("request aborted")}
+	b := &testCompleter{name: "b"}
+
+	completer, err := fallback.NewCompleter(a, b)
+	require.NoError(t, err)
+
+	cancel()
+
+	_, err = completer.Complete(ctx, nil, nil)
+	require.EqualError(t, err, "request aborted")
+	require.Equal(t, 0, b.calls)
+}
+
+func TestCompleterStream(t *testing.T) {
+	a := &testCompleter{err: &provider.Error{StatusCode: http.StatusServiceUnavailable}}
+	b := &testCompleter{name: "b", chunks: []string{"hello", " world"}}
+
+	var chunks []string
+
+	completer, err := fallback.NewCompleter(a, b)
+	require.NoError(t, err)
+
+	result, err := completer.Complete(context.Background(), nil, &provider.CompleteOptions{
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			chunks = append(chunks, completion.Message.Content)
+			return nil
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "b", result.Message.Content)
+
+	// errors before the first chunk fall back silently
+	require.Equal(t, []string{"hello", " world"}, chunks)
+}
+
+func TestCompleterMidStreamError(t *testing.T) {
+	failure := &provider.Error{StatusCode: http.StatusServiceUnavailable, Message: "connection reset"}
+
+	a := &testCompleter{chunks: []string{"hello"}, err: failure}
+	b := &testCompleter{name: "b", chunks: []string{"hi"}}
+
+	var chunks []string
+
+	completer, err := fallback.NewCompleter(a, b)
+	require.NoError(t, err)
+
+	_, err = completer.Complete(context.Background(), nil, &provider.CompleteOptions{
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			chunks = append(chunks, completion.Message.Content)
+			return nil
+		},
+	})
+
+	// once a chunk reached the caller the answer cannot switch to another completer
+	require.Equal(t, failure, err)
+	require.Equal(t, []string{"hello"}, chunks)
+	require.Equal(t, 0, b.calls)
+}
@@ -0,0 +1,30 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Embedder struct {
+	embedders []provider.Embedder
+}
+
+func NewEmbedder(embedder ...provider.Embedder) (provider.Embedder, error) {
+	if len(embedder) == 0 {
+		return nil, errors.New("no embedders configured")
+	}
+
+	r := &Embedder{
+		embedders: embedder,
+	}
+
+	return r, nil
+}
+
+func (r *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	return try(ctx, r.embedders, func(p provider.Embedder) (*provider.Embedding, error) {
+		return p.Embed(ctx, texts, options)
+	})
+}
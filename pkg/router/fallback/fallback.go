@@ -0,0 +1,29 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/router"
+)
+
+func try[T, R any](ctx context.Context, providers []T, fn func(T) (R, error)) (R, error) {
+	var errs []error
+
+	for _, p := range providers {
+		result, err := fn(p)
+
+		if err == nil {
+			return result, nil
+		}
+
+		if router.IsCallerError(ctx, err) || !router.IsRetryable(err) {
+			return result, err
+		}
+
+		errs = append(errs, err)
+	}
+
+	var empty R
+	return empty, errors.Join(errs...)
+}
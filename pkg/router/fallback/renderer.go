@@ -0,0 +1,72 @@
+package fallback
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Renderer struct {
+	renderers []provider.Renderer
+}
+
+func NewRenderer(renderer ...provider.Renderer) (provider.Renderer, error) {
+	if len(renderer) == 0 {
+		return nil, errors.New("no renderers configured")
+	}
+
+	r := &Renderer{
+		renderers: renderer,
+	}
+
+	return r, nil
+}
+
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
+	if options == nil {
+		options = new(provider.RenderOptions)
+	}
+
+	image, err := readFile(options.Image)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mask, err := readFile(options.Mask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return try(ctx, r.renderers, func(p provider.Renderer) (*provider.Rendering, error) {
+		o := *options
+		o.Image = newFile(options.Image, image)
+		o.Mask = newFile(options.Mask, mask)
+
+		return p.Render(ctx, input, &o)
+	})
+}
+
+// readFile buffers the content of an optional file, so every attempt can read it again
+func readFile(f *provider.File) ([]byte, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	return io.ReadAll(f.Content)
+}
+
+func newFile(f *provider.File, data []byte) *provider.File {
+	if f == nil {
+		return nil
+	}
+
+	file := *f
+	file.Content = bytes.NewReader(data)
+
+	return &file
+}
@@ -0,0 +1,55 @@
+package fallback_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router/fallback"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readingRenderer reads the image and mask of every request before it answers
+type readingRenderer struct {
+	err error
+
+	images []string
+	masks  []string
+}
+
+func (r *readingRenderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
+	image, _ := io.ReadAll(options.Image.Content)
+	mask, _ := io.ReadAll(options.Mask.Content)
+
+	r.images = append(r.images, string(image))
+	r.masks = append(r.masks, string(mask))
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return &provider.Rendering{}, nil
+}
+
+func TestRendererRetryReadsFiles(t *testing.T) {
+	a := &readingRenderer{err: &provider.Error{StatusCode: http.StatusServiceUnavailable}}
+	b := &readingRenderer{}
+
+	renderer, err := fallback.NewRenderer(a, b)
+	require.NoError(t, err)
+
+	_, err = renderer.Render(context.Background(), "add a hat", &provider.RenderOptions{
+		Image: &provider.File{Name: "image.png", Content: strings.NewReader("image")},
+		Mask:  &provider.File{Name: "mask.png", Content: strings.NewReader("mask")},
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"image"}, a.images)
+	require.Equal(t, []string{"image"}, b.images)
+	require.Equal(t, []string{"mask"}, b.masks)
+}
@@ -0,0 +1,30 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Reranker struct {
+	rerankers []provider.Reranker
+}
+
+func NewReranker(reranker ...provider.Reranker) (provider.Reranker, error) {
+	if len(reranker) == 0 {
+		return nil, errors.New("no rerankers configured")
+	}
+
+	r := &Reranker{
+		rerankers: reranker,
+	}
+
+	return r, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, inputs []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	return try(ctx, r.rerankers, func(p provider.Reranker) ([]provider.Ranking, error) {
+		return p.Rerank(ctx, query, inputs, options)
+	})
+}
@@ -0,0 +1,36 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router"
+)
+
+type Synthesizer struct {
+	synthesizers []provider.Synthesizer
+}
+
+func NewSynthesizer(synthesizer ...provider.Synthesizer) (provider.Synthesizer, error) {
+	if len(synthesizer) == 0 {
+		return nil, errors.New("no synthesizers configured")
+	}
+
+	r := &Synthesizer{
+		synthesizers: synthesizer,
+	}
+
+	return r, nil
+}
+
+// Voices lists the voices of all synthesizers, as any of them may end up serving a request
+func (r *Synthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	return router.Voices(ctx, r.synthesizers...)
+}
+
+func (r *Synthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	return try(ctx, r.synthesizers, func(p provider.Synthesizer) (*provider.Synthesis, error) {
+		return p.Synthesize(ctx, content, options)
+	})
+}
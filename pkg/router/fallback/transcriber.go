@@ -0,0 +1,41 @@
+package fallback
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Transcriber struct {
+	transcribers []provider.Transcriber
+}
+
+func NewTranscriber(transcriber ...provider.Transcriber) (provider.Transcriber, error) {
+	if len(transcriber) == 0 {
+		return nil, errors.New("no transcribers configured")
+	}
+
+	r := &Transcriber{
+		transcribers: transcriber,
+	}
+
+	return r, nil
+}
+
+func (r *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	data, err := io.ReadAll(input.Content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return try(ctx, r.transcribers, func(p provider.Transcriber) (*provider.Transcription, error) {
+		file := input
+		file.Content = bytes.NewReader(data)
+
+		return p.Transcribe(ctx, file, options)
+	})
+}
@@ -2,12 +2,13 @@ package roundrobin
 
 import (
 	"context"
-	"math/rand"
+	"sync/atomic"
 
 	"github.com/adrianliechti/llama/pkg/provider"
 )
 
 type Completer struct {
+	counter    atomic.Uint64
 	completers []provider.Completer
 }
 
@@ -20,7 +21,7 @@ func NewCompleter(completer ...provider.Completer) (provider.Completer, error) {
 }
 
 func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
-	index := rand.Intn(len(c.completers))
+	index := (c.counter.Add(1) - 1) % uint64(len(c.completers))
 	provider := c.completers[index]
 
 	return provider.Complete(ctx, messages, options)
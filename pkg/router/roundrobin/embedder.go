@@ -0,0 +1,28 @@
+package roundrobin
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Embedder struct {
+	counter   atomic.Uint64
+	embedders []provider.Embedder
+}
+
+func NewEmbedder(embedder ...provider.Embedder) (provider.Embedder, error) {
+	r := &Embedder{
+		embedders: embedder,
+	}
+
+	return r, nil
+}
+
+func (r *Embedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	index := (r.counter.Add(1) - 1) % uint64(len(r.embedders))
+	provider := r.embedders[index]
+
+	return provider.Embed(ctx, texts, options)
+}
@@ -0,0 +1,28 @@
+package roundrobin
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Renderer struct {
+	counter   atomic.Uint64
+	renderers []provider.Renderer
+}
+
+func NewRenderer(renderer ...provider.Renderer) (provider.Renderer, error) {
+	r := &Renderer{
+		renderers: renderer,
+	}
+
+	return r, nil
+}
+
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
+	index := (r.counter.Add(1) - 1) % uint64(len(r.renderers))
+	provider := r.renderers[index]
+
+	return provider.Render(ctx, input, options)
+}
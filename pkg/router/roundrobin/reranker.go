@@ -0,0 +1,28 @@
+package roundrobin
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Reranker struct {
+	counter   atomic.Uint64
+	rerankers []provider.Reranker
+}
+
+func NewReranker(reranker ...provider.Reranker) (provider.Reranker, error) {
+	r := &Reranker{
+		rerankers: reranker,
+	}
+
+	return r, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, inputs []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	index := (r.counter.Add(1) - 1) % uint64(len(r.rerankers))
+	provider := r.rerankers[index]
+
+	return provider.Rerank(ctx, query, inputs, options)
+}
@@ -0,0 +1,33 @@
+package roundrobin
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router"
+)
+
+type Synthesizer struct {
+	counter      atomic.Uint64
+	synthesizers []provider.Synthesizer
+}
+
+func NewSynthesizer(synthesizer ...provider.Synthesizer) (provider.Synthesizer, error) {
+	r := &Synthesizer{
+		synthesizers: synthesizer,
+	}
+
+	return r, nil
+}
+
+func (r *Synthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	return router.Voices(ctx, r.synthesizers...)
+}
+
+func (r *Synthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	index := (r.counter.Add(1) - 1) % uint64(len(r.synthesizers))
+	provider := r.synthesizers[index]
+
+	return provider.Synthesize(ctx, content, options)
+}
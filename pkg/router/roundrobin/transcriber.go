@@ -0,0 +1,28 @@
+package roundrobin
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type Transcriber struct {
+	counter      atomic.Uint64
+	transcribers []provider.Transcriber
+}
+
+func NewTranscriber(transcriber ...provider.Transcriber) (provider.Transcriber, error) {
+	r := &Transcriber{
+		transcribers: transcriber,
+	}
+
+	return r, nil
+}
+
+func (r *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	index := (r.counter.Add(1) - 1) % uint64(len(r.transcribers))
+	provider := r.transcribers[index]
+
+	return provider.Transcribe(ctx, input, options)
+}
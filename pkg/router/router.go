@@ -0,0 +1,86 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+)
+
+// IsCallerError reports whether err is caused by the caller rather than the provider,
+// like an exhausted quota or a canceled or expired request
+func IsCallerError(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if ctx.Err() != nil {
+		return true
+	}
+
+	var qerr *quota.Error
+	return errors.As(err, &qerr)
+}
+
+// IsRetryable reports whether another provider might succeed where err failed
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var qerr *quota.Error
+
+	if errors.As(err, &qerr) {
+		return false
+	}
+
+	var perr *provider.Error
+
+	if errors.As(err, &perr) {
+		if perr.StatusCode == http.StatusTooManyRequests || perr.StatusCode == http.StatusRequestTimeout {
+			return true
+		}
+
+		return perr.StatusCode >= 500
+	}
+
+	return true
+}
+
+// Voices returns the union of the voices of all synthesizers, skipping the ones that fail unless all of them do
+func Voices(ctx context.Context, synthesizers ...provider.Synthesizer) ([]provider.Voice, error) {
+	var result []provider.Voice
+	var errs []error
+
+	seen := make(map[string]bool)
+
+	for _, s := range synthesizers {
+		voices, err := s.Voices(ctx)
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, v := range voices {
+			if seen[v.ID] {
+				continue
+			}
+
+			seen[v.ID] = true
+			result = append(result, v)
+		}
+	}
+
+	if len(errs) > 0 && len(errs) == len(synthesizers) {
+		return nil, errors.Join(errs...)
+	}
+
+	return result, nil
+}
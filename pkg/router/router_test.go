@@ -0,0 +1,41 @@
+package router_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/router"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSynthesizer struct {
+	voices []provider.Voice
+	err    error
+}
+
+func (s *testSynthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	return s.voices, s.err
+}
+
+func (s *testSynthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestVoices(t *testing.T) {
+	a := &testSynthesizer{voices: []provider.Voice{{ID: "alloy"}, {ID: "echo"}}}
+	b := &testSynthesizer{voices: []provider.Voice{{ID: "echo"}, {ID: "nova"}}}
+	c := &testSynthesizer{err: errors.New("unavailable")}
+
+	voices, err := router.Voices(context.Background(), a, c, b)
+	require.NoError(t, err)
+
+	require.Equal(t, []provider.Voice{{ID: "alloy"}, {ID: "echo"}, {ID: "nova"}}, voices)
+}
+
+func TestVoicesFailed(t *testing.T) {
+	_, err := router.Voices(context.Background(), &testSynthesizer{err: errors.New("unavailable")}, &testSynthesizer{err: errors.New("unavailable")})
+	require.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/adrianliechti/llama/pkg/thread"
+)
+
+var _ thread.Store = &Store{}
+
+type Store struct {
+	mu sync.Mutex
+
+	path string
+}
+
+type document struct {
+	Thread thread.Thread `json:"thread"`
+
+	Messages []thread.Message `json:"messages,omitempty"`
+	Runs     []thread.Run     `json:"runs,omitempty"`
+}
+
+func New(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		path: path,
+	}, nil
+}
+
+func (s *Store) CreateThread(ctx context.Context, t thread.Thread) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write(&document{
+		Thread: t,
+	})
+}
+
+func (s *Store) GetThread(ctx context.Context, id string) (*thread.Thread, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &d.Thread, nil
+}
+
+func (s *Store) DeleteThread(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.filePath(id)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return thread.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) CreateMessage(ctx context.Context, m thread.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read(m.Thread)
+
+	if err != nil {
+		return err
+	}
+
+	d.Messages = append(d.Messages, m)
+
+	return s.write(d)
+}
+
+func (s *Store) ListMessages(ctx context.Context, id string) ([]thread.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Messages, nil
+}
+
+func (s *Store) CreateRun(ctx context.Context, r thread.Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read(r.Thread)
+
+	if err != nil {
+		return err
+	}
+
+	d.Runs = append(d.Runs, r)
+
+	return s.write(d)
+}
+
+func (s *Store) UpdateRun(ctx context.Context, r thread.Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read(r.Thread)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range d.Runs {
+		if d.Runs[i].ID == r.ID {
+			d.Runs[i] = r
+			return s.write(d)
+		}
+	}
+
+	return thread.ErrNotFound
+}
+
+func (s *Store) GetRun(ctx context.Context, id, run string) (*thread.Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range d.Runs {
+		if r.ID == run {
+			return &r, nil
+		}
+	}
+
+	return nil, thread.ErrNotFound
+}
+
+func (s *Store) ListRuns(ctx context.Context, id string) ([]thread.Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Runs, nil
+}
+
+func (s *Store) filePath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return "", thread.ErrNotFound
+	}
+
+	return filepath.Join(s.path, id+".json"), nil
+}
+
+func (s *Store) read(id string) (*document, error) {
+	path, err := s.filePath(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, thread.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	var d document
+
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func (s *Store) write(d *document) error {
+	path, err := s.filePath(d.Thread.ID)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(d)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(s.path, ".tmp-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,100 @@
+package file_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/thread"
+	"github.com/adrianliechti/llama/pkg/thread/file"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	s, err := file.New(path)
+	require.NoError(t, err)
+
+	created := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	temperature := float32(0.5)
+
+	require.NoError(t, s.CreateThread(ctx, thread.Thread{ID: "thread_1", Owner: "alice", Metadata: map[string]string{"topic": "weather"}, CreatedAt: created}))
+
+	require.NoError(t, s.CreateMessage(ctx, thread.Message{ID: "msg_1", Thread: "thread_1", Role: provider.MessageRoleUser, Content: "hello", Files: []string{"file-1"}}))
+	require.NoError(t, s.CreateMessage(ctx, thread.Message{ID: "msg_2", Thread: "thread_1", Role: provider.MessageRoleAssistant, ToolCalls: []provider.ToolCall{{ID: "call_1", Name: "weather", Arguments: "{}"}}}))
+	require.NoError(t, s.CreateMessage(ctx, thread.Message{ID: "msg_3", Thread: "thread_1", Role: provider.MessageRoleTool, Tool: "call_1", Content: "sunny"}))
+
+	run := thread.Run{
+		ID:     "run_1",
+		Thread: "thread_1",
+
+		Tools:       []provider.Tool{{Name: "weather"}},
+		ToolChoice:  &provider.ToolChoice{Mode: provider.ToolChoiceModeAuto},
+		Temperature: &temperature,
+
+		Status:    thread.RunStatusRequiresAction,
+		ToolCalls: []provider.ToolCall{{ID: "call_1", Name: "weather"}},
+
+		CreatedAt: created,
+	}
+
+	require.NoError(t, s.CreateRun(ctx, run))
+
+	// a new store on the same path reads everything back
+	s, err = file.New(path)
+	require.NoError(t, err)
+
+	result, err := s.GetThread(ctx, "thread_1")
+	require.NoError(t, err)
+	require.Equal(t, "alice", result.Owner)
+	require.Equal(t, "weather", result.Metadata["topic"])
+	require.True(t, created.Equal(result.CreatedAt))
+
+	messages, err := s.ListMessages(ctx, "thread_1")
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	require.Equal(t, []string{"msg_1", "msg_2", "msg_3"}, []string{messages[0].ID, messages[1].ID, messages[2].ID})
+	require.Equal(t, []string{"file-1"}, messages[0].Files)
+	require.Equal(t, "weather", messages[1].ToolCalls[0].Name)
+	require.Equal(t, "call_1", messages[2].Tool)
+
+	stored, err := s.GetRun(ctx, "thread_1", "run_1")
+	require.NoError(t, err)
+	require.Equal(t, run.Tools, stored.Tools)
+	require.Equal(t, run.ToolChoice, stored.ToolChoice)
+	require.Equal(t, run.Temperature, stored.Temperature)
+	require.Equal(t, run.ToolCalls, stored.ToolCalls)
+
+	stored.Status = thread.RunStatusCompleted
+	stored.ToolCalls = nil
+	require.NoError(t, s.UpdateRun(ctx, *stored))
+
+	runs, err := s.ListRuns(ctx, "thread_1")
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, thread.RunStatusCompleted, runs[0].Status)
+
+	require.ErrorIs(t, s.UpdateRun(ctx, thread.Run{ID: "run_2", Thread: "thread_1"}), thread.ErrNotFound)
+
+	require.NoError(t, s.DeleteThread(ctx, "thread_1"))
+
+	_, err = s.GetThread(ctx, "thread_1")
+	require.ErrorIs(t, err, thread.ErrNotFound)
+	require.ErrorIs(t, s.DeleteThread(ctx, "thread_1"), thread.ErrNotFound)
+}
+
+func TestStoreInvalidID(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := file.New(t.TempDir())
+	require.NoError(t, err)
+
+	for _, id := range []string{"", "../thread", "a/b", `a\b`} {
+		_, err := s.GetThread(ctx, id)
+		require.ErrorIs(t, err, thread.ErrNotFound)
+	}
+}
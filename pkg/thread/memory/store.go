@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/adrianliechti/llama/pkg/thread"
+)
+
+var _ thread.Store = &Store{}
+
+type Store struct {
+	mu sync.RWMutex
+
+	threads  map[string]thread.Thread
+	messages map[string][]thread.Message
+	runs     map[string][]thread.Run
+}
+
+func New() (*Store, error) {
+	return &Store{
+		threads:  make(map[string]thread.Thread),
+		messages: make(map[string][]thread.Message),
+		runs:     make(map[string][]thread.Run),
+	}, nil
+}
+
+func (s *Store) CreateThread(ctx context.Context, t thread.Thread) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.threads[t.ID] = t
+	return nil
+}
+
+func (s *Store) GetThread(ctx context.Context, id string) (*thread.Thread, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.threads[id]
+
+	if !ok {
+		return nil, thread.ErrNotFound
+	}
+
+	return &t, nil
+}
+
+func (s *Store) DeleteThread(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.threads[id]; !ok {
+		return thread.ErrNotFound
+	}
+
+	delete(s.threads, id)
+	delete(s.messages, id)
+	delete(s.runs, id)
+
+	return nil
+}
+
+func (s *Store) CreateMessage(ctx context.Context, m thread.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.threads[m.Thread]; !ok {
+		return thread.ErrNotFound
+	}
+
+	s.messages[m.Thread] = append(s.messages[m.Thread], m)
+	return nil
+}
+
+func (s *Store) ListMessages(ctx context.Context, id string) ([]thread.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.threads[id]; !ok {
+		return nil, thread.ErrNotFound
+	}
+
+	return append([]thread.Message(nil), s.messages[id]...), nil
+}
+
+func (s *Store) CreateRun(ctx context.Context, r thread.Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.threads[r.Thread]; !ok {
+		return thread.ErrNotFound
+	}
+
+	s.runs[r.Thread] = append(s.runs[r.Thread], r)
+	return nil
+}
+
+func (s *Store) UpdateRun(ctx context.Context, r thread.Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := s.runs[r.Thread]
+
+	for i := range runs {
+		if runs[i].ID == r.ID {
+			runs[i] = r
+			return nil
+		}
+	}
+
+	return thread.ErrNotFound
+}
+
+func (s *Store) GetRun(ctx context.Context, id, run string) (*thread.Run, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range s.runs[id] {
+		if r.ID == run {
+			return &r, nil
+		}
+	}
+
+	return nil, thread.ErrNotFound
+}
+
+func (s *Store) ListRuns(ctx context.Context, id string) ([]thread.Run, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.threads[id]; !ok {
+		return nil, thread.ErrNotFound
+	}
+
+	return append([]thread.Run(nil), s.runs[id]...), nil
+}
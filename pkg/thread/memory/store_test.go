@@ -0,0 +1,75 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/thread"
+	"github.com/adrianliechti/llama/pkg/thread/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := memory.New()
+	require.NoError(t, err)
+
+	created := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, s.CreateThread(ctx, thread.Thread{ID: "thread_1", Owner: "alice", CreatedAt: created}))
+
+	result, err := s.GetThread(ctx, "thread_1")
+	require.NoError(t, err)
+	require.Equal(t, "alice", result.Owner)
+
+	require.NoError(t, s.CreateMessage(ctx, thread.Message{ID: "msg_1", Thread: "thread_1", Role: provider.MessageRoleUser, Content: "hello", Files: []string{"file-1"}}))
+	require.NoError(t, s.CreateMessage(ctx, thread.Message{ID: "msg_2", Thread: "thread_1", Role: provider.MessageRoleAssistant, ToolCalls: []provider.ToolCall{{ID: "call_1", Name: "weather"}}}))
+
+	messages, err := s.ListMessages(ctx, "thread_1")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.Equal(t, []string{"file-1"}, messages[0].Files)
+	require.Equal(t, "call_1", messages[1].ToolCalls[0].ID)
+
+	run := thread.Run{ID: "run_1", Thread: "thread_1", Status: thread.RunStatusQueued}
+	require.NoError(t, s.CreateRun(ctx, run))
+
+	run.Status = thread.RunStatusCompleted
+	require.NoError(t, s.UpdateRun(ctx, run))
+
+	stored, err := s.GetRun(ctx, "thread_1", "run_1")
+	require.NoError(t, err)
+	require.Equal(t, thread.RunStatusCompleted, stored.Status)
+
+	runs, err := s.ListRuns(ctx, "thread_1")
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	require.NoError(t, s.DeleteThread(ctx, "thread_1"))
+
+	_, err = s.GetThread(ctx, "thread_1")
+	require.ErrorIs(t, err, thread.ErrNotFound)
+
+	_, err = s.ListMessages(ctx, "thread_1")
+	require.ErrorIs(t, err, thread.ErrNotFound)
+
+	require.ErrorIs(t, s.DeleteThread(ctx, "thread_1"), thread.ErrNotFound)
+}
+
+func TestStoreNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := memory.New()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, s.CreateMessage(ctx, thread.Message{ID: "msg_1", Thread: "missing"}), thread.ErrNotFound)
+	require.ErrorIs(t, s.CreateRun(ctx, thread.Run{ID: "run_1", Thread: "missing"}), thread.ErrNotFound)
+	require.ErrorIs(t, s.UpdateRun(ctx, thread.Run{ID: "run_1", Thread: "missing"}), thread.ErrNotFound)
+
+	_, err = s.GetRun(ctx, "missing", "run_1")
+	require.ErrorIs(t, err, thread.ErrNotFound)
+}
@@ -0,0 +1,95 @@
+package thread
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type Store interface {
+	CreateThread(ctx context.Context, t Thread) error
+	GetThread(ctx context.Context, id string) (*Thread, error)
+	DeleteThread(ctx context.Context, id string) error
+
+	CreateMessage(ctx context.Context, m Message) error
+	ListMessages(ctx context.Context, thread string) ([]Message, error)
+
+	CreateRun(ctx context.Context, r Run) error
+	UpdateRun(ctx context.Context, r Run) error
+	GetRun(ctx context.Context, thread, id string) (*Run, error)
+	ListRuns(ctx context.Context, thread string) ([]Run, error)
+}
+
+type Thread struct {
+	ID    string
+	Owner string
+
+	Metadata map[string]string
+
+	CreatedAt time.Time
+}
+
+type Message struct {
+	ID     string
+	Thread string
+	Run    string
+
+	Role    provider.MessageRole
+	Content string
+
+	// Files are the ids of the attached files
+	Files []string
+
+	// Tool is the id of the tool call answered by a tool message
+	Tool      string
+	ToolCalls []provider.ToolCall
+
+	Metadata map[string]string
+
+	CreatedAt time.Time
+}
+
+type RunStatus string
+
+const (
+	RunStatusQueued         RunStatus = "queued"
+	RunStatusInProgress     RunStatus = "in_progress"
+	RunStatusRequiresAction RunStatus = "requires_action"
+	RunStatusCompleted      RunStatus = "completed"
+	RunStatusFailed         RunStatus = "failed"
+	RunStatusCancelled      RunStatus = "cancelled"
+)
+
+type Run struct {
+	ID     string
+	Thread string
+
+	Model        string
+	Instructions string
+
+	Tools      []provider.Tool
+	ToolChoice *provider.ToolChoice
+
+	Temperature *float32
+
+	Status RunStatus
+	Error  string
+
+	// ToolCalls are the calls awaiting their outputs while the run requires action
+	ToolCalls []provider.ToolCall
+
+	Usage *provider.Usage
+
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+}
+
+// Active reports whether the run still executes or waits for tool outputs
+func (r Run) Active() bool {
+	return r.Status == RunStatusQueued || r.Status == RunStatusInProgress || r.Status == RunStatusRequiresAction
+}
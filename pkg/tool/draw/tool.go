@@ -71,12 +71,19 @@ func (t *Tool) Execute(ctx context.Context, parameters map[string]any) (any, err
 
 	options := &provider.RenderOptions{}
 
-	image, err := t.renderer.Render(ctx, prompt, options)
+	rendering, err := t.renderer.Render(ctx, prompt, options)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if len(rendering.Images) == 0 {
+		return nil, errors.New("no image rendered")
+	}
+
+	image := rendering.Images[0]
+	defer image.Content.Close()
+
 	name := uuid.New().String() + ".png"
 
 	os.MkdirAll(filepath.Join("public", "files"), 0755)
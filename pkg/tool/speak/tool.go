@@ -70,7 +70,9 @@ func (t *Tool) Execute(ctx context.Context, parameters map[string]any) (any, err
 		return nil, errors.New("missing prompt parameter")
 	}
 
-	options := &provider.SynthesizeOptions{}
+	options := &provider.SynthesizeOptions{
+		Format: provider.SpeechFormatWAV,
+	}
 
 	synthesis, err := t.synthesizer.Synthesize(ctx, prompt, options)
 
@@ -78,6 +80,8 @@ func (t *Tool) Execute(ctx context.Context, parameters map[string]any) (any, err
 		return nil, err
 	}
 
+	defer synthesis.Content.Close()
+
 	name := uuid.New().String() + ".wav"
 
 	os.MkdirAll(filepath.Join("public", "files"), 0755)
@@ -0,0 +1,102 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/adrianliechti/llama/config"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	*config.Config
+	http.Handler
+}
+
+func New(cfg *config.Config) (*Handler, error) {
+	mux := chi.NewMux()
+
+	h := &Handler{
+		Config:  cfg,
+		Handler: mux,
+	}
+
+	h.Attach(mux)
+	return h, nil
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Post("/messages", h.handleMessages)
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(v)
+}
+
+// https://docs.anthropic.com/en/api/errors
+func writeError(w http.ResponseWriter, code int, err error) {
+	var providerErr *provider.Error
+
+	if errors.As(err, &providerErr) && providerErr.StatusCode >= 400 {
+		code = providerErr.StatusCode
+
+		// rejected upstream credentials are no fault of the caller
+		if code == http.StatusUnauthorized || code == http.StatusForbidden {
+			code = http.StatusBadGateway
+		}
+	}
+
+	var quotaErr *quota.Error
+
+	if errors.As(err, &quotaErr) {
+		code = http.StatusTooManyRequests
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.RetryAfter.Seconds()))))
+	}
+
+	errorType := "api_error"
+
+	switch code {
+	case http.StatusBadRequest:
+		errorType = "invalid_request_error"
+
+	case http.StatusUnauthorized:
+		errorType = "authentication_error"
+
+	case http.StatusForbidden:
+		errorType = "permission_error"
+
+	case http.StatusNotFound:
+		errorType = "not_found_error"
+
+	case http.StatusTooManyRequests:
+		errorType = "rate_limit_error"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	resp := ErrorResponse{
+		Type: "error",
+
+		Error: Error{
+			Type:    errorType,
+			Message: err.Error(),
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(resp)
+}
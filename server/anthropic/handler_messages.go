@@ -0,0 +1,588 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleMessages(w http.ResponseWriter, r *http.Request) {
+	var req MessageRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
+	completer, err := h.Completer(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if req.TopK != nil {
+		writeError(w, http.StatusBadRequest, errors.New("top_k is not supported"))
+		return
+	}
+
+	messages, err := toMessages(req.System, req.Messages)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	toolChoice, err := toToolChoice(req.ToolChoice)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options := &provider.CompleteOptions{
+		Stop:  req.StopSequences,
+		Tools: toTools(req.Tools),
+
+		ToolChoice: toolChoice,
+
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+
+	if req.MaxTokens > 0 {
+		options.MaxTokens = &req.MaxTokens
+	}
+
+	if req.Stream {
+		s := &streamWriter{
+			w: w,
+
+			id:    "msg_" + uuid.NewString(),
+			model: req.Model,
+
+			index: -1,
+		}
+
+		options.Stream = s.write
+
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			if !s.started {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			s.writeEvent("error", ErrorResponse{
+				Type: "error",
+
+				Error: Error{
+					Type:    "api_error",
+					Message: err.Error(),
+				},
+			})
+
+			return
+		}
+
+		s.finish(completion)
+	} else {
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		id := completion.ID
+
+		if id == "" {
+			id = "msg_" + uuid.NewString()
+		}
+
+		result := MessageResponse{
+			ID:   id,
+			Type: "message",
+
+			Role:  MessageRoleAssistant,
+			Model: req.Model,
+
+			Content: toResponseBlocks(completion.Message),
+
+			StopReason: toStopReason(completion.Reason),
+		}
+
+		if completion.Usage != nil {
+			result.Usage = Usage{
+				InputTokens:  completion.Usage.InputTokens,
+				OutputTokens: completion.Usage.OutputTokens,
+			}
+		}
+
+		writeJson(w, result)
+	}
+}
+
+type streamWriter struct {
+	w http.ResponseWriter
+
+	id    string
+	model string
+
+	started bool
+
+	index int
+	block ContentBlockType
+
+	toolCount int
+}
+
+func (s *streamWriter) write(ctx context.Context, completion provider.Completion) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	if completion.Message.Content != "" {
+		if s.block != ContentBlockTypeText {
+			if err := s.startBlock(ResponseBlock{Type: ContentBlockTypeText, Text: new(string)}); err != nil {
+				return err
+			}
+		}
+
+		if err := s.writeEvent("content_block_delta", ContentBlockDeltaEvent{
+			Type: "content_block_delta",
+
+			Index: s.index,
+
+			Delta: Delta{
+				Type: DeltaTypeText,
+				Text: completion.Message.Content,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range completion.Message.ToolCalls {
+		if err := s.writeToolCall(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *streamWriter) writeToolCall(t provider.ToolCall) error {
+	if t.ID != "" || t.Name != "" || s.block != ContentBlockTypeToolUse {
+		id := t.ID
+
+		if id == "" {
+			id = "toolu_" + uuid.NewString()
+		}
+
+		if err := s.startBlock(ResponseBlock{Type: ContentBlockTypeToolUse, ID: id, Name: t.Name, Input: json.RawMessage("{}")}); err != nil {
+			return err
+		}
+
+		s.toolCount++
+	}
+
+	if t.Arguments == "" {
+		return nil
+	}
+
+	return s.writeEvent("content_block_delta", ContentBlockDeltaEvent{
+		Type: "content_block_delta",
+
+		Index: s.index,
+
+		Delta: Delta{
+			Type:        DeltaTypeInputJSON,
+			PartialJSON: t.Arguments,
+		},
+	})
+}
+
+func (s *streamWriter) start() error {
+	if s.started {
+		return nil
+	}
+
+	s.started = true
+
+	s.w.Header().Set("Content-Type", "text/event-stream")
+
+	return s.writeEvent("message_start", MessageStartEvent{
+		Type: "message_start",
+
+		Message: MessageResponse{
+			ID:   s.id,
+			Type: "message",
+
+			Role:  MessageRoleAssistant,
+			Model: s.model,
+
+			Content: []ResponseBlock{},
+		},
+	})
+}
+
+func (s *streamWriter) startBlock(block ResponseBlock) error {
+	if err := s.stopBlock(); err != nil {
+		return err
+	}
+
+	s.index++
+	s.block = block.Type
+
+	return s.writeEvent("content_block_start", ContentBlockStartEvent{
+		Type: "content_block_start",
+
+		Index:        s.index,
+		ContentBlock: block,
+	})
+}
+
+func (s *streamWriter) stopBlock() error {
+	if s.block == "" {
+		return nil
+	}
+
+	s.block = ""
+
+	return s.writeEvent("content_block_stop", ContentBlockStopEvent{
+		Type: "content_block_stop",
+
+		Index: s.index,
+	})
+}
+
+func (s *streamWriter) finish(completion *provider.Completion) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	// providers not streaming tool calls only return them with the final result
+	if s.toolCount == 0 {
+		for _, t := range completion.Message.ToolCalls {
+			if t.ID == "" {
+				t.ID = "toolu_" + uuid.NewString()
+			}
+
+			if err := s.writeToolCall(t); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.stopBlock(); err != nil {
+		return err
+	}
+
+	reason := toStopReason(completion.Reason)
+
+	if s.toolCount > 0 {
+		reason = &StopReasonToolUse
+	}
+
+	event := MessageDeltaEvent{
+		Type: "message_delta",
+
+		Delta: MessageDelta{
+			StopReason: reason,
+		},
+	}
+
+	if completion.Usage != nil {
+		event.Usage = Usage{
+			InputTokens:  completion.Usage.InputTokens,
+			OutputTokens: completion.Usage.OutputTokens,
+		}
+	}
+
+	if err := s.writeEvent("message_delta", event); err != nil {
+		return err
+	}
+
+	return s.writeEvent("message_stop", MessageStopEvent{
+		Type: "message_stop",
+	})
+}
+
+func (s *streamWriter) writeEvent(event string, v any) error {
+	var data bytes.Buffer
+
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, strings.TrimSpace(data.String())); err != nil {
+		return err
+	}
+
+	s.w.(http.Flusher).Flush()
+
+	return nil
+}
+
+func toMessages(system Content, messages []Message) ([]provider.Message, error) {
+	var result []provider.Message
+
+	if text := toText(system); text != "" {
+		result = append(result, provider.Message{
+			Role:    provider.MessageRoleSystem,
+			Content: text,
+		})
+	}
+
+	for _, m := range messages {
+		switch m.Role {
+		case MessageRoleUser:
+			message := provider.Message{
+				Role: provider.MessageRoleUser,
+			}
+
+			for _, b := range m.Content {
+				switch b.Type {
+				case ContentBlockTypeText:
+					if message.Content != "" {
+						message.Content += "\n\n"
+					}
+
+					message.Content += b.Text
+
+				case ContentBlockTypeImage:
+					file, err := toFile(b.Source)
+
+					if err != nil {
+						return nil, err
+					}
+
+					message.Files = append(message.Files, *file)
+
+				case ContentBlockTypeToolResult:
+					result = append(result, provider.Message{
+						Role:    provider.MessageRoleTool,
+						Content: toText(b.Content),
+
+						Tool: b.ToolUseID,
+					})
+
+				default:
+					return nil, fmt.Errorf("unsupported content block type in user message: %s", b.Type)
+				}
+			}
+
+			if message.Content != "" || len(message.Files) > 0 {
+				result = append(result, message)
+			}
+
+		case MessageRoleAssistant:
+			message := provider.Message{
+				Role: provider.MessageRoleAssistant,
+			}
+
+			for _, b := range m.Content {
+				switch b.Type {
+				case ContentBlockTypeText:
+					message.Content += b.Text
+
+				case ContentBlockTypeToolUse:
+					arguments := string(b.Input)
+
+					if arguments == "" {
+						arguments = "{}"
+					}
+
+					message.ToolCalls = append(message.ToolCalls, provider.ToolCall{
+						ID: b.ID,
+
+						Name:      b.Name,
+						Arguments: arguments,
+					})
+
+				default:
+					return nil, fmt.Errorf("unsupported content block type in assistant message: %s", b.Type)
+				}
+			}
+
+			result = append(result, message)
+
+		default:
+			return nil, fmt.Errorf("invalid message role: %s", m.Role)
+		}
+	}
+
+	return result, nil
+}
+
+func toText(content Content) string {
+	var parts []string
+
+	for _, b := range content {
+		if b.Type == ContentBlockTypeText {
+			parts = append(parts, b.Text)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func toFile(source *ImageSource) (*provider.File, error) {
+	if source == nil {
+		return nil, errors.New("missing image source")
+	}
+
+	var data []byte
+	var mimeType string
+
+	switch source.Type {
+	case "base64":
+		val, err := base64.StdEncoding.DecodeString(source.Data)
+
+		if err != nil {
+			return nil, errors.New("invalid image encoding")
+		}
+
+		data = val
+		mimeType = source.MediaType
+
+	case "url":
+		resp, err := http.Get(source.URL)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer resp.Body.Close()
+
+		val, err := io.ReadAll(resp.Body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data = val
+		mimeType = resp.Header.Get("Content-Type")
+
+	default:
+		return nil, fmt.Errorf("unsupported image source type: %s", source.Type)
+	}
+
+	file := provider.File{
+		Content: bytes.NewReader(data),
+	}
+
+	if ext, _ := mime.ExtensionsByType(mimeType); len(ext) > 0 {
+		file.Name = uuid.New().String() + ext[0]
+	}
+
+	return &file, nil
+}
+
+func toTools(tools []Tool) []provider.Tool {
+	var result []provider.Tool
+
+	for _, t := range tools {
+		result = append(result, provider.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+
+			Parameters: t.InputSchema,
+		})
+	}
+
+	return result
+}
+
+func toToolChoice(c *ToolChoice) (*provider.ToolChoice, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	switch c.Type {
+	case ToolChoiceTypeAuto:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeAuto}, nil
+
+	case ToolChoiceTypeNone:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeNone}, nil
+
+	case ToolChoiceTypeAny:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}, nil
+
+	case ToolChoiceTypeTool:
+		if c.Name != "" {
+			return &provider.ToolChoice{Mode: provider.ToolChoiceModeTool, Name: c.Name}, nil
+		}
+	}
+
+	return nil, errors.New("invalid tool_choice")
+}
+
+func toResponseBlocks(m provider.Message) []ResponseBlock {
+	result := []ResponseBlock{}
+
+	if m.Content != "" {
+		text := m.Content
+
+		result = append(result, ResponseBlock{
+			Type: ContentBlockTypeText,
+			Text: &text,
+		})
+	}
+
+	for _, t := range m.ToolCalls {
+		input := json.RawMessage(t.Arguments)
+
+		if !json.Valid(input) {
+			input = json.RawMessage("{}")
+		}
+
+		id := t.ID
+
+		if id == "" {
+			id = "toolu_" + uuid.NewString()
+		}
+
+		result = append(result, ResponseBlock{
+			Type: ContentBlockTypeToolUse,
+
+			ID:    id,
+			Name:  t.Name,
+			Input: input,
+		})
+	}
+
+	return result
+}
+
+func toStopReason(reason provider.CompletionReason) *StopReason {
+	switch reason {
+	case provider.CompletionReasonLength:
+		return &StopReasonMaxTokens
+
+	case provider.CompletionReasonTool:
+		return &StopReasonToolUse
+
+	default:
+		return &StopReasonEndTurn
+	}
+}
@@ -0,0 +1,318 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/config"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type toolCompleter struct{}
+
+func (toolCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	completion := provider.Completion{
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "checking",
+		},
+	}
+
+	if options.Stream != nil {
+		if err := options.Stream(ctx, completion); err != nil {
+			return nil, err
+		}
+	}
+
+	completion.Reason = provider.CompletionReasonStop
+
+	completion.Message.ToolCalls = []provider.ToolCall{
+		{
+			ID:        "call_1",
+			Name:      "get_weather",
+			Arguments: `{"city":"Zurich"}`,
+		},
+	}
+
+	return &completion, nil
+}
+
+func TestStreamFinalToolCalls(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RegisterCompleter("fake", toolCompleter{})
+
+	h, err := New(cfg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"model":"fake","max_tokens":100,"stream":true,"messages":[{"role":"user","content":"weather?"}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	output := rec.Body.String()
+
+	for _, expected := range []string{
+		`"content_block":{"type":"tool_use","id":"call_1","name":"get_weather","input":{}}`,
+		`"delta":{"type":"input_json_delta","partial_json":"{\"city\":\"Zurich\"}"}`,
+		`"stop_reason":"tool_use"`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("missing %s in\n%s", expected, output)
+		}
+	}
+
+	if strings.Count(output, "event: content_block_start") != 2 || strings.Count(output, "event: content_block_stop") != 2 {
+		t.Errorf("expected a text and a tool_use block in\n%s", output)
+	}
+}
+
+// streamCompleter streams text and then two tool calls, the first one in argument deltas
+type streamCompleter struct{}
+
+func (streamCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	chunks := []provider.Message{
+		{Content: "Let me "},
+		{Content: "check."},
+		{ToolCalls: []provider.ToolCall{{ID: "call_1", Name: "get_weather"}}},
+		{ToolCalls: []provider.ToolCall{{Arguments: `{"city":`}}},
+		{ToolCalls: []provider.ToolCall{{Arguments: `"Zurich"}`}}},
+		{ToolCalls: []provider.ToolCall{{ID: "call_2", Name: "get_time", Arguments: `{}`}}},
+	}
+
+	for _, m := range chunks {
+		m.Role = provider.MessageRoleAssistant
+
+		if err := options.Stream(ctx, provider.Completion{Message: m}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonTool,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "Let me check.",
+
+			ToolCalls: []provider.ToolCall{
+				{ID: "call_1", Name: "get_weather", Arguments: `{"city":"Zurich"}`},
+				{ID: "call_2", Name: "get_time", Arguments: `{}`},
+			},
+		},
+
+		Usage: &provider.Usage{
+			InputTokens:  10,
+			OutputTokens: 20,
+		},
+	}, nil
+}
+
+func TestStreamEvents(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RegisterCompleter("fake", streamCompleter{})
+
+	h, err := New(cfg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"model":"fake","max_tokens":100,"stream":true,"messages":[{"role":"user","content":"weather?"}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", got)
+	}
+
+	var events []string
+
+	for _, block := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n") {
+		event, data, ok := strings.Cut(block, "\n")
+
+		if !ok || !strings.HasPrefix(event, "event: ") || !strings.HasPrefix(data, "data: ") {
+			t.Fatalf("invalid event %q", block)
+		}
+
+		var v struct {
+			Type  string `json:"type"`
+			Index int    `json:"index"`
+
+			ContentBlock struct {
+				Type string `json:"type"`
+				ID   string `json:"id"`
+			} `json:"content_block"`
+
+			Delta struct {
+				Type string `json:"type"`
+
+				Text        string `json:"text"`
+				PartialJSON string `json:"partial_json"`
+
+				StopReason string `json:"stop_reason"`
+			} `json:"delta"`
+		}
+
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &v); err != nil {
+			t.Fatal(err)
+		}
+
+		name := strings.TrimPrefix(event, "event: ")
+
+		if name != v.Type {
+			t.Errorf("event %s carries data of type %s", name, v.Type)
+		}
+
+		switch name {
+		case "content_block_start":
+			events = append(events, fmt.Sprintf("start %d %s %s", v.Index, v.ContentBlock.Type, v.ContentBlock.ID))
+
+		case "content_block_delta":
+			events = append(events, fmt.Sprintf("delta %d %s %s%s", v.Index, v.Delta.Type, v.Delta.Text, v.Delta.PartialJSON))
+
+		case "content_block_stop":
+			events = append(events, fmt.Sprintf("stop %d", v.Index))
+
+		case "message_delta":
+			events = append(events, "message_delta "+v.Delta.StopReason)
+
+		default:
+			events = append(events, name)
+		}
+	}
+
+	expected := []string{
+		"message_start",
+		"start 0 text ",
+		"delta 0 text_delta Let me ",
+		"delta 0 text_delta check.",
+		"stop 0",
+		"start 1 tool_use call_1",
+		`delta 1 input_json_delta {"city":`,
+		`delta 1 input_json_delta "Zurich"}`,
+		"stop 1",
+		"start 2 tool_use call_2",
+		"delta 2 input_json_delta {}",
+		"stop 2",
+		"message_delta tool_use",
+		"message_stop",
+	}
+
+	if strings.Join(events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected events:\n%s\nwant:\n%s", strings.Join(events, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+// optionsCompleter records the options of the last request
+type optionsCompleter struct {
+	options *provider.CompleteOptions
+}
+
+func (c *optionsCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.options = options
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "ok",
+		},
+	}, nil
+}
+
+func TestMessageOptions(t *testing.T) {
+	completer := &optionsCompleter{}
+
+	cfg := &config.Config{}
+	cfg.RegisterCompleter("fake", completer)
+
+	h, err := New(cfg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"model":"fake","max_tokens":100,"temperature":0.2,"top_p":0.9,"stop_sequences":["END"],"tool_choice":{"type":"any"},"tools":[{"name":"weather","input_schema":{"type":"object"}}],"messages":[{"role":"user","content":"Hi"}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	options := completer.options
+
+	if *options.MaxTokens != 100 || *options.Temperature != 0.2 || *options.TopP != 0.9 || !reflect.DeepEqual(options.Stop, []string{"END"}) {
+		t.Errorf("unexpected options: %+v", options)
+	}
+
+	if len(options.Tools) != 1 || options.Tools[0].Name != "weather" || *options.ToolChoice != (provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}) {
+		t.Errorf("unexpected tools: %+v %+v", options.Tools, options.ToolChoice)
+	}
+
+	for _, invalid := range []string{`"top_k":5`, `"tool_choice":{"type":"tool"}`, `"tool_choice":{"type":"sometimes"}`} {
+		body := `{"model":"fake","max_tokens":100,` + invalid + `,"messages":[{"role":"user","content":"Hi"}]}`
+
+		req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", invalid, rec.Code)
+		}
+	}
+}
+
+func TestToToolChoice(t *testing.T) {
+	tests := []struct {
+		choice ToolChoice
+		want   *provider.ToolChoice
+	}{
+		{ToolChoice{Type: ToolChoiceTypeNone}, &provider.ToolChoice{Mode: provider.ToolChoiceModeNone}},
+		{ToolChoice{Type: ToolChoiceTypeAuto}, &provider.ToolChoice{Mode: provider.ToolChoiceModeAuto}},
+		{ToolChoice{Type: ToolChoiceTypeAny}, &provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}},
+		{ToolChoice{Type: ToolChoiceTypeTool, Name: "weather"}, &provider.ToolChoice{Mode: provider.ToolChoiceModeTool, Name: "weather"}},
+		{ToolChoice{Type: ToolChoiceTypeTool}, nil},
+		{ToolChoice{Type: "required"}, nil},
+		{ToolChoice{}, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := toToolChoice(&tt.choice)
+
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%+v: expected error, got %+v", tt.choice, got)
+			}
+
+			continue
+		}
+
+		if err != nil || *got != *tt.want {
+			t.Errorf("%+v: expected %+v, got %+v (%v)", tt.choice, tt.want, got, err)
+		}
+	}
+
+	if got, err := toToolChoice(nil); got != nil || err != nil {
+		t.Errorf("expected no tool choice, got %+v, %v", got, err)
+	}
+}
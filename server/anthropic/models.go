@@ -0,0 +1,236 @@
+package anthropic
+
+import (
+	"encoding/json"
+)
+
+type ErrorResponse struct {
+	Type string `json:"type"` // "error"
+
+	Error Error `json:"error"`
+}
+
+type Error struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// https://docs.anthropic.com/en/api/messages
+type MessageRequest struct {
+	Model string `json:"model"`
+
+	System   Content   `json:"system,omitempty"`
+	Messages []Message `json:"messages"`
+
+	Stream        bool     `json:"stream,omitempty"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+
+	Tools      []Tool      `json:"tools,omitempty"`
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+
+	MaxTokens   int      `json:"max_tokens"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+	TopK        *int     `json:"top_k,omitempty"`
+
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+type Metadata struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+type MessageRole string
+
+var (
+	MessageRoleUser      MessageRole = "user"
+	MessageRoleAssistant MessageRole = "assistant"
+)
+
+type Message struct {
+	Role    MessageRole `json:"role"`
+	Content Content     `json:"content"`
+}
+
+// Content is either a plain string or a list of content blocks
+type Content []ContentBlock
+
+func (c *Content) UnmarshalJSON(data []byte) error {
+	var text string
+
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = Content{
+			{
+				Type: ContentBlockTypeText,
+				Text: text,
+			},
+		}
+
+		return nil
+	}
+
+	var blocks []ContentBlock
+
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return err
+	}
+
+	*c = blocks
+	return nil
+}
+
+type ContentBlockType string
+
+var (
+	ContentBlockTypeText       ContentBlockType = "text"
+	ContentBlockTypeImage      ContentBlockType = "image"
+	ContentBlockTypeToolUse    ContentBlockType = "tool_use"
+	ContentBlockTypeToolResult ContentBlockType = "tool_result"
+)
+
+type ContentBlock struct {
+	Type ContentBlockType `json:"type"`
+
+	// text
+	Text string `json:"text,omitempty"`
+
+	// image
+	Source *ImageSource `json:"source,omitempty"`
+
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// tool_result
+	ToolUseID string  `json:"tool_use_id,omitempty"`
+	Content   Content `json:"content,omitempty"`
+	IsError   bool    `json:"is_error,omitempty"`
+}
+
+type ImageSource struct {
+	Type string `json:"type"` // "base64" | "url"
+
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+
+	URL string `json:"url,omitempty"`
+}
+
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type ToolChoiceType string
+
+var (
+	ToolChoiceTypeAuto ToolChoiceType = "auto"
+	ToolChoiceTypeAny  ToolChoiceType = "any"
+	ToolChoiceTypeTool ToolChoiceType = "tool"
+	ToolChoiceTypeNone ToolChoiceType = "none"
+)
+
+type ToolChoice struct {
+	Type ToolChoiceType `json:"type"`
+	Name string         `json:"name,omitempty"`
+}
+
+type StopReason string
+
+var (
+	StopReasonEndTurn      StopReason = "end_turn"
+	StopReasonMaxTokens    StopReason = "max_tokens"
+	StopReasonStopSequence StopReason = "stop_sequence"
+	StopReasonToolUse      StopReason = "tool_use"
+)
+
+// https://docs.anthropic.com/en/api/messages
+type MessageResponse struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "message"
+
+	Role  MessageRole `json:"role"`
+	Model string      `json:"model"`
+
+	Content []ResponseBlock `json:"content"`
+
+	StopReason   *StopReason `json:"stop_reason"`
+	StopSequence *string     `json:"stop_sequence"`
+
+	Usage Usage `json:"usage"`
+}
+
+type ResponseBlock struct {
+	Type ContentBlockType `json:"type"`
+
+	Text *string `json:"text,omitempty"`
+
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// https://docs.anthropic.com/en/api/messages-streaming
+type MessageStartEvent struct {
+	Type string `json:"type"` // "message_start"
+
+	Message MessageResponse `json:"message"`
+}
+
+type ContentBlockStartEvent struct {
+	Type string `json:"type"` // "content_block_start"
+
+	Index        int           `json:"index"`
+	ContentBlock ResponseBlock `json:"content_block"`
+}
+
+type ContentBlockDeltaEvent struct {
+	Type string `json:"type"` // "content_block_delta"
+
+	Index int   `json:"index"`
+	Delta Delta `json:"delta"`
+}
+
+type DeltaType string
+
+var (
+	DeltaTypeText      DeltaType = "text_delta"
+	DeltaTypeInputJSON DeltaType = "input_json_delta"
+)
+
+type Delta struct {
+	Type DeltaType `json:"type"`
+
+	Text        string `json:"text,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
+}
+
+type ContentBlockStopEvent struct {
+	Type string `json:"type"` // "content_block_stop"
+
+	Index int `json:"index"`
+}
+
+type MessageDeltaEvent struct {
+	Type string `json:"type"` // "message_delta"
+
+	Delta MessageDelta `json:"delta"`
+	Usage Usage        `json:"usage"`
+}
+
+type MessageDelta struct {
+	StopReason   *StopReason `json:"stop_reason"`
+	StopSequence *string     `json:"stop_sequence"`
+}
+
+type MessageStopEvent struct {
+	Type string `json:"type"` // "message_stop"
+}
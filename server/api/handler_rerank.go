@@ -3,9 +3,11 @@ package api
 import (
 	"cmp"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"slices"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/provider"
 )
 
@@ -17,6 +19,11 @@ func (h *Handler) handleRerank(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
 	p, err := h.Reranker(req.Model)
 
 	if err != nil {
@@ -2,7 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
 )
 
 func (h *Handler) handleSummarize(w http.ResponseWriter, r *http.Request) {
@@ -13,6 +16,11 @@ func (h *Handler) handleSummarize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
 	p, err := h.Summarizer(req.Model)
 
 	if err != nil {
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/provider"
 )
 
@@ -17,6 +19,11 @@ func (h *Handler) handleTranscribe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizer.AllowModel(r.Context(), model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+model))
+		return
+	}
+
 	p, err := h.Transcriber(model)
 
 	if err != nil {
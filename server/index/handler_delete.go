@@ -3,9 +3,16 @@ package index
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
 )
 
 func (s *Handler) handleDeletion(w http.ResponseWriter, r *http.Request) {
+	if !authorizer.AllowIndex(r.Context(), r.PathValue("index")) {
+		http.Error(w, "index not allowed", http.StatusForbidden)
+		return
+	}
+
 	i, err := s.Index(r.PathValue("index"))
 
 	if err != nil {
@@ -2,9 +2,16 @@ package index
 
 import (
 	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
 )
 
 func (s *Handler) handleList(w http.ResponseWriter, r *http.Request) {
+	if !authorizer.AllowIndex(r.Context(), r.PathValue("index")) {
+		http.Error(w, "index not allowed", http.StatusForbidden)
+		return
+	}
+
 	i, err := s.Index(r.PathValue("index"))
 
 	if err != nil {
@@ -4,11 +4,17 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/index"
 	"github.com/adrianliechti/llama/pkg/to"
 )
 
 func (s *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	if !authorizer.AllowIndex(r.Context(), r.PathValue("index")) {
+		http.Error(w, "index not allowed", http.StatusForbidden)
+		return
+	}
+
 	i, err := s.Index(r.PathValue("index"))
 
 	if err != nil {
@@ -30,6 +36,19 @@ func (s *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
 
 	options := &index.QueryOptions{
 		Limit: query.Limit,
+
+		Hybrid: query.Hybrid,
+	}
+
+	if query.Filter != nil {
+		filter := toFilter(*query.Filter)
+
+		if err := filter.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		options.Filter = &filter
 	}
 
 	result, err := i.Query(r.Context(), query.Text, options)
@@ -56,3 +75,23 @@ func (s *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
 
 	writeJson(w, results)
 }
+
+func toFilter(f Filter) index.Filter {
+	result := index.Filter{
+		Field:    f.Field,
+		Operator: index.Operator(f.Operator),
+
+		Value:  f.Value,
+		Values: f.Values,
+	}
+
+	for _, c := range f.And {
+		result.And = append(result.And, toFilter(c))
+	}
+
+	for _, c := range f.Or {
+		result.Or = append(result.Or, toFilter(c))
+	}
+
+	return result
+}
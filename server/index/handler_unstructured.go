@@ -8,10 +8,16 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/index"
 )
 
 func (s *Handler) handleUnstructured(w http.ResponseWriter, r *http.Request) {
+	if !authorizer.AllowIndex(r.Context(), r.PathValue("index")) {
+		http.Error(w, "index not allowed", http.StatusForbidden)
+		return
+	}
+
 	i, err := s.Index(r.PathValue("index"))
 
 	if err != nil {
@@ -17,4 +17,21 @@ type Query struct {
 	Text string `json:"text,omitempty"`
 
 	Limit *int `json:"limit,omitempty"`
+
+	Hybrid *float32 `json:"hybrid,omitempty"`
+
+	Filter *Filter `json:"filter,omitempty"`
+}
+
+// Filter is either a combination ({"and": [...]} or {"or": [...]}) or a comparison
+// such as {"field": "date", "op": "gte", "value": "2024-01-01"} or {"field": "tag", "op": "in", "values": ["a", "b"]}
+type Filter struct {
+	And []Filter `json:"and,omitempty"`
+	Or  []Filter `json:"or,omitempty"`
+
+	Field    string `json:"field,omitempty"`
+	Operator string `json:"op,omitempty"`
+
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values,omitempty"`
 }
@@ -0,0 +1,99 @@
+package ollama
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/adrianliechti/llama/config"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	*config.Config
+	http.Handler
+}
+
+func New(cfg *config.Config) (*Handler, error) {
+	mux := chi.NewMux()
+
+	h := &Handler{
+		Config:  cfg,
+		Handler: mux,
+	}
+
+	h.Attach(mux)
+	return h, nil
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Get("/version", h.handleVersion)
+
+	r.Get("/tags", h.handleTags)
+	r.Get("/ps", h.handlePs)
+	r.Post("/show", h.handleShow)
+
+	r.Post("/chat", h.handleChat)
+	r.Post("/generate", h.handleGenerate)
+
+	r.Post("/embed", h.handleEmbed)
+	r.Post("/embeddings", h.handleEmbeddings)
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	var providerErr *provider.Error
+
+	if errors.As(err, &providerErr) && providerErr.StatusCode >= 400 {
+		code = providerErr.StatusCode
+
+		// rejected upstream credentials are no fault of the caller
+		if code == http.StatusUnauthorized || code == http.StatusForbidden {
+			code = http.StatusBadGateway
+		}
+	}
+
+	var quotaErr *quota.Error
+
+	if errors.As(err, &quotaErr) {
+		code = http.StatusTooManyRequests
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.RetryAfter.Seconds()))))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(ErrorResponse{
+		Error: err.Error(),
+	})
+}
+
+// streamed responses are newline delimited json
+func writeChunk(w http.ResponseWriter, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	w.(http.Flusher).Flush()
+
+	return nil
+}
@@ -0,0 +1,304 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req ChatRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
+	completer, err := h.Completer(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	messages, err := toMessages(req.Messages)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options := toCompleteOptions(req.Options, req.Format)
+	options.Tools = toTools(req.Tools)
+
+	start := time.Now()
+
+	if req.Stream == nil || *req.Stream {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+
+		options.Stream = func(ctx context.Context, completion provider.Completion) error {
+			if completion.Message.Content == "" {
+				return nil
+			}
+
+			return writeChunk(w, ChatResponse{
+				Model:     req.Model,
+				CreatedAt: time.Now().UTC(),
+
+				Message: Message{
+					Role:    MessageRoleAssistant,
+					Content: completion.Message.Content,
+				},
+			})
+		}
+
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// tool calls are streamed as deltas by most providers and therefore sent once complete
+		if len(completion.Message.ToolCalls) > 0 {
+			writeChunk(w, ChatResponse{
+				Model:     req.Model,
+				CreatedAt: time.Now().UTC(),
+
+				Message: Message{
+					Role: MessageRoleAssistant,
+
+					ToolCalls: toToolCalls(completion.Message.ToolCalls),
+				},
+			})
+		}
+
+		writeChunk(w, ChatResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Message: Message{
+				Role: MessageRoleAssistant,
+			},
+
+			Metrics: toMetrics(completion, start),
+		})
+	} else {
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeJson(w, ChatResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Message: Message{
+				Role:    MessageRoleAssistant,
+				Content: completion.Message.Content,
+
+				ToolCalls: toToolCalls(completion.Message.ToolCalls),
+			},
+
+			Metrics: toMetrics(completion, start),
+		})
+	}
+}
+
+func toMessages(messages []Message) ([]provider.Message, error) {
+	var result []provider.Message
+
+	// ollama has no tool call ids, so results are matched to the calls in order
+	var calls []string
+
+	for _, m := range messages {
+		message := provider.Message{
+			Content: m.Content,
+		}
+
+		switch m.Role {
+		case MessageRoleSystem:
+			message.Role = provider.MessageRoleSystem
+
+		case MessageRoleUser:
+			message.Role = provider.MessageRoleUser
+
+		case MessageRoleAssistant:
+			message.Role = provider.MessageRoleAssistant
+
+			calls = nil
+
+			for _, c := range m.ToolCalls {
+				id := "call_" + uuid.NewString()
+
+				arguments := string(c.Function.Arguments)
+
+				if arguments == "" {
+					arguments = "{}"
+				}
+
+				message.ToolCalls = append(message.ToolCalls, provider.ToolCall{
+					ID: id,
+
+					Name:      c.Function.Name,
+					Arguments: arguments,
+				})
+
+				calls = append(calls, id)
+			}
+
+		case MessageRoleTool:
+			message.Role = provider.MessageRoleTool
+
+			if len(calls) > 0 {
+				message.Tool = calls[0]
+				calls = calls[1:]
+			}
+
+		default:
+			return nil, fmt.Errorf("invalid message role: %s", m.Role)
+		}
+
+		files, err := toFiles(m.Images)
+
+		if err != nil {
+			return nil, err
+		}
+
+		message.Files = files
+
+		result = append(result, message)
+	}
+
+	return result, nil
+}
+
+func toFiles(images []string) ([]provider.File, error) {
+	var result []provider.File
+
+	for _, image := range images {
+		data, err := base64.StdEncoding.DecodeString(image)
+
+		if err != nil {
+			return nil, errors.New("invalid image encoding")
+		}
+
+		result = append(result, provider.File{
+			Content: bytes.NewReader(data),
+		})
+	}
+
+	return result, nil
+}
+
+func toTools(tools []Tool) []provider.Tool {
+	var result []provider.Tool
+
+	for _, t := range tools {
+		result = append(result, provider.Tool{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+
+			Parameters: t.Function.Parameters,
+		})
+	}
+
+	return result
+}
+
+func toToolCalls(calls []provider.ToolCall) []ToolCall {
+	var result []ToolCall
+
+	for _, c := range calls {
+		arguments := json.RawMessage(c.Arguments)
+
+		if !json.Valid(arguments) {
+			arguments = json.RawMessage("{}")
+		}
+
+		result = append(result, ToolCall{
+			Function: ToolCallFunction{
+				Name:      c.Name,
+				Arguments: arguments,
+			},
+		})
+	}
+
+	return result
+}
+
+// runner specific options (num_ctx, top_k, mirostat, ...) have no equivalent and are ignored like ollama does for unknown parameters
+func toCompleteOptions(o *Options, format Format) *provider.CompleteOptions {
+	options := &provider.CompleteOptions{}
+
+	if o != nil {
+		options.Stop = o.Stop
+
+		options.MaxTokens = o.NumPredict
+		options.Temperature = o.Temperature
+		options.TopP = o.TopP
+
+		options.Seed = o.Seed
+
+		options.PresencePenalty = o.PresencePenalty
+		options.FrequencyPenalty = o.FrequencyPenalty
+	}
+
+	if format.JSON {
+		options.Format = provider.CompletionFormatJSON
+	}
+
+	if format.Schema != nil {
+		options.Format = provider.CompletionFormatJSONSchema
+
+		options.Schema = &provider.Schema{
+			Name:   "response",
+			Schema: format.Schema,
+		}
+	}
+
+	return options
+}
+
+func toMetrics(completion *provider.Completion, start time.Time) Metrics {
+	metrics := Metrics{
+		Done:       true,
+		DoneReason: toDoneReason(completion.Reason),
+
+		TotalDuration: time.Since(start),
+	}
+
+	if completion.Usage != nil {
+		metrics.PromptEvalCount = completion.Usage.InputTokens
+		metrics.EvalCount = completion.Usage.OutputTokens
+	}
+
+	return metrics
+}
+
+func toDoneReason(reason provider.CompletionReason) string {
+	switch reason {
+	case provider.CompletionReasonLength:
+		return "length"
+
+	default:
+		return "stop"
+	}
+}
@@ -0,0 +1,91 @@
+package ollama
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+func (h *Handler) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	var req EmbedRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
+	embedder, err := h.Embedder(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if len(req.Input) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no input provided"))
+		return
+	}
+
+	options := &provider.EmbedOptions{
+		Dimensions: req.Dimensions,
+	}
+
+	embedding, err := embedder.Embed(r.Context(), req.Input, options)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result := EmbedResponse{
+		Model: req.Model,
+
+		Embeddings: embedding.Embeddings,
+	}
+
+	if embedding.Usage != nil {
+		result.PromptEvalCount = embedding.Usage.InputTokens
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
+	var req EmbeddingsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
+	embedder, err := h.Embedder(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	embedding, err := embedder.Embed(r.Context(), []string{req.Prompt}, nil)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJson(w, EmbeddingsResponse{
+		Embedding: embedding.Embeddings[0],
+	})
+}
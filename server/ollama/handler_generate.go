@@ -0,0 +1,132 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+func (h *Handler) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req GenerateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
+	completer, err := h.Completer(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	stream := req.Stream == nil || *req.Stream
+
+	// an empty prompt only loads the model
+	if req.Prompt == "" && req.Suffix == "" {
+		result := GenerateResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Metrics: Metrics{
+				Done:       true,
+				DoneReason: "load",
+			},
+		}
+
+		if stream {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			writeChunk(w, result)
+		} else {
+			writeJson(w, result)
+		}
+
+		return
+	}
+
+	files, err := toFiles(req.Images)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var messages []provider.Message
+
+	if req.System != "" {
+		messages = append(messages, provider.Message{
+			Role:    provider.MessageRoleSystem,
+			Content: req.System,
+		})
+	}
+
+	messages = append(messages, provider.Message{
+		Role:    provider.MessageRoleUser,
+		Content: req.Prompt,
+
+		Files: files,
+	})
+
+	options := toCompleteOptions(req.Options, req.Format)
+	options.Suffix = req.Suffix
+
+	start := time.Now()
+
+	if stream {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+
+		options.Stream = func(ctx context.Context, completion provider.Completion) error {
+			if completion.Message.Content == "" {
+				return nil
+			}
+
+			return writeChunk(w, GenerateResponse{
+				Model:     req.Model,
+				CreatedAt: time.Now().UTC(),
+
+				Response: completion.Message.Content,
+			})
+		}
+
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeChunk(w, GenerateResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Metrics: toMetrics(completion, start),
+		})
+	} else {
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeJson(w, GenerateResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Response: completion.Message.Content,
+
+			Metrics: toMetrics(completion, start),
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package ollama
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+)
+
+// version of the ollama api this handler is compatible with
+const version = "0.3.14"
+
+func (h *Handler) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, VersionResponse{
+		Version: version,
+	})
+}
+
+func (h *Handler) handleTags(w http.ResponseWriter, r *http.Request) {
+	result := ModelList{
+		Models: make([]Model, 0),
+	}
+
+	for _, m := range h.Models() {
+		if !authorizer.AllowModel(r.Context(), m.ID) || !h.isServed(m.ID) {
+			continue
+		}
+
+		result.Models = append(result.Models, toModel(m.ID))
+	}
+
+	writeJson(w, result)
+}
+
+// ollama lists loaded models; all served models are always available
+func (h *Handler) handlePs(w http.ResponseWriter, r *http.Request) {
+	h.handleTags(w, r)
+}
+
+func (h *Handler) handleShow(w http.ResponseWriter, r *http.Request) {
+	var req ShowRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	id := req.Model
+
+	if id == "" {
+		id = req.Name
+	}
+
+	if !authorizer.AllowModel(r.Context(), id) || !h.isServed(id) {
+		writeError(w, http.StatusNotFound, errors.New("model not found: "+id))
+		return
+	}
+
+	writeJson(w, ShowResponse{
+		Details:   toModel(id).Details,
+		ModelInfo: map[string]any{},
+	})
+}
+
+func (h *Handler) isServed(id string) bool {
+	if _, err := h.Completer(id); err == nil {
+		return true
+	}
+
+	if _, err := h.Embedder(id); err == nil {
+		return true
+	}
+
+	return false
+}
+
+func toModel(id string) Model {
+	return Model{
+		Name:  id,
+		Model: id,
+
+		ModifiedAt: time.Now().UTC(),
+
+		Details: ModelDetails{
+			Families: []string{},
+		},
+	}
+}
@@ -0,0 +1,426 @@
+package ollama
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/config"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+// fakeCompleter streams its chunks and records the messages and options of the last request
+type fakeCompleter struct {
+	chunks []string
+
+	reason    provider.CompletionReason
+	toolCalls []provider.ToolCall
+
+	messages []provider.Message
+	options  *provider.CompleteOptions
+}
+
+func (c *fakeCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.messages = messages
+	c.options = options
+
+	if options.Stream != nil {
+		for _, chunk := range c.chunks {
+			if err := options.Stream(ctx, provider.Completion{
+				Message: provider.Message{
+					Role:    provider.MessageRoleAssistant,
+					Content: chunk,
+				},
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &provider.Completion{
+		Reason: c.reason,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: strings.Join(c.chunks, ""),
+
+			ToolCalls: c.toolCalls,
+		},
+
+		Usage: &provider.Usage{
+			InputTokens:  7,
+			OutputTokens: 3,
+		},
+	}, nil
+}
+
+// fakeEmbedder embeds texts as their length
+type fakeEmbedder struct {
+	options *provider.EmbedOptions
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	e.options = options
+
+	result := &provider.Embedding{
+		Usage: &provider.Usage{
+			InputTokens: len(texts),
+		},
+	}
+
+	for _, text := range texts {
+		result.Embeddings = append(result.Embeddings, []float32{float32(len(text))})
+	}
+
+	return result, nil
+}
+
+func newTestHandler(t *testing.T, completer provider.Completer, embedder provider.Embedder) *Handler {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(path, []byte("providers: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Parse(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.RegisterCompleter("chat", completer)
+	cfg.RegisterEmbedder("embed", embedder)
+
+	h, err := New(cfg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func doRequest(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// readLines decodes every line of a newline delimited json stream
+func readLines[T any](t *testing.T, rec *httptest.ResponseRecorder) []T {
+	t.Helper()
+
+	if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("expected application/x-ndjson, got %q", got)
+	}
+
+	var result []T
+
+	scanner := bufio.NewScanner(rec.Body)
+
+	for scanner.Scan() {
+		var v T
+
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+
+		result = append(result, v)
+	}
+
+	return result
+}
+
+func TestChatStream(t *testing.T) {
+	completer := &fakeCompleter{
+		chunks: []string{"Hel", "", "lo"},
+		reason: provider.CompletionReasonLength,
+	}
+
+	h := newTestHandler(t, completer, &fakeEmbedder{})
+
+	rec := doRequest(h, http.MethodPost, "/chat", `{"model":"chat","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"Hi"}],"options":{"temperature":0.5,"num_predict":10,"stop":["\n"]}}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	lines := readLines[ChatResponse](t, rec)
+
+	// empty chunks are skipped and the final line only carries the metrics
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %s", len(lines), rec.Body.String())
+	}
+
+	if lines[0].Message.Content != "Hel" || lines[1].Message.Content != "lo" || lines[0].Done || lines[1].Done {
+		t.Errorf("unexpected chunks: %+v", lines[:2])
+	}
+
+	last := lines[2]
+
+	if !last.Done || last.DoneReason != "length" || last.Message.Content != "" || last.PromptEvalCount != 7 || last.EvalCount != 3 {
+		t.Errorf("unexpected final line: %+v", last)
+	}
+
+	if len(completer.messages) != 2 || completer.messages[0].Role != provider.MessageRoleSystem || completer.messages[1].Content != "Hi" {
+		t.Errorf("unexpected messages: %+v", completer.messages)
+	}
+
+	options := completer.options
+
+	if *options.Temperature != 0.5 || *options.MaxTokens != 10 || len(options.Stop) != 1 {
+		t.Errorf("unexpected options: %+v", options)
+	}
+}
+
+func TestChatStreamToolCalls(t *testing.T) {
+	completer := &fakeCompleter{
+		toolCalls: []provider.ToolCall{
+			{
+				ID: "call_1",
+
+				Name:      "weather",
+				Arguments: `{"city":"Zurich"}`,
+			},
+		},
+	}
+
+	h := newTestHandler(t, completer, &fakeEmbedder{})
+
+	rec := doRequest(h, http.MethodPost, "/chat", `{"model":"chat","messages":[{"role":"user","content":"Weather?"}],"tools":[{"type":"function","function":{"name":"weather","parameters":{"type":"object"}}}]}`)
+
+	lines := readLines[ChatResponse](t, rec)
+
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), rec.Body.String())
+	}
+
+	calls := lines[0].Message.ToolCalls
+
+	if len(calls) != 1 || calls[0].Function.Name != "weather" || string(calls[0].Function.Arguments) != `{"city":"Zurich"}` || lines[0].Done {
+		t.Errorf("unexpected tool call line: %+v", lines[0])
+	}
+
+	if !lines[1].Done || lines[1].DoneReason != "stop" {
+		t.Errorf("unexpected final line: %+v", lines[1])
+	}
+
+	if len(completer.options.Tools) != 1 || completer.options.Tools[0].Name != "weather" {
+		t.Errorf("unexpected tools: %+v", completer.options.Tools)
+	}
+}
+
+func TestChat(t *testing.T) {
+	completer := &fakeCompleter{
+		chunks: []string{"Hello"},
+	}
+
+	h := newTestHandler(t, completer, &fakeEmbedder{})
+
+	rec := doRequest(h, http.MethodPost, "/chat", `{"model":"chat","messages":[{"role":"user","content":"Hi"}],"stream":false,"format":"json"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result ChatResponse
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Message.Role != MessageRoleAssistant || result.Message.Content != "Hello" || !result.Done || result.DoneReason != "stop" {
+		t.Errorf("unexpected response: %+v", result)
+	}
+
+	if completer.options.Stream != nil || completer.options.Format != provider.CompletionFormatJSON {
+		t.Errorf("unexpected options: %+v", completer.options)
+	}
+}
+
+func TestChatValidation(t *testing.T) {
+	h := newTestHandler(t, &fakeCompleter{}, &fakeEmbedder{})
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"model":"unknown","messages":[]}`, http.StatusNotFound},
+		{`{"model":"chat","messages":[{"role":"robot","content":"Hi"}]}`, http.StatusBadRequest},
+		{`{"model":"chat","messages":[{"role":"user","content":"Hi","images":["!"]}]}`, http.StatusBadRequest},
+		{`{"model":"chat",`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if rec := doRequest(h, http.MethodPost, "/chat", tt.body); rec.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.body, tt.code, rec.Code)
+		}
+	}
+}
+
+func TestGenerateStream(t *testing.T) {
+	completer := &fakeCompleter{
+		chunks: []string{"return ", "a + b"},
+	}
+
+	h := newTestHandler(t, completer, &fakeEmbedder{})
+
+	rec := doRequest(h, http.MethodPost, "/generate", `{"model":"chat","prompt":"func add(a, b int) int {","suffix":"}","system":"Complete code."}`)
+
+	lines := readLines[GenerateResponse](t, rec)
+
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %s", len(lines), rec.Body.String())
+	}
+
+	if lines[0].Response != "return " || lines[1].Response != "a + b" || lines[1].Done {
+		t.Errorf("unexpected chunks: %+v", lines[:2])
+	}
+
+	if !lines[2].Done || lines[2].DoneReason != "stop" || lines[2].Response != "" {
+		t.Errorf("unexpected final line: %+v", lines[2])
+	}
+
+	if len(completer.messages) != 2 || completer.messages[1].Content != "func add(a, b int) int {" || completer.options.Suffix != "}" {
+		t.Errorf("unexpected request: %+v %+v", completer.messages, completer.options)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	h := newTestHandler(t, &fakeCompleter{chunks: []string{"Hello"}}, &fakeEmbedder{})
+
+	rec := doRequest(h, http.MethodPost, "/generate", `{"model":"chat","prompt":"Hi","stream":false}`)
+
+	var result GenerateResponse
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Response != "Hello" || !result.Done || result.EvalCount != 3 {
+		t.Errorf("unexpected response: %+v", result)
+	}
+}
+
+func TestGenerateLoad(t *testing.T) {
+	completer := &fakeCompleter{}
+	h := newTestHandler(t, completer, &fakeEmbedder{})
+
+	rec := doRequest(h, http.MethodPost, "/generate", `{"model":"chat"}`)
+
+	lines := readLines[GenerateResponse](t, rec)
+
+	if len(lines) != 1 || !lines[0].Done || lines[0].DoneReason != "load" {
+		t.Errorf("unexpected response: %s", rec.Body.String())
+	}
+
+	if completer.options != nil {
+		t.Error("expected no completion for an empty prompt")
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	embedder := &fakeEmbedder{}
+	h := newTestHandler(t, &fakeCompleter{}, embedder)
+
+	tests := []struct {
+		body string
+
+		embeddings [][]float32
+		dimensions int
+	}{
+		{`{"model":"embed","input":"hello"}`, [][]float32{{5}}, 0},
+		{`{"model":"embed","input":["a","bb"]}`, [][]float32{{1}, {2}}, 0},
+		{`{"model":"embed","input":"a","dimensions":256}`, [][]float32{{1}}, 256},
+	}
+
+	for _, tt := range tests {
+		rec := doRequest(h, http.MethodPost, "/embed", tt.body)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", tt.body, rec.Code, rec.Body.String())
+		}
+
+		var result EmbedResponse
+
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Model != "embed" || result.PromptEvalCount != len(tt.embeddings) || !reflect.DeepEqual(result.Embeddings, tt.embeddings) {
+			t.Errorf("%s: unexpected response: %+v", tt.body, result)
+		}
+
+		if dimensions := embedder.options.Dimensions; tt.dimensions > 0 && (dimensions == nil || *dimensions != tt.dimensions) || tt.dimensions == 0 && dimensions != nil {
+			t.Errorf("%s: unexpected dimensions: %v", tt.body, dimensions)
+		}
+	}
+
+	if rec := doRequest(h, http.MethodPost, "/embed", `{"model":"embed","input":[]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without input, got %d", rec.Code)
+	}
+
+	if rec := doRequest(h, http.MethodPost, "/embed", `{"model":"chat","input":"hello"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a model without embedder, got %d", rec.Code)
+	}
+}
+
+func TestEmbeddings(t *testing.T) {
+	h := newTestHandler(t, &fakeCompleter{}, &fakeEmbedder{})
+
+	rec := doRequest(h, http.MethodPost, "/embeddings", `{"model":"embed","prompt":"hello"}`)
+
+	var result EmbeddingsResponse
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Embedding) != 1 || result.Embedding[0] != 5 {
+		t.Errorf("unexpected response: %+v", result)
+	}
+}
+
+func TestTags(t *testing.T) {
+	h := newTestHandler(t, &fakeCompleter{}, &fakeEmbedder{})
+
+	h.RegisterModel("other")
+
+	rec := doRequest(h, http.MethodGet, "/tags", "")
+
+	var result ModelList
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	// models without completer or embedder are not served
+	var names []string
+
+	for _, m := range result.Models {
+		names = append(names, m.Name)
+	}
+
+	if strings.Join(names, ",") != "chat,embed" {
+		t.Errorf("unexpected models: %v", names)
+	}
+
+	if rec := doRequest(h, http.MethodPost, "/show", `{"model":"other"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unserved model, got %d", rec.Code)
+	}
+
+	if rec := doRequest(h, http.MethodPost, "/show", `{"name":"chat"}`); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a served model, got %d", rec.Code)
+	}
+}
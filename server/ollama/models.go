@@ -0,0 +1,237 @@
+package ollama
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#version
+type VersionResponse struct {
+	Version string `json:"version"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#list-local-models
+type ModelList struct {
+	Models []Model `json:"models"`
+}
+
+type Model struct {
+	Name  string `json:"name"`
+	Model string `json:"model"`
+
+	ModifiedAt time.Time `json:"modified_at"`
+
+	Size   int64  `json:"size"`
+	Digest string `json:"digest"`
+
+	Details ModelDetails `json:"details"`
+}
+
+type ModelDetails struct {
+	Format string `json:"format"`
+	Family string `json:"family"`
+
+	Families []string `json:"families"`
+
+	ParameterSize     string `json:"parameter_size"`
+	QuantizationLevel string `json:"quantization_level"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#show-model-information
+type ShowRequest struct {
+	Model string `json:"model"`
+	Name  string `json:"name"`
+}
+
+type ShowResponse struct {
+	Modelfile  string `json:"modelfile"`
+	Parameters string `json:"parameters"`
+	Template   string `json:"template"`
+
+	Details   ModelDetails   `json:"details"`
+	ModelInfo map[string]any `json:"model_info"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-a-chat-completion
+type ChatRequest struct {
+	Model string `json:"model"`
+
+	Messages []Message `json:"messages"`
+	Tools    []Tool    `json:"tools,omitempty"`
+
+	Format  Format   `json:"format,omitempty"`
+	Options *Options `json:"options,omitempty"`
+
+	Stream *bool `json:"stream,omitempty"`
+}
+
+type ChatResponse struct {
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Message Message `json:"message"`
+
+	Metrics
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-a-completion
+type GenerateRequest struct {
+	Model string `json:"model"`
+
+	Prompt string `json:"prompt"`
+	Suffix string `json:"suffix,omitempty"`
+	System string `json:"system,omitempty"`
+
+	Images []string `json:"images,omitempty"`
+
+	Format  Format   `json:"format,omitempty"`
+	Options *Options `json:"options,omitempty"`
+
+	Stream *bool `json:"stream,omitempty"`
+}
+
+type GenerateResponse struct {
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Response string `json:"response"`
+
+	Metrics
+}
+
+type Metrics struct {
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason,omitempty"`
+
+	TotalDuration time.Duration `json:"total_duration,omitempty"`
+
+	PromptEvalCount int `json:"prompt_eval_count,omitempty"`
+	EvalCount       int `json:"eval_count,omitempty"`
+}
+
+type MessageRole string
+
+var (
+	MessageRoleSystem    MessageRole = "system"
+	MessageRoleUser      MessageRole = "user"
+	MessageRoleAssistant MessageRole = "assistant"
+	MessageRoleTool      MessageRole = "tool"
+)
+
+type Message struct {
+	Role    MessageRole `json:"role"`
+	Content string      `json:"content"`
+
+	Images []string `json:"images,omitempty"`
+
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+type Tool struct {
+	Type string `json:"type"` // "function"
+
+	Function ToolFunction `json:"function"`
+}
+
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Parameters map[string]any `json:"parameters"`
+}
+
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// Format is either "json" or a json schema object
+type Format struct {
+	JSON   bool
+	Schema map[string]any
+}
+
+func (f *Format) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s != "" && s != "json" {
+			return errors.New("invalid format: " + s)
+		}
+
+		f.JSON = s == "json"
+		return nil
+	}
+
+	return json.Unmarshal(data, &f.Schema)
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/modelfile.md#valid-parameters-and-values
+type Options struct {
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+
+	Seed *int `json:"seed,omitempty"`
+
+	NumPredict *int     `json:"num_predict,omitempty"`
+	Stop       []string `json:"stop,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-embeddings
+type EmbedRequest struct {
+	Model string `json:"model"`
+	Input Input  `json:"input"`
+
+	Dimensions *int `json:"dimensions,omitempty"`
+}
+
+// Input is either a single string or a list of strings
+type Input []string
+
+func (i *Input) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		*i = []string{s}
+		return nil
+	}
+
+	var list []string
+
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*i = list
+	return nil
+}
+
+type EmbedResponse struct {
+	Model string `json:"model"`
+
+	Embeddings [][]float32 `json:"embeddings"`
+
+	PromptEvalCount int `json:"prompt_eval_count,omitempty"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-embedding
+type EmbeddingsRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type EmbeddingsResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
@@ -2,15 +2,31 @@ package openai
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
 
 	"github.com/adrianliechti/llama/config"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+
 	"github.com/go-chi/chi/v5"
 )
 
 type Handler struct {
 	*config.Config
 	http.Handler
+
+	runs    sync.Map
+	batches sync.Map
+
+	// batchMu serializes batch status changes between cancel requests and running batches
+	batchMu sync.Mutex
+
+	// threadMu serializes thread changes between requests and running runs
+	threadMu sync.Mutex
 }
 
 func New(cfg *config.Config) (*Handler, error) {
@@ -31,12 +47,40 @@ func (h *Handler) Attach(r chi.Router) {
 
 	r.Post("/embeddings", h.handleEmbeddings)
 
+	r.Post("/completions", h.handleCompletion)
 	r.Post("/chat/completions", h.handleChatCompletion)
 
 	r.Post("/audio/speech", h.handleAudioSpeech)
+	r.Get("/audio/voices", h.handleAudioVoices)
 	r.Post("/audio/transcriptions", h.handleAudioTranscription)
 
 	r.Post("/images/generations", h.handleImageGeneration)
+	r.Post("/images/edits", h.handleImageEdit)
+	r.Post("/images/variations", h.handleImageVariation)
+
+	r.Post("/threads", h.handleThreadCreate)
+	r.Get("/threads/{thread}", h.handleThread)
+	r.Delete("/threads/{thread}", h.handleThreadDelete)
+
+	r.Get("/threads/{thread}/messages", h.handleThreadMessages)
+	r.Post("/threads/{thread}/messages", h.handleThreadMessageCreate)
+
+	r.Get("/threads/{thread}/runs", h.handleThreadRuns)
+	r.Post("/threads/{thread}/runs", h.handleThreadRunCreate)
+	r.Get("/threads/{thread}/runs/{run}", h.handleThreadRun)
+	r.Post("/threads/{thread}/runs/{run}/cancel", h.handleThreadRunCancel)
+	r.Post("/threads/{thread}/runs/{run}/submit_tool_outputs", h.handleThreadRunSubmitToolOutputs)
+
+	r.Post("/files", h.handleFileCreate)
+	r.Get("/files", h.handleFiles)
+	r.Get("/files/{file}", h.handleFile)
+	r.Get("/files/{file}/content", h.handleFileContent)
+	r.Delete("/files/{file}", h.handleFileDelete)
+
+	r.Post("/batches", h.handleBatchCreate)
+	r.Get("/batches", h.handleBatches)
+	r.Get("/batches/{batch}", h.handleBatch)
+	r.Post("/batches/{batch}/cancel", h.handleBatchCancel)
 }
 
 func writeJson(w http.ResponseWriter, v any) {
@@ -49,18 +93,46 @@ func writeJson(w http.ResponseWriter, v any) {
 }
 
 func writeError(w http.ResponseWriter, code int, err error) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
+	var providerErr *provider.Error
+
+	if errors.As(err, &providerErr) && providerErr.StatusCode >= 400 {
+		code = providerErr.StatusCode
+
+		// rejected upstream credentials are no fault of the caller
+		if code == http.StatusUnauthorized || code == http.StatusForbidden {
+			code = http.StatusBadGateway
+		}
+	}
 
 	errorType := "invalid_request_error"
+	errorCode := ""
 
 	if code >= 500 {
 		errorType = "internal_server_error"
 	}
 
+	var quotaErr *quota.Error
+
+	if errors.As(err, &quotaErr) {
+		code = http.StatusTooManyRequests
+
+		errorType = string(quotaErr.Type)
+		errorCode = "rate_limit_exceeded"
+
+		if quotaErr.Type == quota.ErrorTypeQuota {
+			errorCode = "insufficient_quota"
+		}
+
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.RetryAfter.Seconds()))))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
 	resp := ErrorResponse{
 		Error: Error{
 			Type:    errorType,
+			Code:    errorCode,
 			Message: err.Error(),
 		},
 	}
@@ -2,9 +2,10 @@ package openai
 
 import (
 	"encoding/json"
-	"io"
+	"errors"
 	"net/http"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/provider"
 )
 
@@ -16,6 +17,11 @@ func (h *Handler) handleAudioSpeech(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
 	synthesizer, err := h.Synthesizer(req.Model)
 
 	if err != nil {
@@ -23,8 +29,27 @@ func (h *Handler) handleAudioSpeech(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ResponseFormat == "" {
+		req.ResponseFormat = SpeechFormatMP3
+	}
+
+	contentType, ok := speechContentTypes[req.ResponseFormat]
+
+	if !ok {
+		writeError(w, http.StatusBadRequest, errors.New("unsupported response format: "+string(req.ResponseFormat)))
+		return
+	}
+
+	if req.Speed != nil && (*req.Speed < 0.25 || *req.Speed > 4) {
+		writeError(w, http.StatusBadRequest, errors.New("speed must be between 0.25 and 4.0"))
+		return
+	}
+
 	options := &provider.SynthesizeOptions{
 		Voice: req.Voice,
+
+		Format: provider.SpeechFormat(req.ResponseFormat),
+		Speed:  req.Speed,
 	}
 
 	synthesis, err := synthesizer.Synthesize(r.Context(), req.Input, options)
@@ -36,6 +61,32 @@ func (h *Handler) handleAudioSpeech(w http.ResponseWriter, r *http.Request) {
 
 	defer synthesis.Content.Close()
 
-	w.Header().Set("Content-Type", "audio/wav")
-	io.Copy(w, synthesis.Content)
+	w.Header().Set("Content-Type", contentType)
+
+	buf := make([]byte, 32*1024)
+
+	for {
+		n, err := synthesis.Content.Read(buf)
+
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+
+			w.(http.Flusher).Flush()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+var speechContentTypes = map[SpeechFormat]string{
+	SpeechFormatMP3:  "audio/mpeg",
+	SpeechFormatOpus: "audio/ogg",
+	SpeechFormatAAC:  "audio/aac",
+	SpeechFormatFLAC: "audio/flac",
+	SpeechFormatWAV:  "audio/wav",
+	SpeechFormatPCM:  "audio/pcm",
 }
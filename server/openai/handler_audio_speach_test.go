@@ -0,0 +1,125 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+// recordingSynthesizer records the options of the last request and speaks the input as is
+type recordingSynthesizer struct {
+	voices []provider.Voice
+	err    error
+
+	options *provider.SynthesizeOptions
+}
+
+func (s *recordingSynthesizer) Voices(ctx context.Context) ([]provider.Voice, error) {
+	return s.voices, s.err
+}
+
+func (s *recordingSynthesizer) Synthesize(ctx context.Context, content string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	s.options = options
+
+	return &provider.Synthesis{
+		Content: io.NopCloser(strings.NewReader(content)),
+	}, nil
+}
+
+func postSpeech(h http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/audio/speech", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestAudioSpeech(t *testing.T) {
+	synthesizer := &recordingSynthesizer{}
+
+	h := newTestHandler(t, &blockingCompleter{})
+	h.RegisterSynthesizer("speaker", synthesizer)
+
+	rec := postSpeech(h, `{"model":"speaker","input":"hello","voice":"nova","speed":1.5,"response_format":"wav"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "audio/wav" {
+		t.Errorf("expected audio/wav, got %q", got)
+	}
+
+	if rec.Body.String() != "hello" {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+
+	options := synthesizer.options
+
+	if options.Voice != "nova" || options.Format != provider.SpeechFormatWAV || options.Speed == nil || *options.Speed != 1.5 {
+		t.Errorf("unexpected options: %+v", options)
+	}
+
+	rec = postSpeech(h, `{"model":"speaker","input":"hello","voice":"nova"}`)
+
+	if got := rec.Header().Get("Content-Type"); got != "audio/mpeg" || synthesizer.options.Format != provider.SpeechFormatMP3 {
+		t.Errorf("expected mp3 by default, got %q", got)
+	}
+}
+
+func TestAudioSpeechValidation(t *testing.T) {
+	h := newTestHandler(t, &blockingCompleter{})
+	h.RegisterSynthesizer("speaker", &recordingSynthesizer{})
+
+	for name, body := range map[string]string{
+		"format":      `{"model":"speaker","input":"hello","voice":"nova","response_format":"ogg"}`,
+		"slow":        `{"model":"speaker","input":"hello","voice":"nova","speed":0.1}`,
+		"fast":        `{"model":"speaker","input":"hello","voice":"nova","speed":4.5}`,
+		"model":       `{"model":"unknown","input":"hello","voice":"nova"}`,
+		"malformed":   `{"model":"speaker",`,
+		"synthesizer": `{"model":"fake","input":"hello","voice":"nova"}`,
+	} {
+		if rec := postSpeech(h, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestAudioVoices(t *testing.T) {
+	h := newTestHandler(t, &blockingCompleter{})
+
+	h.RegisterSynthesizer("speaker", &recordingSynthesizer{voices: []provider.Voice{{ID: "nova", Name: "Nova"}}})
+	h.RegisterSynthesizer("broken", &recordingSynthesizer{err: errors.New("unavailable")})
+
+	var result VoiceList
+	doRequest(t, h, http.MethodGet, "/audio/voices", "", nil, &result)
+
+	// the failing synthesizer is skipped and chat models are not listed
+	if len(result.Voices) != 1 || result.Voices[0] != (Voice{Object: "voice", ID: "nova", Name: "Nova", Model: "speaker"}) {
+		t.Errorf("unexpected voices: %+v", result.Voices)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/audio/voices?model=broken", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a failing synthesizer, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/audio/voices?model=fake", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a model without synthesizer, got %d", rec.Code)
+	}
+}
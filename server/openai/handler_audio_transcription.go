@@ -1,9 +1,16 @@
 package openai
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/to"
 )
 
 func (h *Handler) handleAudioTranscription(w http.ResponseWriter, r *http.Request) {
@@ -14,6 +21,11 @@ func (h *Handler) handleAudioTranscription(w http.ResponseWriter, r *http.Reques
 
 	model := r.FormValue("model")
 
+	if !authorizer.AllowModel(r.Context(), model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+model))
+		return
+	}
+
 	transcriber, err := h.Transcriber(model)
 
 	if err != nil {
@@ -21,11 +33,22 @@ func (h *Handler) handleAudioTranscription(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	prompt := r.FormValue("prompt")
-	language := r.FormValue("language")
+	format := TranscriptionFormat(r.FormValue("response_format"))
+
+	if format == "" {
+		format = TranscriptionFormatJSON
+	}
+
+	if !slices.Contains([]TranscriptionFormat{TranscriptionFormatJSON, TranscriptionFormatText, TranscriptionFormatSRT, TranscriptionFormatVTT, TranscriptionFormatVerboseJSON}, format) {
+		writeError(w, http.StatusBadRequest, errors.New("unsupported response format: "+string(format)))
+		return
+	}
+
+	granularities := r.Form["timestamp_granularities[]"]
 
-	_ = prompt
-	_ = language
+	if len(granularities) == 0 {
+		granularities = r.Form["timestamp_granularities"]
+	}
 
 	file, header, err := r.FormFile("file")
 
@@ -41,7 +64,22 @@ func (h *Handler) handleAudioTranscription(w http.ResponseWriter, r *http.Reques
 		Name:    header.Filename,
 	}
 
-	options := &provider.TranscribeOptions{}
+	options := &provider.TranscribeOptions{
+		Language: r.FormValue("language"),
+
+		Words: format == TranscriptionFormatVerboseJSON && slices.Contains(granularities, "word"),
+	}
+
+	if val := r.FormValue("temperature"); val != "" {
+		temperature, err := strconv.ParseFloat(val, 32)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid temperature: "+val))
+			return
+		}
+
+		options.Temperature = to.Ptr(float32(temperature))
+	}
 
 	transcription, err := transcriber.Transcribe(r.Context(), input, options)
 
@@ -50,14 +88,101 @@ func (h *Handler) handleAudioTranscription(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	result := Transcription{
-		Task: "transcribe",
+	switch format {
+	case TranscriptionFormatText:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(transcription.Content))
+
+	case TranscriptionFormatSRT:
+		w.Header().Set("Content-Type", "application/x-subrip")
+		w.Write([]byte(toSRT(transcription)))
+
+	case TranscriptionFormatVTT:
+		w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+		w.Write([]byte(toVTT(transcription)))
+
+	default:
+		result := Transcription{
+			Task: "transcribe",
+
+			Language: transcription.Language,
+			Duration: transcription.Duration,
+
+			Text: transcription.Content,
+		}
+
+		if format == TranscriptionFormatVerboseJSON {
+			for _, s := range transcriptionSegments(transcription) {
+				result.Segments = append(result.Segments, TranscriptionSegment{
+					ID: s.ID,
+
+					Start: s.Start,
+					End:   s.End,
+
+					Text: s.Text,
+				})
+			}
+
+			if options.Words {
+				for _, w := range transcription.Words {
+					result.Words = append(result.Words, TranscriptionWord{
+						Start: w.Start,
+						End:   w.End,
+
+						Word: w.Text,
+					})
+				}
+			}
+		}
+
+		writeJson(w, result)
+	}
+}
+
+func transcriptionSegments(transcription *provider.Transcription) []provider.TranscriptionSegment {
+	if len(transcription.Segments) > 0 || transcription.Content == "" {
+		return transcription.Segments
+	}
+
+	// without a duration there is no end to time the content by, and a 00:00:00 --> 00:00:00 cue would never be shown
+	if transcription.Duration <= 0 {
+		return nil
+	}
 
-		Language: transcription.Language,
-		Duration: transcription.Duration,
+	return []provider.TranscriptionSegment{
+		{
+			Start: 0,
+			End:   transcription.Duration,
 
-		Text: transcription.Content,
+			Text: transcription.Content,
+		},
 	}
+}
+
+func toSRT(transcription *provider.Transcription) string {
+	var sb strings.Builder
+
+	for i, s := range transcriptionSegments(transcription) {
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", i+1, formatTimestamp(s.Start, ","), formatTimestamp(s.End, ","), strings.TrimSpace(s.Text))
+	}
+
+	return sb.String()
+}
+
+func toVTT(transcription *provider.Transcription) string {
+	var sb strings.Builder
+
+	sb.WriteString("WEBVTT\n\n")
+
+	for _, s := range transcriptionSegments(transcription) {
+		fmt.Fprintf(&sb, "%s --> %s\n%s\n\n", formatTimestamp(s.Start, "."), formatTimestamp(s.End, "."), strings.TrimSpace(s.Text))
+	}
+
+	return sb.String()
+}
+
+func formatTimestamp(seconds float64, separator string) string {
+	ms := int64(seconds*1000 + 0.5)
 
-	writeJson(w, result)
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, separator, ms%1000)
 }
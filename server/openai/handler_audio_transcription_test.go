@@ -0,0 +1,93 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		seconds   float64
+		separator string
+		want      string
+	}{
+		{0, ",", "00:00:00,000"},
+		{1.5, ",", "00:00:01,500"},
+		{59.9994, ".", "00:00:59.999"},
+		{59.9995, ".", "00:01:00.000"},
+		{61.0004, ",", "00:01:01,000"},
+		{3599.9996, ",", "01:00:00,000"},
+		{3723.042, ".", "01:02:03.042"},
+		{36000, ".", "10:00:00.000"},
+	}
+
+	for _, tt := range tests {
+		if got := formatTimestamp(tt.seconds, tt.separator); got != tt.want {
+			t.Errorf("formatTimestamp(%v, %q) = %q, want %q", tt.seconds, tt.separator, got, tt.want)
+		}
+	}
+}
+
+func TestSubtitles(t *testing.T) {
+	transcription := &provider.Transcription{
+		Duration: 3725,
+
+		Content: "Hello world. Goodbye.",
+
+		Segments: []provider.TranscriptionSegment{
+			{ID: 0, Start: 0, End: 1.25, Text: " Hello world."},
+			{ID: 1, Start: 3600.5, End: 3724.0004, Text: "Goodbye. "},
+		},
+	}
+
+	srt := "1\n" +
+		"00:00:00,000 --> 00:00:01,250\n" +
+		"Hello world.\n" +
+		"\n" +
+		"2\n" +
+		"01:00:00,500 --> 01:02:04,000\n" +
+		"Goodbye.\n" +
+		"\n"
+
+	if got := toSRT(transcription); got != srt {
+		t.Errorf("unexpected srt:\n%s\nwant:\n%s", got, srt)
+	}
+
+	vtt := "WEBVTT\n" +
+		"\n" +
+		"00:00:00.000 --> 00:00:01.250\n" +
+		"Hello world.\n" +
+		"\n" +
+		"01:00:00.500 --> 01:02:04.000\n" +
+		"Goodbye.\n" +
+		"\n"
+
+	if got := toVTT(transcription); got != vtt {
+		t.Errorf("unexpected vtt:\n%s\nwant:\n%s", got, vtt)
+	}
+}
+
+func TestSubtitlesWithoutSegments(t *testing.T) {
+	transcription := &provider.Transcription{
+		Duration: 2.5,
+		Content:  "Hello world.",
+	}
+
+	srt := "1\n00:00:00,000 --> 00:00:02,500\nHello world.\n\n"
+
+	if got := toSRT(transcription); got != srt {
+		t.Errorf("unexpected srt:\n%s\nwant:\n%s", got, srt)
+	}
+
+	// without a duration the content cannot be timed
+	transcription.Duration = 0
+
+	if got := toSRT(transcription); got != "" {
+		t.Errorf("expected empty srt, got:\n%s", got)
+	}
+
+	if got := toVTT(transcription); got != "WEBVTT\n\n" {
+		t.Errorf("expected empty vtt, got:\n%s", got)
+	}
+}
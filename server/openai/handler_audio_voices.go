@@ -0,0 +1,86 @@
+package openai
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+func (h *Handler) handleAudioVoices(w http.ResponseWriter, r *http.Request) {
+	var models []string
+
+	explicit := r.URL.Query().Get("model") != ""
+
+	if model := r.URL.Query().Get("model"); model != "" {
+		if !authorizer.AllowModel(r.Context(), model) {
+			writeError(w, http.StatusForbidden, errors.New("model not allowed: "+model))
+			return
+		}
+
+		if _, err := h.Synthesizer(model); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		models = append(models, model)
+	} else {
+		for _, m := range h.Models() {
+			if !authorizer.AllowModel(r.Context(), m.ID) {
+				continue
+			}
+
+			if _, err := h.Synthesizer(m.ID); err != nil {
+				continue
+			}
+
+			models = append(models, m.ID)
+		}
+	}
+
+	result := &VoiceList{
+		Object: "list",
+
+		Voices: []Voice{},
+	}
+
+	for _, model := range models {
+		synthesizer, _ := h.Synthesizer(model)
+
+		voices, err := synthesizer.Voices(r.Context())
+
+		if err != nil {
+			if explicit {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			// one unreachable synthesizer should not hide the voices of all others
+			slog.Warn("failed to list voices", "model", model, "error", err)
+			continue
+		}
+
+		result.Voices = append(result.Voices, toVoices(model, voices)...)
+	}
+
+	writeJson(w, result)
+}
+
+func toVoices(model string, voices []provider.Voice) []Voice {
+	var result []Voice
+
+	for _, v := range voices {
+		result = append(result, Voice{
+			Object: "voice",
+
+			ID:   v.ID,
+			Name: v.Name,
+
+			Model: model,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,601 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/batch"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const batchConcurrency = 8
+
+// batchProgressInterval throttles progress writes, which rewrite the whole batch in file stores
+const batchProgressInterval = 2 * time.Second
+
+var batchEndpoints = []string{
+	"/v1/chat/completions",
+	"/v1/embeddings",
+}
+
+func (h *Handler) handleBatchCreate(w http.ResponseWriter, r *http.Request) {
+	var req BatchCreateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !isBatchEndpoint(req.Endpoint) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported endpoint: %s", req.Endpoint))
+		return
+	}
+
+	if req.CompletionWindow == "" {
+		req.CompletionWindow = "24h"
+	}
+
+	if req.CompletionWindow != "24h" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported completion window: %s", req.CompletionWindow))
+		return
+	}
+
+	store := h.Batches()
+
+	input, err := store.GetFile(r.Context(), req.InputFileID)
+
+	if err != nil || !isOwner(r, input.Owner) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("input file not found: %s", req.InputFileID))
+		return
+	}
+
+	if input.Purpose != "batch" {
+		writeError(w, http.StatusBadRequest, errors.New("input file must have purpose batch"))
+		return
+	}
+
+	b := batch.Batch{
+		ID: "batch_" + uuid.NewString(),
+
+		Endpoint: req.Endpoint,
+		Window:   req.CompletionWindow,
+
+		InputFile: input.ID,
+
+		Status: batch.StatusValidating,
+
+		Metadata: req.Metadata,
+
+		CreatedAt: time.Now(),
+	}
+
+	if p, ok := authorizer.PrincipalFromContext(r.Context()); ok {
+		b.Owner = p.ID
+	}
+
+	if err := store.CreateBatch(r.Context(), b); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	h.batches.Store(b.ID, cancel)
+
+	go func() {
+		defer h.batches.Delete(b.ID)
+		defer cancel()
+
+		h.executeBatch(ctx, store, b)
+	}()
+
+	writeJson(w, oaiBatch(b))
+}
+
+func (h *Handler) handleBatches(w http.ResponseWriter, r *http.Request) {
+	list, err := h.Batches().ListBatches(r.Context())
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+
+	var owned []batch.Batch
+
+	for _, b := range list {
+		if isOwner(r, b.Owner) {
+			owned = append(owned, b)
+		}
+	}
+
+	owned, hasMore := paginate(r, owned, func(b batch.Batch) string { return b.ID })
+
+	result := BatchList{
+		Object: "list",
+
+		Batches: make([]Batch, 0),
+		HasMore: hasMore,
+	}
+
+	for _, b := range owned {
+		result.Batches = append(result.Batches, oaiBatch(b))
+	}
+
+	if len(owned) > 0 {
+		result.FirstID = owned[0].ID
+		result.LastID = owned[len(owned)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleBatch(w http.ResponseWriter, r *http.Request) {
+	b, err := h.batch(r)
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	writeJson(w, oaiBatch(*b))
+}
+
+func (h *Handler) handleBatchCancel(w http.ResponseWriter, r *http.Request) {
+	h.batchMu.Lock()
+	defer h.batchMu.Unlock()
+
+	b, err := h.batch(r)
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	switch b.Status {
+	case batch.StatusValidating, batch.StatusInProgress:
+	default:
+		writeError(w, http.StatusConflict, fmt.Errorf("cannot cancel batch with status %s", b.Status))
+		return
+	}
+
+	if cancel, ok := h.batches.Load(b.ID); ok {
+		b.Status = batch.StatusCancelling
+
+		if err := h.Batches().UpdateBatch(r.Context(), *b); err != nil {
+			writeBatchError(w, err)
+			return
+		}
+
+		cancel.(context.CancelFunc)()
+	} else {
+		now := time.Now()
+
+		b.Status = batch.StatusCancelled
+		b.CancelledAt = &now
+
+		if err := h.Batches().UpdateBatch(r.Context(), *b); err != nil {
+			writeBatchError(w, err)
+			return
+		}
+	}
+
+	writeJson(w, oaiBatch(*b))
+}
+
+func (h *Handler) batch(r *http.Request) (*batch.Batch, error) {
+	b, err := h.Batches().GetBatch(r.Context(), r.PathValue("batch"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOwner(r, b.Owner) {
+		return nil, batch.ErrNotFound
+	}
+
+	return b, nil
+}
+
+func (h *Handler) executeBatch(ctx context.Context, store batch.Store, b batch.Batch) {
+	// status updates must outlive a cancelled batch
+	storeCtx := context.WithoutCancel(ctx)
+
+	fail := func(err error) {
+		now := time.Now()
+
+		b.Status = batch.StatusFailed
+		b.Error = err.Error()
+		b.FailedAt = &now
+
+		store.UpdateBatch(storeCtx, b)
+	}
+
+	requests, err := readBatchRequests(ctx, store, b)
+
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	now := time.Now()
+
+	b.Status = batch.StatusInProgress
+	b.Total = len(requests)
+	b.StartedAt = &now
+
+	if err := h.updateBatchProgress(storeCtx, store, b); err != nil {
+		return
+	}
+
+	output, err := os.CreateTemp("", "batch-output-*.jsonl")
+
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	defer os.Remove(output.Name())
+	defer output.Close()
+
+	errput, err := os.CreateTemp("", "batch-error-*.jsonl")
+
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	defer os.Remove(errput.Name())
+	defer errput.Close()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	var updated time.Time
+
+	queue := make(chan BatchRequestInput)
+
+	for range batchConcurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for req := range queue {
+				result, ok := h.executeBatchRequest(ctx, req)
+
+				data, _ := json.Marshal(result)
+
+				mu.Lock()
+
+				if ok {
+					b.Completed++
+					output.Write(append(data, '\n'))
+				} else {
+					b.Failed++
+					errput.Write(append(data, '\n'))
+				}
+
+				if time.Since(updated) >= batchProgressInterval {
+					h.updateBatchProgress(storeCtx, store, b)
+					updated = time.Now()
+				}
+
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, req := range requests {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case queue <- req:
+		}
+	}
+
+	close(queue)
+	wg.Wait()
+
+	now = time.Now()
+
+	b.Status = batch.StatusFinalizing
+	b.FinalizingAt = &now
+
+	store.UpdateBatch(storeCtx, b)
+
+	if b.Completed > 0 {
+		f, err := createBatchFile(storeCtx, store, b, output)
+
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		b.OutputFile = f.ID
+	}
+
+	if b.Failed > 0 {
+		f, err := createBatchFile(storeCtx, store, b, errput)
+
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		b.ErrorFile = f.ID
+	}
+
+	now = time.Now()
+
+	if ctx.Err() != nil {
+		b.Status = batch.StatusCancelled
+		b.CancelledAt = &now
+	} else {
+		b.Status = batch.StatusCompleted
+		b.CompletedAt = &now
+	}
+
+	store.UpdateBatch(storeCtx, b)
+}
+
+// updateBatchProgress stores the batch without reverting a cancel that was requested in the meantime
+func (h *Handler) updateBatchProgress(ctx context.Context, store batch.Store, b batch.Batch) error {
+	h.batchMu.Lock()
+	defer h.batchMu.Unlock()
+
+	current, err := store.GetBatch(ctx, b.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if current.Status == batch.StatusCancelling {
+		b.Status = current.Status
+	}
+
+	return store.UpdateBatch(ctx, b)
+}
+
+func (h *Handler) executeBatchRequest(ctx context.Context, input BatchRequestInput) (BatchRequestOutput, bool) {
+	result := BatchRequestOutput{
+		ID:       "batch_req_" + uuid.NewString(),
+		CustomID: input.CustomID,
+	}
+
+	// the batch context inherits the route context of the create request, concurrent requests need their own
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, (*chi.Context)(nil))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimPrefix(input.URL, "/v1"), bytes.NewReader(input.Body))
+
+	if err != nil {
+		result.Error = &Error{
+			Type:    "invalid_request_error",
+			Message: err.Error(),
+		}
+
+		return result, false
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	w := &batchResponseWriter{
+		header: make(http.Header),
+		status: http.StatusOK,
+	}
+
+	h.Handler.ServeHTTP(w, req)
+
+	result.Response = &BatchResponse{
+		StatusCode: w.status,
+		RequestID:  uuid.NewString(),
+		Body:       json.RawMessage(bytes.TrimSpace(w.body.Bytes())),
+	}
+
+	if !json.Valid(result.Response.Body) {
+		result.Response.Body, _ = json.Marshal(w.body.String())
+	}
+
+	return result, w.status >= 200 && w.status < 300
+}
+
+func readBatchRequests(ctx context.Context, store batch.Store, b batch.Batch) ([]BatchRequestInput, error) {
+	content, err := store.OpenFile(ctx, b.InputFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer content.Close()
+
+	var result []BatchRequestInput
+
+	ids := make(map[string]bool)
+
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+
+		if len(data) == 0 {
+			continue
+		}
+
+		var req BatchRequestInput
+
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if req.CustomID == "" {
+			return nil, fmt.Errorf("line %d: custom_id is required", line)
+		}
+
+		if ids[req.CustomID] {
+			return nil, fmt.Errorf("line %d: duplicate custom_id: %s", line, req.CustomID)
+		}
+
+		if req.Method != http.MethodPost {
+			return nil, fmt.Errorf("line %d: unsupported method: %s", line, req.Method)
+		}
+
+		if req.URL != b.Endpoint {
+			return nil, fmt.Errorf("line %d: url %s does not match batch endpoint %s", line, req.URL, b.Endpoint)
+		}
+
+		var body struct {
+			Model string `json:"model"`
+		}
+
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		// the batch context carries the principal of the creating request
+		if !authorizer.AllowModel(ctx, body.Model) {
+			return nil, fmt.Errorf("line %d: model not allowed: %s", line, body.Model)
+		}
+
+		ids[req.CustomID] = true
+		result = append(result, req)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("input file contains no requests")
+	}
+
+	return result, nil
+}
+
+func createBatchFile(ctx context.Context, store batch.Store, b batch.Batch, content *os.File) (*batch.File, error) {
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	f := batch.File{
+		ID:    "file-" + uuid.NewString(),
+		Owner: b.Owner,
+
+		Name:    b.ID + ".jsonl",
+		Purpose: "batch_output",
+
+		CreatedAt: time.Now(),
+	}
+
+	return store.CreateFile(ctx, f, content)
+}
+
+func isBatchEndpoint(endpoint string) bool {
+	for _, e := range batchEndpoints {
+		if e == endpoint {
+			return true
+		}
+	}
+
+	return false
+}
+
+type batchResponseWriter struct {
+	header http.Header
+	status int
+
+	body bytes.Buffer
+}
+
+func (w *batchResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *batchResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *batchResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *batchResponseWriter) Flush() {
+}
+
+func oaiBatch(b batch.Batch) Batch {
+	result := Batch{
+		Object: "batch",
+
+		ID: b.ID,
+
+		Endpoint:         b.Endpoint,
+		CompletionWindow: b.Window,
+
+		InputFileID: b.InputFile,
+
+		Status: string(b.Status),
+
+		CreatedAt: b.CreatedAt.Unix(),
+
+		RequestCounts: BatchRequestCounts{
+			Total:     b.Total,
+			Completed: b.Completed,
+			Failed:    b.Failed,
+		},
+
+		Metadata: b.Metadata,
+	}
+
+	if b.OutputFile != "" {
+		result.OutputFileID = &b.OutputFile
+	}
+
+	if b.ErrorFile != "" {
+		result.ErrorFileID = &b.ErrorFile
+	}
+
+	if b.Error != "" {
+		result.Errors = &BatchErrors{
+			Object: "list",
+
+			Data: []BatchError{
+				{
+					Code:    "invalid_request",
+					Message: b.Error,
+				},
+			},
+		}
+	}
+
+	result.InProgressAt = unixTime(b.StartedAt)
+	result.FinalizingAt = unixTime(b.FinalizingAt)
+	result.CompletedAt = unixTime(b.CompletedAt)
+	result.FailedAt = unixTime(b.FailedAt)
+	result.CancelledAt = unixTime(b.CancelledAt)
+
+	return result
+}
+
+func unixTime(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+
+	val := t.Unix()
+	return &val
+}
@@ -0,0 +1,218 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/llama/config"
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/batch"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+// blockingCompleter answers once release is closed, regardless of cancellation
+type blockingCompleter struct {
+	release chan struct{}
+}
+
+func (c *blockingCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	<-c.release
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "ok",
+		},
+	}, nil
+}
+
+func newTestHandler(t *testing.T, completer provider.Completer) *Handler {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(path, []byte("providers: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Parse(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.RegisterCompleter("fake", completer)
+
+	h, err := New(cfg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func doRequest(t *testing.T, h http.Handler, method, path, contentType string, body []byte, result any) {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s %s: %d %s", method, path, rec.Code, rec.Body.String())
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), result); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBatchCancelDuringExecution(t *testing.T) {
+	completer := &blockingCompleter{release: make(chan struct{})}
+	h := newTestHandler(t, completer)
+
+	var lines []string
+
+	for i := range 20 {
+		lines = append(lines, `{"custom_id":"request-`+strconv.Itoa(i)+`","method":"POST","url":"/v1/chat/completions","body":{"model":"fake","messages":[{"role":"user","content":"hi"}]}}`)
+	}
+
+	b := createBatch(t, h, lines)
+
+	waitBatchStatus(t, h, b.ID, batch.StatusInProgress)
+
+	doRequest(t, h, http.MethodPost, "/batches/"+b.ID+"/cancel", "", nil, &b)
+
+	if b.Status != string(batch.StatusCancelling) {
+		t.Fatalf("expected status cancelling, got %s", b.Status)
+	}
+
+	// requests in flight complete after the cancel and report their progress
+	close(completer.release)
+
+	result := waitBatchStatus(t, h, b.ID, batch.StatusCancelled)
+
+	if result.RequestCounts.Completed+result.RequestCounts.Failed >= 20 {
+		t.Errorf("expected cancelled batch to skip requests, got %+v", result.RequestCounts)
+	}
+}
+
+func TestBatchModelNotAllowed(t *testing.T) {
+	h := newTestHandler(t, &blockingCompleter{release: make(chan struct{})})
+
+	// requests of a key restricted to the fake model
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := authorizer.WithPrincipal(r.Context(), &authorizer.Principal{
+			ID:     "alice",
+			Models: []string{"fake"},
+		})
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+
+	b := createBatch(t, handler, []string{
+		`{"custom_id":"allowed","method":"POST","url":"/v1/chat/completions","body":{"model":"fake","messages":[{"role":"user","content":"hi"}]}}`,
+		`{"custom_id":"denied","method":"POST","url":"/v1/chat/completions","body":{"model":"other","messages":[{"role":"user","content":"hi"}]}}`,
+	})
+
+	result := waitBatchStatus(t, handler, b.ID, batch.StatusFailed)
+
+	if result.Errors == nil || len(result.Errors.Data) == 0 || !strings.Contains(result.Errors.Data[0].Message, "line 2: model not allowed: other") {
+		t.Errorf("expected model not allowed error, got %+v", result.Errors)
+	}
+}
+
+func TestBatchProgressKeepsCancelling(t *testing.T) {
+	h := newTestHandler(t, &blockingCompleter{})
+	store := h.Batches()
+
+	ctx := context.Background()
+
+	b := batch.Batch{
+		ID:     "batch_test",
+		Status: batch.StatusInProgress,
+		Total:  10,
+	}
+
+	if err := store.CreateBatch(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled := b
+	cancelled.Status = batch.StatusCancelling
+
+	if err := store.UpdateBatch(ctx, cancelled); err != nil {
+		t.Fatal(err)
+	}
+
+	b.Completed = 3
+
+	if err := h.updateBatchProgress(ctx, store, b); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.GetBatch(ctx, b.ID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Status != batch.StatusCancelling || result.Completed != 3 {
+		t.Errorf("expected cancelling with 3 completed, got %s with %d", result.Status, result.Completed)
+	}
+}
+
+func createBatch(t *testing.T, h http.Handler, lines []string) Batch {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("purpose", "batch")
+
+	fw, _ := mw.CreateFormFile("file", "input.jsonl")
+	fw.Write([]byte(strings.Join(lines, "\n")))
+
+	mw.Close()
+
+	var file File
+	doRequest(t, h, http.MethodPost, "/files", mw.FormDataContentType(), body.Bytes(), &file)
+
+	var b Batch
+	doRequest(t, h, http.MethodPost, "/batches", "application/json", []byte(`{"input_file_id":"`+file.ID+`","endpoint":"/v1/chat/completions","completion_window":"24h"}`), &b)
+
+	return b
+}
+
+func waitBatchStatus(t *testing.T, h http.Handler, id string, status batch.Status) Batch {
+	deadline := time.Now().Add(10 * time.Second)
+
+	for {
+		var b Batch
+		doRequest(t, h, http.MethodGet, "/batches/"+id, "", nil, &b)
+
+		if b.Status == string(status) {
+			return b
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for status %s, got %s (%+v)", status, b.Status, b.Errors)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -13,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/provider"
 
 	"github.com/google/uuid"
@@ -26,6 +28,11 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
 	completer, err := h.Completer(req.Model)
 
 	if err != nil {
@@ -47,87 +54,101 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var stops []string
+	stops, err := toStrings(req.Stop)
 
-	switch v := req.Stop.(type) {
-	case string:
-		stops = []string{v}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("stop must be a string or an array of strings"))
+		return
+	}
 
-	case []string:
-		stops = v
+	if len(req.LogitBias) > 0 {
+		writeError(w, http.StatusBadRequest, errors.New("logit_bias is not supported"))
+		return
+	}
+
+	toolChoice, err := toToolChoice(req.ToolChoice)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Stream && req.N != nil && *req.N > 1 {
+		writeError(w, http.StatusBadRequest, errors.New("n > 1 is not supported when streaming"))
+		return
 	}
 
 	options := &provider.CompleteOptions{
 		Stop:  stops,
 		Tools: tools,
 
+		ToolChoice: toolChoice,
+
 		MaxTokens:   req.MaxTokens,
 		Temperature: req.Temperature,
+		TopP:        req.TopP,
+
+		Seed: req.Seed,
+
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+
+		N: req.N,
+
+		Logprobs:    req.Logprobs,
+		TopLogprobs: req.TopLogprobs,
 	}
 
 	if req.ResponseFormat != nil {
 		if req.ResponseFormat.Type == ResponseFormatJSON {
 			options.Format = provider.CompletionFormatJSON
 		}
-	}
-
-	if req.Stream {
-		w.Header().Set("Content-Type", "text/event-stream")
 
-		options.Stream = func(ctx context.Context, completion provider.Completion) error {
-			result := ChatCompletion{
-				Object: "chat.completion.chunk",
+		if req.ResponseFormat.Type == ResponseFormatJSONSchema {
+			schema, err := toSchema(req.ResponseFormat.JSONSchema)
 
-				ID: completion.ID,
-
-				Model:   req.Model,
-				Created: time.Now().Unix(),
-
-				Choices: []ChatCompletionChoice{
-					{
-						FinishReason: oaiFinishReason(completion.Reason),
-
-						Delta: &ChatCompletionMessage{
-							Role:    oaiMessageRole(completion.Message.Role),
-							Content: completion.Message.Content,
-
-							ToolCalls:  oaiToolCalls(completion.Message.ToolCalls),
-							ToolCallID: completion.Message.Tool,
-						},
-					},
-				},
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
 			}
 
-			if completion.Usage != nil {
-				result.Usage = &Usage{
-					PromptTokens:     completion.Usage.InputTokens,
-					CompletionTokens: completion.Usage.OutputTokens,
-					TotalTokens:      completion.Usage.InputTokens + completion.Usage.OutputTokens,
-				}
-			}
+			options.Format = provider.CompletionFormatJSONSchema
+			options.Schema = schema
+		}
+	}
 
-			var data bytes.Buffer
+	if req.Stream {
+		w.Header().Set("Content-Type", "text/event-stream")
 
-			enc := json.NewEncoder(&data)
-			enc.SetEscapeHTML(false)
-			enc.Encode(result)
+		s := &chatStreamWriter{
+			w: w,
 
-			event := strings.TrimSpace(data.String())
+			id:      "chatcmpl-" + uuid.NewString(),
+			model:   req.Model,
+			created: time.Now().Unix(),
 
-			if _, err := fmt.Fprintf(w, "data: %s\n\n", event); err != nil {
-				return err
-			}
+			toolIndex: -1,
+		}
 
-			w.(http.Flusher).Flush()
+		options.Stream = s.write
 
-			return nil
-		}
+		completion, err := completer.Complete(r.Context(), messages, options)
 
-		if _, err := completer.Complete(r.Context(), messages, options); err != nil {
+		if err != nil {
 			writeError(w, http.StatusBadRequest, err)
 			return
 		}
 
+		if err := s.finish(completion); err != nil {
+			return
+		}
+
+		if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+			if err := s.writeUsage(completion.Usage); err != nil {
+				return
+			}
+		}
+
 		fmt.Fprintf(w, "data: [DONE]\n\n")
 		w.(http.Flusher).Flush()
 
@@ -147,19 +168,38 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 			Model:   req.Model,
 			Created: time.Now().Unix(),
 
-			Choices: []ChatCompletionChoice{
+			Choices: []ChatCompletionChoice{},
+		}
+
+		choices := completion.Choices
+
+		if len(choices) == 0 {
+			choices = []provider.CompletionChoice{
 				{
-					FinishReason: oaiFinishReason(completion.Reason),
+					Reason: completion.Reason,
 
-					Message: &ChatCompletionMessage{
-						Role:    oaiMessageRole(completion.Message.Role),
-						Content: completion.Message.Content,
+					Message:  completion.Message,
+					Logprobs: completion.Logprobs,
+				},
+			}
+		}
+
+		for i, c := range choices {
+			result.Choices = append(result.Choices, ChatCompletionChoice{
+				Index: i,
 
-						ToolCalls:  oaiToolCalls(completion.Message.ToolCalls),
-						ToolCallID: completion.Message.Tool,
-					},
+				FinishReason: oaiFinishReason(c.Reason),
+
+				Message: &ChatCompletionMessage{
+					Role:    oaiMessageRole(c.Message.Role),
+					Content: c.Message.Content,
+
+					ToolCalls:  oaiToolCalls(c.Message.ToolCalls),
+					ToolCallID: c.Message.Tool,
 				},
-			},
+
+				Logprobs: oaiLogprobs(c.Logprobs),
+			})
 		}
 
 		if completion.Usage != nil {
@@ -174,6 +214,224 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// chatStreamWriter keeps the completion id and tool call indexes stable across chunks
+type chatStreamWriter struct {
+	w http.ResponseWriter
+
+	id      string
+	model   string
+	created int64
+
+	toolID    string
+	toolIndex int
+	toolCount int
+
+	reason *FinishReason
+}
+
+func (s *chatStreamWriter) write(ctx context.Context, completion provider.Completion) error {
+	toolCalls := s.toolCallDeltas(completion.Message.ToolCalls)
+
+	if completion.Message.Content == "" && len(toolCalls) == 0 && len(completion.Logprobs) == 0 && completion.Reason == "" {
+		return nil
+	}
+
+	reason := oaiFinishReason(completion.Reason)
+
+	if reason != nil && *reason == FinishReasonStop && s.toolCount > 0 {
+		reason = &FinishReasonToolCalls
+	}
+
+	return s.writeChoice(ChatCompletionChoice{
+		FinishReason: reason,
+
+		Delta: &ChatCompletionMessage{
+			Role:    MessageRoleAssistant,
+			Content: completion.Message.Content,
+
+			ToolCalls: toolCalls,
+		},
+
+		Logprobs: oaiLogprobs(completion.Logprobs),
+	})
+}
+
+func (s *chatStreamWriter) finish(completion *provider.Completion) error {
+	delta := &ChatCompletionMessage{
+		Role: MessageRoleAssistant,
+	}
+
+	// providers not streaming tool calls only return them with the final result
+	if s.toolCount == 0 && len(completion.Message.ToolCalls) > 0 {
+		for _, t := range completion.Message.ToolCalls {
+			if t.ID == "" {
+				t.ID = "call_" + uuid.NewString()
+			}
+
+			delta.ToolCalls = append(delta.ToolCalls, s.toolCallDeltas([]provider.ToolCall{t})...)
+		}
+	}
+
+	if s.reason != nil && len(delta.ToolCalls) == 0 {
+		return nil
+	}
+
+	reason := oaiFinishReason(completion.Reason)
+
+	if s.toolCount > 0 {
+		reason = &FinishReasonToolCalls
+	}
+
+	if reason == nil {
+		reason = &FinishReasonStop
+	}
+
+	return s.writeChoice(ChatCompletionChoice{
+		FinishReason: reason,
+
+		Delta: delta,
+	})
+}
+
+func (s *chatStreamWriter) writeUsage(usage *provider.Usage) error {
+	result := ChatCompletion{
+		Object: "chat.completion.chunk",
+
+		ID: s.id,
+
+		Model:   s.model,
+		Created: s.created,
+
+		Choices: []ChatCompletionChoice{},
+
+		Usage: &Usage{},
+	}
+
+	if usage != nil {
+		result.Usage = &Usage{
+			PromptTokens:     usage.InputTokens,
+			CompletionTokens: usage.OutputTokens,
+			TotalTokens:      usage.InputTokens + usage.OutputTokens,
+		}
+	}
+
+	return s.writeChunk(result)
+}
+
+func (s *chatStreamWriter) writeChoice(choice ChatCompletionChoice) error {
+	s.reason = choice.FinishReason
+
+	return s.writeChunk(ChatCompletion{
+		Object: "chat.completion.chunk",
+
+		ID: s.id,
+
+		Model:   s.model,
+		Created: s.created,
+
+		Choices: []ChatCompletionChoice{choice},
+	})
+}
+
+func (s *chatStreamWriter) writeChunk(chunk ChatCompletion) error {
+	var data bytes.Buffer
+
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.Encode(chunk)
+
+	event := strings.TrimSpace(data.String())
+
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", event); err != nil {
+		return err
+	}
+
+	s.w.(http.Flusher).Flush()
+
+	return nil
+}
+
+// deltas with an upstream index keep it, otherwise a tool call carrying an id or name starts a new call
+// and anything else continues the current one
+func (s *chatStreamWriter) toolCallDeltas(calls []provider.ToolCall) []ToolCall {
+	var result []ToolCall
+
+	for _, c := range calls {
+		if c.Index != nil {
+			result = append(result, s.indexedToolCallDelta(c))
+			continue
+		}
+
+		if s.toolIndex < 0 || (c.ID != "" && c.ID != s.toolID) || (c.ID == "" && c.Name != "") {
+			id := c.ID
+
+			if id == "" {
+				id = "call_" + uuid.NewString()
+			}
+
+			s.toolID = id
+			s.toolIndex = s.toolCount
+			s.toolCount++
+
+			result = append(result, ToolCall{
+				Index: s.toolIndex,
+
+				ID:   id,
+				Type: ToolTypeFunction,
+
+				Function: &FunctionCall{
+					Name:      c.Name,
+					Arguments: c.Arguments,
+				},
+			})
+
+			continue
+		}
+
+		if c.Arguments == "" {
+			continue
+		}
+
+		result = append(result, ToolCall{
+			Index: s.toolIndex,
+
+			Function: &FunctionCall{
+				Arguments: c.Arguments,
+			},
+		})
+	}
+
+	return result
+}
+
+func (s *chatStreamWriter) indexedToolCallDelta(c provider.ToolCall) ToolCall {
+	index := *c.Index
+
+	result := ToolCall{
+		Index: index,
+
+		Function: &FunctionCall{
+			Name:      c.Name,
+			Arguments: c.Arguments,
+		},
+	}
+
+	if index >= s.toolCount || c.ID != "" {
+		id := c.ID
+
+		if id == "" {
+			id = "call_" + uuid.NewString()
+		}
+
+		result.ID = id
+		result.Type = ToolTypeFunction
+
+		s.toolCount = max(s.toolCount, index+1)
+	}
+
+	return result
+}
+
 func toMessages(s []ChatCompletionMessage) ([]provider.Message, error) {
 	result := make([]provider.Message, 0)
 
@@ -306,6 +564,53 @@ func toTools(tools []Tool) ([]provider.Tool, error) {
 	return result, nil
 }
 
+func toSchema(s *JSONSchema) (*provider.Schema, error) {
+	if s == nil || s.Name == "" {
+		return nil, errors.New("json_schema name is required")
+	}
+
+	result := &provider.Schema{
+		Name:        s.Name,
+		Description: s.Description,
+
+		Schema: s.Schema,
+	}
+
+	if result.Schema == nil {
+		result.Schema = map[string]any{}
+	}
+
+	if s.Strict != nil {
+		result.Strict = *s.Strict
+	}
+
+	return result, nil
+}
+
+func toToolChoice(c *ToolChoice) (*provider.ToolChoice, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	switch c.Mode {
+	case ToolChoiceModeNone:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeNone}, nil
+
+	case ToolChoiceModeAuto:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeAuto}, nil
+
+	case ToolChoiceModeRequired:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}, nil
+
+	case "":
+		if c.Type == ToolTypeFunction && c.Function != nil && c.Function.Name != "" {
+			return &provider.ToolChoice{Mode: provider.ToolChoiceModeTool, Name: c.Function.Name}, nil
+		}
+	}
+
+	return nil, errors.New("invalid tool_choice")
+}
+
 func toToolCalls(calls []ToolCall) []provider.ToolCall {
 	var result []provider.ToolCall
 
@@ -383,3 +688,40 @@ func oaiToolCalls(calls []provider.ToolCall) []ToolCall {
 
 	return result
 }
+
+func oaiLogprobs(logprobs []provider.Logprob) *Logprobs {
+	if len(logprobs) == 0 {
+		return nil
+	}
+
+	result := &Logprobs{
+		Content: make([]TokenLogprob, 0),
+	}
+
+	for _, l := range logprobs {
+		logprob := oaiTokenLogprob(l)
+		logprob.TopLogprobs = make([]TokenLogprob, 0)
+
+		for _, t := range l.TopLogprobs {
+			logprob.TopLogprobs = append(logprob.TopLogprobs, oaiTokenLogprob(t))
+		}
+
+		result.Content = append(result.Content, logprob)
+	}
+
+	return result
+}
+
+func oaiTokenLogprob(l provider.Logprob) TokenLogprob {
+	var bytes []int
+
+	for _, b := range []byte(l.Token) {
+		bytes = append(bytes, int(b))
+	}
+
+	return TokenLogprob{
+		Token:   l.Token,
+		Logprob: l.Logprob,
+		Bytes:   bytes,
+	}
+}
@@ -0,0 +1,197 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+// optionsCompleter records the options of the last request
+type optionsCompleter struct {
+	options *provider.CompleteOptions
+}
+
+func (c *optionsCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.options = options
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "ok",
+		},
+	}, nil
+}
+
+func postJSON(h http.Handler, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestChatCompletionOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want provider.CompleteOptions
+	}{
+		{
+			"stop string",
+			`"stop":"\n"`,
+			provider.CompleteOptions{Stop: []string{"\n"}},
+		},
+		{
+			"stop array",
+			`"stop":["\n","END"]`,
+			provider.CompleteOptions{Stop: []string{"\n", "END"}},
+		},
+		{
+			"sampling",
+			`"temperature":0.2,"top_p":0.9,"max_tokens":64,"seed":42`,
+			provider.CompleteOptions{Temperature: ptr[float32](0.2), TopP: ptr[float32](0.9), MaxTokens: ptr(64), Seed: ptr(42)},
+		},
+		{
+			"penalties",
+			`"presence_penalty":0.5,"frequency_penalty":-1`,
+			provider.CompleteOptions{PresencePenalty: ptr[float32](0.5), FrequencyPenalty: ptr[float32](-1)},
+		},
+		{
+			"choices",
+			`"n":3,"logprobs":true,"top_logprobs":2`,
+			provider.CompleteOptions{N: ptr(3), Logprobs: true, TopLogprobs: ptr(2)},
+		},
+		{
+			"empty logit bias",
+			`"logit_bias":{}`,
+			provider.CompleteOptions{},
+		},
+		{
+			"json",
+			`"response_format":{"type":"json_object"}`,
+			provider.CompleteOptions{Format: provider.CompletionFormatJSON},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completer := &optionsCompleter{}
+			h := newTestHandler(t, completer)
+
+			rec := postJSON(h, "/chat/completions", `{"model":"fake","messages":[{"role":"user","content":"Hi"}],`+tt.body+`}`)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			if !reflect.DeepEqual(*completer.options, tt.want) {
+				t.Errorf("unexpected options:\n%+v\nwant:\n%+v", *completer.options, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatCompletionInvalidOptions(t *testing.T) {
+	completer := &optionsCompleter{}
+	h := newTestHandler(t, completer)
+
+	for _, body := range []string{
+		`"stop":42`,
+		`"stop":["\n",42]`,
+		`"logit_bias":{"50256":-100}`,
+		`"tool_choice":"sometimes"`,
+		`"tool_choice":{"type":"function"}`,
+		`"n":2,"stream":true`,
+	} {
+		rec := postJSON(h, "/chat/completions", `{"model":"fake","messages":[{"role":"user","content":"Hi"}],`+body+`}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", body, rec.Code, rec.Body.String())
+		}
+	}
+
+	if completer.options != nil {
+		t.Error("expected no completion for invalid options")
+	}
+}
+
+func TestCompletionInvalidOptions(t *testing.T) {
+	h := newTestHandler(t, &optionsCompleter{})
+
+	for _, body := range []string{
+		`"stop":42`,
+		`"logit_bias":{"50256":-100}`,
+	} {
+		if rec := postJSON(h, "/completions", `{"model":"fake","prompt":"Hi",`+body+`}`); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", body, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestToToolChoice(t *testing.T) {
+	tests := []struct {
+		choice string
+		want   *provider.ToolChoice
+	}{
+		{`"none"`, &provider.ToolChoice{Mode: provider.ToolChoiceModeNone}},
+		{`"auto"`, &provider.ToolChoice{Mode: provider.ToolChoiceModeAuto}},
+		{`"required"`, &provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}},
+		{`{"type":"function","function":{"name":"weather"}}`, &provider.ToolChoice{Mode: provider.ToolChoiceModeTool, Name: "weather"}},
+		{`"any"`, nil},
+		{`{"type":"function"}`, nil},
+		{`{"type":"function","function":{"name":""}}`, nil},
+		{`{"type":"code_interpreter"}`, nil},
+	}
+
+	for _, tt := range tests {
+		var choice ToolChoice
+
+		if err := json.Unmarshal([]byte(tt.choice), &choice); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := toToolChoice(&choice)
+
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%s: expected error, got %+v", tt.choice, got)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.choice, err)
+			continue
+		}
+
+		if *got != *tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.choice, tt.want, got)
+		}
+
+		// the choice survives a round trip through its json representation
+		data, _ := json.Marshal(choice)
+
+		if string(data) != tt.choice {
+			t.Errorf("%s: marshalled as %s", tt.choice, data)
+		}
+	}
+
+	if got, err := toToolChoice(nil); got != nil || err != nil {
+		t.Errorf("expected no tool choice, got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,348 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleCompletion(w http.ResponseWriter, r *http.Request) {
+	var req CompletionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
+	completer, err := h.Completer(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	prompts, err := toStrings(req.Prompt)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("prompt must be a string or an array of strings"))
+		return
+	}
+
+	if len(prompts) == 0 {
+		prompts = []string{""}
+	}
+
+	stops, err := toStrings(req.Stop)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("stop must be a string or an array of strings"))
+		return
+	}
+
+	if len(req.LogitBias) > 0 {
+		writeError(w, http.StatusBadRequest, errors.New("logit_bias is not supported"))
+		return
+	}
+
+	if req.Stream && (len(prompts) > 1 || req.N != nil && *req.N > 1) {
+		writeError(w, http.StatusBadRequest, errors.New("multiple choices are not supported when streaming"))
+		return
+	}
+
+	options := &provider.CompleteOptions{
+		Stop: stops,
+
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+
+		Seed: req.Seed,
+
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+
+		N: req.N,
+
+		Suffix: req.Suffix,
+	}
+
+	if req.Logprobs != nil {
+		options.Logprobs = true
+
+		if *req.Logprobs > 0 {
+			options.TopLogprobs = req.Logprobs
+		}
+	}
+
+	id := "cmpl-" + uuid.NewString()
+	created := time.Now().Unix()
+
+	if req.Stream {
+		prompt := prompts[0]
+
+		messages := []provider.Message{
+			{
+				Role:    provider.MessageRoleUser,
+				Content: prompt,
+			},
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		writeChunk := func(result Completion) error {
+			var data bytes.Buffer
+
+			enc := json.NewEncoder(&data)
+			enc.SetEscapeHTML(false)
+			enc.Encode(result)
+
+			event := strings.TrimSpace(data.String())
+
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", event); err != nil {
+				return err
+			}
+
+			w.(http.Flusher).Flush()
+
+			return nil
+		}
+
+		writeChoice := func(choice CompletionChoice) error {
+			return writeChunk(Completion{
+				Object: "text_completion",
+
+				ID: id,
+
+				Model:   req.Model,
+				Created: created,
+
+				Choices: []CompletionChoice{choice},
+			})
+		}
+
+		if req.Echo && prompt != "" {
+			if err := writeChoice(CompletionChoice{Text: prompt}); err != nil {
+				return
+			}
+		}
+
+		var reason *FinishReason
+
+		options.Stream = func(ctx context.Context, completion provider.Completion) error {
+			if completion.Message.Content == "" && completion.Reason == "" && len(completion.Logprobs) == 0 {
+				return nil
+			}
+
+			reason = oaiFinishReason(completion.Reason)
+
+			return writeChoice(CompletionChoice{
+				Text: completion.Message.Content,
+
+				FinishReason: reason,
+
+				Logprobs: oaiCompletionLogprobs(completion.Logprobs, 0),
+			})
+		}
+
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if reason == nil {
+			reason = oaiFinishReason(completion.Reason)
+
+			if reason == nil {
+				reason = &FinishReasonStop
+			}
+
+			if err := writeChoice(CompletionChoice{FinishReason: reason}); err != nil {
+				return
+			}
+		}
+
+		if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+			result := Completion{
+				Object: "text_completion",
+
+				ID: id,
+
+				Model:   req.Model,
+				Created: created,
+
+				Choices: []CompletionChoice{},
+
+				Usage: &Usage{},
+			}
+
+			if completion.Usage != nil {
+				result.Usage = &Usage{
+					PromptTokens:     completion.Usage.InputTokens,
+					CompletionTokens: completion.Usage.OutputTokens,
+					TotalTokens:      completion.Usage.InputTokens + completion.Usage.OutputTokens,
+				}
+			}
+
+			if err := writeChunk(result); err != nil {
+				return
+			}
+		}
+
+		fmt.Fprintf(w, "data: [DONE]\n\n")
+		w.(http.Flusher).Flush()
+
+		return
+	}
+
+	result := Completion{
+		Object: "text_completion",
+
+		ID: id,
+
+		Model:   req.Model,
+		Created: created,
+
+		Choices: []CompletionChoice{},
+	}
+
+	var usage *Usage
+
+	for _, prompt := range prompts {
+		messages := []provider.Message{
+			{
+				Role:    provider.MessageRoleUser,
+				Content: prompt,
+			},
+		}
+
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		choices := completion.Choices
+
+		if len(choices) == 0 {
+			choices = []provider.CompletionChoice{
+				{
+					Reason: completion.Reason,
+
+					Message:  completion.Message,
+					Logprobs: completion.Logprobs,
+				},
+			}
+		}
+
+		for _, c := range choices {
+			text := c.Message.Content
+			offset := 0
+
+			if req.Echo {
+				text = prompt + text
+				offset = len(prompt)
+			}
+
+			result.Choices = append(result.Choices, CompletionChoice{
+				Index: len(result.Choices),
+
+				Text: text,
+
+				FinishReason: oaiFinishReason(c.Reason),
+
+				Logprobs: oaiCompletionLogprobs(c.Logprobs, offset),
+			})
+		}
+
+		if completion.Usage != nil {
+			if usage == nil {
+				usage = &Usage{}
+			}
+
+			usage.PromptTokens += completion.Usage.InputTokens
+			usage.CompletionTokens += completion.Usage.OutputTokens
+			usage.TotalTokens += completion.Usage.InputTokens + completion.Usage.OutputTokens
+		}
+	}
+
+	result.Usage = usage
+
+	writeJson(w, result)
+}
+
+func toStrings(val any) ([]string, error) {
+	switch v := val.(type) {
+	case nil:
+		return nil, nil
+
+	case string:
+		return []string{v}, nil
+
+	case []any:
+		var result []string
+
+		for _, item := range v {
+			s, ok := item.(string)
+
+			if !ok {
+				return nil, errors.New("invalid value")
+			}
+
+			result = append(result, s)
+		}
+
+		return result, nil
+	}
+
+	return nil, errors.New("invalid value")
+}
+
+func oaiCompletionLogprobs(logprobs []provider.Logprob, offset int) *CompletionLogprobs {
+	if len(logprobs) == 0 {
+		return nil
+	}
+
+	result := &CompletionLogprobs{
+		Tokens:        make([]string, 0),
+		TokenLogprobs: make([]float64, 0),
+
+		TopLogprobs: make([]map[string]float64, 0),
+		TextOffset:  make([]int, 0),
+	}
+
+	for _, l := range logprobs {
+		top := map[string]float64{}
+
+		for _, t := range l.TopLogprobs {
+			top[t.Token] = t.Logprob
+		}
+
+		result.Tokens = append(result.Tokens, l.Token)
+		result.TokenLogprobs = append(result.TokenLogprobs, l.Logprob)
+
+		result.TopLogprobs = append(result.TopLogprobs, top)
+		result.TextOffset = append(result.TextOffset, offset)
+
+		offset += len(l.Token)
+	}
+
+	return result
+}
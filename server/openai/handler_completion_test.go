@@ -0,0 +1,66 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/fim"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+// fillCompleter answers the middle of fill-in-the-middle prompts wrapped in a code block
+type fillCompleter struct {
+	prompts []string
+}
+
+func (c *fillCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.prompts = append(c.prompts, messages[len(messages)-1].Content)
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "```go\nreturn a + b\n```",
+		},
+	}, nil
+}
+
+func TestCompletionSuffix(t *testing.T) {
+	completer := &fillCompleter{}
+	h := newTestHandler(t, fim.NewCompleter(completer))
+
+	var result Completion
+	doRequest(t, h, http.MethodPost, "/completions", "application/json", []byte(`{"model":"fake","prompt":"func add(a, b int) int {\n\t","suffix":"\n}","echo":true}`), &result)
+
+	if len(result.Choices) != 1 || result.Choices[0].Text != "func add(a, b int) int {\n\treturn a + b" {
+		t.Errorf("unexpected choices: %+v", result.Choices)
+	}
+
+	if len(completer.prompts) != 1 || !strings.HasSuffix(completer.prompts[0], "func add(a, b int) int {\n\t<FILL>\n}") {
+		t.Errorf("expected fill-in-the-middle prompt, got %q", completer.prompts)
+	}
+}
+
+func TestCompletionSuffixStream(t *testing.T) {
+	h := newTestHandler(t, fim.NewCompleter(&fillCompleter{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/completions", strings.NewReader(`{"model":"fake","prompt":"func add(a, b int) int {\n\t","suffix":"\n}","stream":true}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+
+	if !strings.Contains(body, `"text":"return a + b"`) || !strings.HasSuffix(body, "data: [DONE]\n\n") {
+		t.Errorf("unexpected stream: %s", body)
+	}
+
+	if strings.Contains(body, "```") {
+		t.Errorf("expected trimmed stream, got %s", body)
+	}
+}
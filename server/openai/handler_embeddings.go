@@ -1,9 +1,15 @@
 package openai
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
 )
 
 func (h *Handler) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
@@ -14,6 +20,11 @@ func (h *Handler) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
 	embedder, err := h.Embedder(req.Model)
 
 	if err != nil {
@@ -21,13 +32,11 @@ func (h *Handler) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var inputs []string
+	inputs, err := toStrings(req.Input)
 
-	switch v := req.Input.(type) {
-	case string:
-		inputs = []string{v}
-	case []string:
-		inputs = v
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("input must be a string or an array of strings"))
+		return
 	}
 
 	if len(inputs) == 0 {
@@ -35,42 +44,59 @@ func (h *Handler) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.EncodingFormat != "" && req.EncodingFormat != EncodingFormatFloat && req.EncodingFormat != EncodingFormatBase64 {
+		writeError(w, http.StatusBadRequest, errors.New("unsupported encoding format: "+string(req.EncodingFormat)))
+		return
+	}
+
+	options := &provider.EmbedOptions{
+		Dimensions: req.Dimensions,
+	}
+
+	embedding, err := embedder.Embed(r.Context(), inputs, options)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	result := &EmbeddingList{
 		Object: "list",
 
 		Model: req.Model,
 	}
 
-	inputTokens := 0
-	outputToken := 0
-
-	for i, input := range inputs {
-		embedding, err := embedder.Embed(r.Context(), input)
+	for i, e := range embedding.Embeddings {
+		var data any = e
 
-		if err != nil {
-			writeError(w, http.StatusBadRequest, err)
-			return
+		if req.EncodingFormat == EncodingFormatBase64 {
+			data = base64Embedding(e)
 		}
 
 		result.Data = append(result.Data, Embedding{
 			Object: "embedding",
 
 			Index:     i,
-			Embedding: embedding.Data,
+			Embedding: data,
 		})
-
-		if embedding.Usage != nil {
-			inputTokens += embedding.Usage.InputTokens
-			outputToken += embedding.Usage.OutputTokens
-		}
 	}
 
-	if inputTokens > 0 || outputToken > 0 {
+	if embedding.Usage != nil {
 		result.Usage = &Usage{
-			PromptTokens: inputTokens,
-			TotalTokens:  inputTokens + outputToken,
+			PromptTokens: embedding.Usage.InputTokens,
+			TotalTokens:  embedding.Usage.InputTokens + embedding.Usage.OutputTokens,
 		}
 	}
 
 	writeJson(w, result)
 }
+
+func base64Embedding(embedding []float32) string {
+	data := make([]byte, len(embedding)*4)
+
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+	}
+
+	return base64.StdEncoding.EncodeToString(data)
+}
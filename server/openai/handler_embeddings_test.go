@@ -0,0 +1,89 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+type staticEmbedder struct {
+	embedding []float32
+}
+
+func (e *staticEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, e.embedding)
+	}
+
+	return result, nil
+}
+
+func decodeEmbedding(t *testing.T, s string) []float32 {
+	data, err := base64.StdEncoding.DecodeString(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data)%4 != 0 {
+		t.Fatalf("unexpected length %d", len(data))
+	}
+
+	result := make([]float32, len(data)/4)
+
+	for i := range result {
+		result[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+
+	return result
+}
+
+func TestBase64Embedding(t *testing.T) {
+	embedding := []float32{0, 1, -1.5, 0.1, math.MaxFloat32, math.SmallestNonzeroFloat32}
+
+	got := decodeEmbedding(t, base64Embedding(embedding))
+
+	if len(got) != len(embedding) {
+		t.Fatalf("expected %d values, got %d", len(embedding), len(got))
+	}
+
+	for i := range embedding {
+		if got[i] != embedding[i] {
+			t.Errorf("value %d: expected %v, got %v", i, embedding[i], got[i])
+		}
+	}
+
+	// 1.0 is 0x3f800000, encoded least significant byte first
+	if s := base64Embedding([]float32{1}); s != base64.StdEncoding.EncodeToString([]byte{0x00, 0x00, 0x80, 0x3f}) {
+		t.Errorf("expected little-endian encoding, got %q", s)
+	}
+}
+
+func TestEmbeddingsBase64(t *testing.T) {
+	h := newTestHandler(t, &blockingCompleter{})
+	h.RegisterEmbedder("embed", &staticEmbedder{embedding: []float32{0.25, -2}})
+
+	var result struct {
+		Data []struct {
+			Index     int    `json:"index"`
+			Embedding string `json:"embedding"`
+		} `json:"data"`
+	}
+
+	doRequest(t, h, http.MethodPost, "/embeddings", "application/json", []byte(`{"model":"embed","input":["a","b"],"encoding_format":"base64"}`), &result)
+
+	if len(result.Data) != 2 || result.Data[1].Index != 1 {
+		t.Fatalf("unexpected data: %+v", result.Data)
+	}
+
+	if got := decodeEmbedding(t, result.Data[0].Embedding); len(got) != 2 || got[0] != 0.25 || got[1] != -2 {
+		t.Errorf("unexpected embedding: %v", got)
+	}
+}
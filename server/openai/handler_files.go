@@ -0,0 +1,191 @@
+package openai
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/batch"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleFileCreate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	purpose := r.FormValue("purpose")
+
+	if purpose == "" {
+		writeError(w, http.StatusBadRequest, errors.New("purpose is required"))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	defer file.Close()
+
+	f := batch.File{
+		ID: "file-" + uuid.NewString(),
+
+		Name:    header.Filename,
+		Purpose: purpose,
+
+		CreatedAt: time.Now(),
+	}
+
+	if p, ok := authorizer.PrincipalFromContext(r.Context()); ok {
+		f.Owner = p.ID
+	}
+
+	result, err := h.Batches().CreateFile(r.Context(), f, file)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, oaiFile(*result))
+}
+
+func (h *Handler) handleFiles(w http.ResponseWriter, r *http.Request) {
+	files, err := h.Batches().ListFiles(r.Context())
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].CreatedAt.After(files[j].CreatedAt) })
+
+	purpose := r.URL.Query().Get("purpose")
+
+	result := FileList{
+		Object: "list",
+
+		Files: make([]File, 0),
+	}
+
+	for _, f := range files {
+		if !isOwner(r, f.Owner) {
+			continue
+		}
+
+		if purpose != "" && f.Purpose != purpose {
+			continue
+		}
+
+		result.Files = append(result.Files, oaiFile(f))
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleFile(w http.ResponseWriter, r *http.Request) {
+	f, err := h.file(r)
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	writeJson(w, oaiFile(*f))
+}
+
+func (h *Handler) handleFileContent(w http.ResponseWriter, r *http.Request) {
+	f, err := h.file(r)
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	content, err := h.Batches().OpenFile(r.Context(), f.ID)
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	defer content.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	io.Copy(w, content)
+}
+
+func (h *Handler) handleFileDelete(w http.ResponseWriter, r *http.Request) {
+	f, err := h.file(r)
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	if err := h.Batches().DeleteFile(r.Context(), f.ID); err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	writeJson(w, FileDeleted{
+		Object: "file",
+
+		ID:      f.ID,
+		Deleted: true,
+	})
+}
+
+func (h *Handler) file(r *http.Request) (*batch.File, error) {
+	f, err := h.Batches().GetFile(r.Context(), r.PathValue("file"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOwner(r, f.Owner) {
+		return nil, batch.ErrNotFound
+	}
+
+	return f, nil
+}
+
+func isOwner(r *http.Request, owner string) bool {
+	p, ok := authorizer.PrincipalFromContext(r.Context())
+
+	if !ok || owner == "" {
+		return true
+	}
+
+	return p.ID == owner
+}
+
+func writeBatchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, batch.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+func oaiFile(f batch.File) File {
+	return File{
+		Object: "file",
+
+		ID:        f.ID,
+		Bytes:     f.Size,
+		CreatedAt: f.CreatedAt.Unix(),
+
+		Filename: f.Name,
+		Purpose:  f.Purpose,
+	}
+}
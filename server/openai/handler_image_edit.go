@@ -0,0 +1,111 @@
+package openai
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+func (h *Handler) handleImageEdit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	model := r.FormValue("model")
+
+	if !authorizer.AllowModel(r.Context(), model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+model))
+		return
+	}
+
+	renderer, err := h.Renderer(model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	prompt := r.FormValue("prompt")
+
+	if prompt == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing prompt"))
+		return
+	}
+
+	options, err := toRenderOptions(r)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if options.Image == nil {
+		writeError(w, http.StatusBadRequest, errors.New("missing image"))
+		return
+	}
+
+	mask, err := toFormFile(r, "mask")
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options.Mask = mask
+
+	rendering, err := renderer.Render(r.Context(), prompt, options)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeImages(w, rendering, r.FormValue("response_format"))
+}
+
+func toRenderOptions(r *http.Request) (*provider.RenderOptions, error) {
+	image, err := toFormFile(r, "image")
+
+	if err != nil {
+		return nil, err
+	}
+
+	options := &provider.RenderOptions{
+		Size: r.FormValue("size"),
+
+		Image: image,
+	}
+
+	if val := r.FormValue("n"); val != "" {
+		n, err := strconv.Atoi(val)
+
+		if err != nil {
+			return nil, errors.New("invalid n: " + val)
+		}
+
+		options.N = &n
+	}
+
+	return options, nil
+}
+
+func toFormFile(r *http.Request, name string) (*provider.File, error) {
+	file, header, err := r.FormFile(name)
+
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &provider.File{
+		Name:    header.Filename,
+		Content: file,
+	}, nil
+}
@@ -0,0 +1,173 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+// recordingRenderer records the prompt and files of the last request and returns the image as rendering
+type recordingRenderer struct {
+	input string
+
+	image string
+	mask  string
+
+	n *int
+}
+
+func (r *recordingRenderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
+	r.input = input
+	r.n = options.N
+
+	if options.Image != nil {
+		data, _ := io.ReadAll(options.Image.Content)
+		r.image = string(data)
+	}
+
+	if options.Mask != nil {
+		data, _ := io.ReadAll(options.Mask.Content)
+		r.mask = string(data)
+	}
+
+	return &provider.Rendering{
+		Images: []provider.Image{
+			{
+				Name:    "result.png",
+				Content: io.NopCloser(bytes.NewReader([]byte("rendered " + r.image))),
+			},
+		},
+	}, nil
+}
+
+func newImageTestHandler(t *testing.T) (*Handler, *recordingRenderer) {
+	renderer := &recordingRenderer{}
+
+	h := newTestHandler(t, &blockingCompleter{})
+	h.RegisterRenderer("painter", renderer)
+
+	return h, renderer
+}
+
+func postImageForm(h http.Handler, path string, fields map[string]string, files map[string]string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		mw.WriteField(name, value)
+	}
+
+	for name, content := range files {
+		fw, _ := mw.CreateFormFile(name, name+".png")
+		fw.Write([]byte(content))
+	}
+
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestImageEdit(t *testing.T) {
+	h, renderer := newImageTestHandler(t)
+
+	rec := postImageForm(h, "/images/edits", map[string]string{
+		"model":           "painter",
+		"prompt":          "add a hat",
+		"n":               "2",
+		"response_format": "b64_json",
+	}, map[string]string{
+		"image": "cat",
+		"mask":  "head",
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if renderer.input != "add a hat" || renderer.image != "cat" || renderer.mask != "head" {
+		t.Errorf("unexpected render request: %+v", renderer)
+	}
+
+	if renderer.n == nil || *renderer.n != 2 {
+		t.Errorf("expected n of 2, got %v", renderer.n)
+	}
+
+	var result ImageList
+	decodeBody(t, rec, &result)
+
+	if len(result.Images) != 1 || result.Images[0].B64JSON != base64.StdEncoding.EncodeToString([]byte("rendered cat")) {
+		t.Errorf("unexpected images: %+v", result.Images)
+	}
+}
+
+func TestImageEditValidation(t *testing.T) {
+	h, _ := newImageTestHandler(t)
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+		files  map[string]string
+	}{
+		{"missing prompt", map[string]string{"model": "painter"}, map[string]string{"image": "cat"}},
+		{"missing image", map[string]string{"model": "painter", "prompt": "add a hat"}, nil},
+		{"invalid n", map[string]string{"model": "painter", "prompt": "add a hat", "n": "two"}, map[string]string{"image": "cat"}},
+		{"unknown model", map[string]string{"model": "other", "prompt": "add a hat"}, map[string]string{"image": "cat"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := postImageForm(h, "/images/edits", test.fields, test.files)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestImageVariation(t *testing.T) {
+	h, renderer := newImageTestHandler(t)
+
+	rec := postImageForm(h, "/images/variations", map[string]string{
+		"model":  "painter",
+		"prompt": "ignored",
+	}, map[string]string{
+		"image": "cat",
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// variations are requested without a prompt
+	if renderer.input != "" || renderer.image != "cat" {
+		t.Errorf("unexpected render request: %+v", renderer)
+	}
+
+	var result ImageList
+	decodeBody(t, rec, &result)
+
+	if len(result.Images) != 1 || result.Images[0].URL != "data:image/png;base64,"+base64.StdEncoding.EncodeToString([]byte("rendered cat")) {
+		t.Errorf("unexpected images: %+v", result.Images)
+	}
+
+	rec = postImageForm(h, "/images/variations", map[string]string{"model": "painter"}, nil)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without image, got %d", rec.Code)
+	}
+}
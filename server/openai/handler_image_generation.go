@@ -3,11 +3,14 @@ package openai
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 	"mime"
 	"net/http"
 	"path"
+	"time"
 
+	"github.com/adrianliechti/llama/pkg/authorizer"
 	"github.com/adrianliechti/llama/pkg/provider"
 )
 
@@ -19,6 +22,11 @@ func (h *Handler) handleImageGeneration(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !authorizer.AllowModel(r.Context(), req.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+req.Model))
+		return
+	}
+
 	renderer, err := h.Renderer(req.Model)
 
 	if err != nil {
@@ -26,43 +34,62 @@ func (h *Handler) handleImageGeneration(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	options := &provider.RenderOptions{}
+	options := &provider.RenderOptions{
+		Size:    req.Size,
+		Quality: req.Quality,
+		Style:   req.Style,
+
+		N: req.N,
+	}
 
-	image, err := renderer.Render(r.Context(), req.Prompt, options)
+	rendering, err := renderer.Render(r.Context(), req.Prompt, options)
 
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	data, err := io.ReadAll(image.Content)
+	writeImages(w, rendering, req.ResponseFormat)
+}
 
-	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
-		return
+func writeImages(w http.ResponseWriter, rendering *provider.Rendering, format string) {
+	defer func() {
+		for _, image := range rendering.Images {
+			image.Content.Close()
+		}
+	}()
+
+	result := ImageList{
+		Created: time.Now().Unix(),
+
+		Images: []Image{},
 	}
 
-	result := ImageList{}
+	for _, image := range rendering.Images {
+		data, err := io.ReadAll(image.Content)
 
-	if req.ResponseFormat == "b64_json" {
-		result.Images = []Image{
-			{
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if format == "b64_json" {
+			result.Images = append(result.Images, Image{
 				B64JSON: base64.StdEncoding.EncodeToString(data),
-			},
+			})
+
+			continue
 		}
 
-	} else {
 		mime := mime.TypeByExtension(path.Ext(image.Name))
 
 		if mime == "" {
 			mime = "image/png"
 		}
 
-		result.Images = []Image{
-			{
-				URL: "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data),
-			},
-		}
+		result.Images = append(result.Images, Image{
+			URL: "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data),
+		})
 	}
 
 	writeJson(w, result)
@@ -0,0 +1,50 @@
+package openai
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+)
+
+func (h *Handler) handleImageVariation(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	model := r.FormValue("model")
+
+	if !authorizer.AllowModel(r.Context(), model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+model))
+		return
+	}
+
+	renderer, err := h.Renderer(model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options, err := toRenderOptions(r)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if options.Image == nil {
+		writeError(w, http.StatusBadRequest, errors.New("missing image"))
+		return
+	}
+
+	rendering, err := renderer.Render(r.Context(), "", options)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeImages(w, rendering, r.FormValue("response_format"))
+}
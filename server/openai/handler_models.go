@@ -1,8 +1,11 @@
 package openai
 
 import (
+	"errors"
 	"net/http"
 	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
 )
 
 func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
@@ -11,6 +14,10 @@ func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, m := range h.Models() {
+		if !authorizer.AllowModel(r.Context(), m.ID) {
+			continue
+		}
+
 		result.Models = append(result.Models, Model{
 			Object: "model",
 
@@ -24,7 +31,14 @@ func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleModel(w http.ResponseWriter, r *http.Request) {
-	model, err := h.Model(r.PathValue("id"))
+	id := r.PathValue("id")
+
+	if !authorizer.AllowModel(r.Context(), id) {
+		writeError(w, http.StatusNotFound, errors.New("model not found: "+id))
+		return
+	}
+
+	model, err := h.Model(id)
 
 	if err != nil {
 		writeError(w, http.StatusNotFound, err)
@@ -0,0 +1,48 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/quota"
+)
+
+func TestWriteErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"plain error", errors.New("invalid messages"), http.StatusBadRequest},
+		{"unsupported option", provider.UnsupportedOption("test", "logprobs"), http.StatusBadRequest},
+		{"upstream rate limit", &provider.Error{StatusCode: http.StatusTooManyRequests, Message: "rate limited"}, http.StatusTooManyRequests},
+		{"upstream failure", &provider.Error{StatusCode: http.StatusBadGateway, Message: "bad gateway"}, http.StatusBadGateway},
+		{"upstream credentials", &provider.Error{StatusCode: http.StatusUnauthorized, Message: "invalid api key"}, http.StatusBadGateway},
+		{"no healthy backend", &provider.Error{StatusCode: http.StatusServiceUnavailable, Message: "no healthy backend available"}, http.StatusServiceUnavailable},
+		{"quota", &quota.Error{Type: quota.ErrorTypeTokens, Message: "quota exceeded", RetryAfter: time.Second}, http.StatusTooManyRequests},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, http.StatusBadRequest, test.err)
+
+			if rec.Code != test.code {
+				t.Errorf("expected status %d, got %d", test.code, rec.Code)
+			}
+		})
+	}
+}
+
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, result any) {
+	t.Helper()
+
+	if err := json.Unmarshal(rec.Body.Bytes(), result); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+}
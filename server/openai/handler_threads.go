@@ -0,0 +1,392 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/thread"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleThreadCreate(w http.ResponseWriter, r *http.Request) {
+	var req ThreadCreateRequest
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	var messages []thread.Message
+
+	for _, m := range req.Messages {
+		message, err := h.toThreadMessage(r, m)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		messages = append(messages, *message)
+	}
+
+	t := thread.Thread{
+		ID: "thread_" + uuid.NewString(),
+
+		Metadata: req.Metadata,
+
+		CreatedAt: time.Now(),
+	}
+
+	if p, ok := authorizer.PrincipalFromContext(r.Context()); ok {
+		t.Owner = p.ID
+	}
+
+	store := h.Threads()
+
+	if err := store.CreateThread(r.Context(), t); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, m := range messages {
+		m.Thread = t.ID
+
+		if err := store.CreateMessage(r.Context(), m); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	writeJson(w, oaiThread(t))
+}
+
+func (h *Handler) handleThread(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	writeJson(w, oaiThread(*t))
+}
+
+func (h *Handler) handleThreadDelete(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	if err := h.Threads().DeleteThread(r.Context(), t.ID); err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	writeJson(w, ThreadDeleted{
+		Object: "thread.deleted",
+
+		ID:      t.ID,
+		Deleted: true,
+	})
+}
+
+func (h *Handler) handleThreadMessageCreate(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	var req ThreadMessageCreateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	message, err := h.toThreadMessage(r, req)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	message.Thread = t.ID
+
+	store := h.Threads()
+
+	h.threadMu.Lock()
+	defer h.threadMu.Unlock()
+
+	// messages added during a run would interleave with its answer
+	if err := checkThreadIdle(r.Context(), store, t.ID); err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	if err := store.CreateMessage(r.Context(), *message); err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	writeJson(w, oaiThreadMessage(*message))
+}
+
+func (h *Handler) handleThreadMessages(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	history, err := h.Threads().ListMessages(r.Context(), t.ID)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	var messages []thread.Message
+
+	// tool calls and their outputs are part of runs, not of the conversation
+	for _, m := range history {
+		if m.Role == provider.MessageRoleTool || len(m.ToolCalls) > 0 {
+			continue
+		}
+
+		messages = append(messages, m)
+	}
+
+	messages, hasMore := paginate(r, messages, func(m thread.Message) string { return m.ID })
+
+	result := ThreadMessageList{
+		Object: "list",
+
+		Messages: make([]ThreadMessage, 0),
+
+		HasMore: hasMore,
+	}
+
+	for _, m := range messages {
+		result.Messages = append(result.Messages, oaiThreadMessage(m))
+	}
+
+	if len(messages) > 0 {
+		result.FirstID = messages[0].ID
+		result.LastID = messages[len(messages)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) thread(r *http.Request) (*thread.Thread, error) {
+	t, err := h.Threads().GetThread(r.Context(), r.PathValue("thread"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p, ok := authorizer.PrincipalFromContext(r.Context()); ok && t.Owner != "" && t.Owner != p.ID {
+		return nil, thread.ErrNotFound
+	}
+
+	return t, nil
+}
+
+func writeThreadError(w http.ResponseWriter, err error) {
+	if errors.Is(err, thread.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if errors.Is(err, errRunActive) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+func paginate[T any](r *http.Request, items []T, id func(T) string) ([]T, bool) {
+	items = slices.Clone(items)
+
+	if r.URL.Query().Get("order") != "asc" {
+		slices.Reverse(items)
+	}
+
+	if after := r.URL.Query().Get("after"); after != "" {
+		for i, item := range items {
+			if id(item) == after {
+				items = items[i+1:]
+				break
+			}
+		}
+	}
+
+	limit := 20
+
+	if val, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && val > 0 && val <= 100 {
+		limit = val
+	}
+
+	if len(items) > limit {
+		return items[:limit], true
+	}
+
+	return items, false
+}
+
+func (h *Handler) toThreadMessage(r *http.Request, m ThreadMessageCreateRequest) (*thread.Message, error) {
+	message, err := toThreadMessage(m)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range message.Files {
+		f, err := h.Batches().GetFile(r.Context(), id)
+
+		if err != nil || !isOwner(r, f.Owner) {
+			return nil, errors.New("file not found: " + id)
+		}
+	}
+
+	return message, nil
+}
+
+func toThreadMessage(m ThreadMessageCreateRequest) (*thread.Message, error) {
+	role := toMessageRole(m.Role)
+
+	if role != provider.MessageRoleUser && role != provider.MessageRoleAssistant {
+		return nil, fmt.Errorf("invalid message role: %s", m.Role)
+	}
+
+	var content string
+
+	switch v := m.Content.(type) {
+	case string:
+		content = v
+
+	case []any:
+		for _, item := range v {
+			part, ok := item.(map[string]any)
+
+			if !ok || part["type"] != "text" {
+				return nil, errors.New("unsupported message content")
+			}
+
+			switch text := part["text"].(type) {
+			case string:
+				content += text
+
+			case map[string]any:
+				if value, ok := text["value"].(string); ok {
+					content += value
+				}
+			}
+		}
+
+	default:
+		return nil, errors.New("invalid message content")
+	}
+
+	var files []string
+
+	for _, a := range m.Attachments {
+		if a.FileID == "" {
+			return nil, errors.New("attachment file_id is required")
+		}
+
+		files = append(files, a.FileID)
+	}
+
+	return &thread.Message{
+		ID: "msg_" + uuid.NewString(),
+
+		Role:    role,
+		Content: content,
+
+		Files: files,
+
+		Metadata: m.Metadata,
+
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func oaiThread(t thread.Thread) Thread {
+	metadata := t.Metadata
+
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	return Thread{
+		Object: "thread",
+
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt.Unix(),
+
+		Metadata: metadata,
+	}
+}
+
+func oaiThreadMessage(m thread.Message) ThreadMessage {
+	metadata := m.Metadata
+
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	result := ThreadMessage{
+		Object: "thread.message",
+
+		ID:        m.ID,
+		CreatedAt: m.CreatedAt.Unix(),
+
+		ThreadID: m.Thread,
+
+		Role: oaiMessageRole(m.Role),
+
+		Content: []ThreadMessageContent{
+			{
+				Type: "text",
+
+				Text: &ThreadMessageText{
+					Value:       m.Content,
+					Annotations: []any{},
+				},
+			},
+		},
+
+		Attachments: []ThreadMessageAttachment{},
+
+		Metadata: metadata,
+	}
+
+	for _, id := range m.Files {
+		result.Attachments = append(result.Attachments, ThreadMessageAttachment{
+			FileID: id,
+		})
+	}
+
+	if m.Run != "" {
+		result.RunID = &m.Run
+	}
+
+	return result
+}
@@ -0,0 +1,639 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+	"github.com/adrianliechti/llama/pkg/thread"
+
+	"github.com/google/uuid"
+)
+
+var errRunActive = errors.New("thread already has an active run")
+
+func (h *Handler) handleThreadRunCreate(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	var req RunCreateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	model := req.Model
+
+	if model == "" {
+		model = req.AssistantID
+	}
+
+	if !authorizer.AllowModel(r.Context(), model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+model))
+		return
+	}
+
+	completer, err := h.Completer(model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tools, err := toTools(req.Tools)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	toolChoice, err := toToolChoice(req.ToolChoice)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var messages []thread.Message
+
+	for _, m := range req.AdditionalMessages {
+		message, err := h.toThreadMessage(r, m)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		message.Thread = t.ID
+		messages = append(messages, *message)
+	}
+
+	instructions := req.Instructions
+
+	if req.AdditionalInstructions != "" {
+		if instructions != "" {
+			instructions += "\n\n"
+		}
+
+		instructions += req.AdditionalInstructions
+	}
+
+	run := thread.Run{
+		ID:     "run_" + uuid.NewString(),
+		Thread: t.ID,
+
+		Model:        model,
+		Instructions: instructions,
+
+		Tools:      tools,
+		ToolChoice: toolChoice,
+
+		Temperature: req.Temperature,
+
+		Status: thread.RunStatusQueued,
+
+		CreatedAt: time.Now(),
+	}
+
+	store := h.Threads()
+
+	h.threadMu.Lock()
+	defer h.threadMu.Unlock()
+
+	if err := checkThreadIdle(r.Context(), store, t.ID); err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	for _, m := range messages {
+		if err := store.CreateMessage(r.Context(), m); err != nil {
+			writeThreadError(w, err)
+			return
+		}
+	}
+
+	if err := store.CreateRun(r.Context(), run); err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	h.startRun(r.Context(), store, run, completer)
+
+	writeJson(w, oaiRun(run))
+}
+
+func (h *Handler) handleThreadRuns(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	runs, err := h.Threads().ListRuns(r.Context(), t.ID)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	runs, hasMore := paginate(r, runs, func(r thread.Run) string { return r.ID })
+
+	result := RunList{
+		Object: "list",
+
+		Runs: make([]Run, 0),
+
+		HasMore: hasMore,
+	}
+
+	for _, r := range runs {
+		result.Runs = append(result.Runs, oaiRun(r))
+	}
+
+	if len(runs) > 0 {
+		result.FirstID = runs[0].ID
+		result.LastID = runs[len(runs)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleThreadRun(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	run, err := h.Threads().GetRun(r.Context(), t.ID, r.PathValue("run"))
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	writeJson(w, oaiRun(*run))
+}
+
+func (h *Handler) handleThreadRunCancel(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	store := h.Threads()
+
+	h.threadMu.Lock()
+	defer h.threadMu.Unlock()
+
+	run, err := store.GetRun(r.Context(), t.ID, r.PathValue("run"))
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	if cancel, ok := h.runs.Load(run.ID); ok {
+		cancel.(context.CancelFunc)()
+
+		result := oaiRun(*run)
+		result.Status = RunStatusCancelling
+
+		writeJson(w, result)
+		return
+	}
+
+	// runs waiting for tool outputs, or left over from a restart, are not executing
+	if run.Active() {
+		completed := time.Now()
+
+		run.Status = thread.RunStatusCancelled
+		run.CompletedAt = &completed
+		run.ToolCalls = nil
+
+		if err := store.UpdateRun(r.Context(), *run); err != nil {
+			writeThreadError(w, err)
+			return
+		}
+	}
+
+	writeJson(w, oaiRun(*run))
+}
+
+func (h *Handler) handleThreadRunSubmitToolOutputs(w http.ResponseWriter, r *http.Request) {
+	t, err := h.thread(r)
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	var req RunSubmitToolOutputsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	store := h.Threads()
+
+	h.threadMu.Lock()
+	defer h.threadMu.Unlock()
+
+	run, err := store.GetRun(r.Context(), t.ID, r.PathValue("run"))
+
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	if run.Status != thread.RunStatusRequiresAction {
+		writeError(w, http.StatusBadRequest, errors.New("run does not require tool outputs: "+string(run.Status)))
+		return
+	}
+
+	if !authorizer.AllowModel(r.Context(), run.Model) {
+		writeError(w, http.StatusForbidden, errors.New("model not allowed: "+run.Model))
+		return
+	}
+
+	completer, err := h.Completer(run.Model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	outputs := make(map[string]string)
+
+	for _, o := range req.ToolOutputs {
+		outputs[o.ToolCallID] = o.Output
+	}
+
+	if len(outputs) != len(run.ToolCalls) {
+		writeError(w, http.StatusBadRequest, errors.New("tool outputs must answer every tool call of the run"))
+		return
+	}
+
+	var messages []thread.Message
+
+	for _, c := range run.ToolCalls {
+		output, ok := outputs[c.ID]
+
+		if !ok {
+			writeError(w, http.StatusBadRequest, errors.New("missing tool output: "+c.ID))
+			return
+		}
+
+		messages = append(messages, thread.Message{
+			ID:     "msg_" + uuid.NewString(),
+			Thread: run.Thread,
+			Run:    run.ID,
+
+			Role:    provider.MessageRoleTool,
+			Content: output,
+
+			Tool: c.ID,
+
+			CreatedAt: time.Now(),
+		})
+	}
+
+	for _, m := range messages {
+		if err := store.CreateMessage(r.Context(), m); err != nil {
+			writeThreadError(w, err)
+			return
+		}
+	}
+
+	run.Status = thread.RunStatusQueued
+	run.ToolCalls = nil
+
+	if err := store.UpdateRun(r.Context(), *run); err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	h.startRun(r.Context(), store, *run, completer)
+
+	writeJson(w, oaiRun(*run))
+}
+
+// startRun executes a run in the background, the caller has to hold threadMu
+func (h *Handler) startRun(ctx context.Context, store thread.Store, run thread.Run, completer provider.Completer) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	h.runs.Store(run.ID, cancel)
+
+	go func() {
+		defer cancel()
+
+		h.executeRun(ctx, store, run, completer)
+	}()
+}
+
+func (h *Handler) executeRun(ctx context.Context, store thread.Store, run thread.Run, completer provider.Completer) {
+	storeCtx := context.WithoutCancel(ctx)
+
+	if run.StartedAt == nil {
+		started := time.Now()
+		run.StartedAt = &started
+	}
+
+	run.Status = thread.RunStatusInProgress
+
+	store.UpdateRun(storeCtx, run)
+
+	var completion *provider.Completion
+
+	messages, err := h.runMessages(storeCtx, store, run)
+
+	if err == nil {
+		completion, err = completer.Complete(ctx, messages, &provider.CompleteOptions{
+			Temperature: run.Temperature,
+
+			Tools:      run.Tools,
+			ToolChoice: run.ToolChoice,
+		})
+	}
+
+	now := time.Now()
+
+	switch {
+	case ctx.Err() != nil:
+		run.Status = thread.RunStatusCancelled
+		run.CompletedAt = &now
+
+	case err != nil:
+		run.Status = thread.RunStatusFailed
+		run.Error = err.Error()
+		run.CompletedAt = &now
+
+	default:
+		run.Usage = addUsage(run.Usage, completion.Usage)
+
+		toolCalls := completion.Message.ToolCalls
+
+		for i := range toolCalls {
+			if toolCalls[i].ID == "" {
+				toolCalls[i].ID = "call_" + uuid.NewString()
+			}
+		}
+
+		message := thread.Message{
+			ID:     "msg_" + uuid.NewString(),
+			Thread: run.Thread,
+			Run:    run.ID,
+
+			Role:    provider.MessageRoleAssistant,
+			Content: completion.Message.Content,
+
+			ToolCalls: toolCalls,
+
+			CreatedAt: now,
+		}
+
+		if err := store.CreateMessage(storeCtx, message); err != nil {
+			run.Status = thread.RunStatusFailed
+			run.Error = err.Error()
+			run.CompletedAt = &now
+			break
+		}
+
+		if len(toolCalls) > 0 {
+			run.Status = thread.RunStatusRequiresAction
+			run.ToolCalls = toolCalls
+			break
+		}
+
+		run.Status = thread.RunStatusCompleted
+		run.CompletedAt = &now
+	}
+
+	h.threadMu.Lock()
+	defer h.threadMu.Unlock()
+
+	h.runs.Delete(run.ID)
+
+	store.UpdateRun(storeCtx, run)
+}
+
+// runMessages returns the history of the thread of a run with its instructions and attached files
+func (h *Handler) runMessages(ctx context.Context, store thread.Store, run thread.Run) ([]provider.Message, error) {
+	history, err := store.ListMessages(ctx, run.Thread)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []provider.Message
+
+	if run.Instructions != "" {
+		messages = append(messages, provider.Message{
+			Role:    provider.MessageRoleSystem,
+			Content: run.Instructions,
+		})
+	}
+
+	for _, m := range history {
+		message := provider.Message{
+			Role:    m.Role,
+			Content: m.Content,
+
+			Tool:      m.Tool,
+			ToolCalls: m.ToolCalls,
+		}
+
+		for _, id := range m.Files {
+			file, err := h.openFile(ctx, id)
+
+			if err != nil {
+				return nil, err
+			}
+
+			message.Files = append(message.Files, *file)
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (h *Handler) openFile(ctx context.Context, id string) (*provider.File, error) {
+	f, err := h.Batches().GetFile(ctx, id)
+
+	if err != nil {
+		return nil, errors.New("file not found: " + id)
+	}
+
+	content, err := h.Batches().OpenFile(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.File{
+		ID: f.ID,
+
+		Name:    f.Name,
+		Content: bytes.NewReader(data),
+	}, nil
+}
+
+// checkThreadIdle fails if a run of the thread is still active, the caller has to hold threadMu
+func checkThreadIdle(ctx context.Context, store thread.Store, id string) error {
+	runs, err := store.ListRuns(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	for _, r := range runs {
+		if r.Active() {
+			return errRunActive
+		}
+	}
+
+	return nil
+}
+
+func addUsage(a, b *provider.Usage) *provider.Usage {
+	if a == nil {
+		return b
+	}
+
+	if b == nil {
+		return a
+	}
+
+	return &provider.Usage{
+		InputTokens:  a.InputTokens + b.InputTokens,
+		OutputTokens: a.OutputTokens + b.OutputTokens,
+	}
+}
+
+func oaiRun(r thread.Run) Run {
+	result := Run{
+		Object: "thread.run",
+
+		ID:        r.ID,
+		CreatedAt: r.CreatedAt.Unix(),
+
+		ThreadID:    r.Thread,
+		AssistantID: r.Model,
+
+		Status: RunStatus(r.Status),
+
+		Model:        r.Model,
+		Instructions: r.Instructions,
+
+		Tools: oaiTools(r.Tools),
+
+		Temperature: r.Temperature,
+	}
+
+	if r.ToolChoice != nil {
+		result.ToolChoice = oaiToolChoice(*r.ToolChoice)
+	}
+
+	if len(r.ToolCalls) > 0 {
+		result.RequiredAction = &RunRequiredAction{
+			Type: "submit_tool_outputs",
+
+			SubmitToolOutputs: RunSubmitToolOutputs{
+				ToolCalls: oaiToolCalls(r.ToolCalls),
+			},
+		}
+	}
+
+	if r.StartedAt != nil {
+		val := r.StartedAt.Unix()
+		result.StartedAt = &val
+	}
+
+	if r.CompletedAt != nil {
+		val := r.CompletedAt.Unix()
+		result.CompletedAt = &val
+	}
+
+	if r.Error != "" {
+		result.LastError = &RunError{
+			Code:    "server_error",
+			Message: r.Error,
+		}
+	}
+
+	if r.Usage != nil {
+		result.Usage = &Usage{
+			PromptTokens:     r.Usage.InputTokens,
+			CompletionTokens: r.Usage.OutputTokens,
+			TotalTokens:      r.Usage.InputTokens + r.Usage.OutputTokens,
+		}
+	}
+
+	return result
+}
+
+func oaiTools(tools []provider.Tool) []Tool {
+	result := make([]Tool, 0)
+
+	for _, t := range tools {
+		result = append(result, Tool{
+			Type: ToolTypeFunction,
+
+			ToolFunction: &Function{
+				Name:        t.Name,
+				Description: t.Description,
+
+				Parameters: t.Parameters,
+			},
+		})
+	}
+
+	return result
+}
+
+func oaiToolChoice(c provider.ToolChoice) *ToolChoice {
+	switch c.Mode {
+	case provider.ToolChoiceModeNone:
+		return &ToolChoice{Mode: ToolChoiceModeNone}
+
+	case provider.ToolChoiceModeRequired:
+		return &ToolChoice{Mode: ToolChoiceModeRequired}
+
+	case provider.ToolChoiceModeTool:
+		return &ToolChoice{
+			Type: ToolTypeFunction,
+
+			Function: &ToolChoiceFunction{
+				Name: c.Name,
+			},
+		}
+
+	default:
+		return &ToolChoice{Mode: ToolChoiceModeAuto}
+	}
+}
@@ -0,0 +1,407 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/provider"
+)
+
+// scriptedCompleter answers every request with the next function of its script
+type scriptedCompleter struct {
+	mu sync.Mutex
+
+	script []func(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error)
+}
+
+func (c *scriptedCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.mu.Lock()
+
+	if len(c.script) == 0 {
+		c.mu.Unlock()
+		return nil, errors.New("unexpected request")
+	}
+
+	next := c.script[0]
+	c.script = c.script[1:]
+
+	c.mu.Unlock()
+
+	return next(ctx, messages, options)
+}
+
+func answer(content string) *provider.Completion {
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: content,
+		},
+	}
+}
+
+func asPrincipal(h http.Handler, id string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := authorizer.WithPrincipal(r.Context(), &authorizer.Principal{ID: id})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func requestStatus(h http.Handler, method, path, body string) int {
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func createRun(t *testing.T, h http.Handler, thread, body string) Run {
+	var run Run
+	doRequest(t, h, http.MethodPost, "/threads/"+thread+"/runs", "application/json", []byte(body), &run)
+
+	return run
+}
+
+func waitRunStatus(t *testing.T, h http.Handler, thread, id string, status RunStatus) Run {
+	deadline := time.Now().Add(10 * time.Second)
+
+	for {
+		var run Run
+		doRequest(t, h, http.MethodGet, "/threads/"+thread+"/runs/"+id, "", nil, &run)
+
+		if run.Status == status {
+			return run
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for status %s, got %s (%+v)", status, run.Status, run.LastError)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func threadMessages(t *testing.T, h http.Handler, thread string) []ThreadMessage {
+	var list ThreadMessageList
+	doRequest(t, h, http.MethodGet, "/threads/"+thread+"/messages?order=asc", "", nil, &list)
+
+	return list.Messages
+}
+
+func TestThreadRunModelNotAllowed(t *testing.T) {
+	h := newTestHandler(t, &blockingCompleter{release: make(chan struct{})})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := authorizer.WithPrincipal(r.Context(), &authorizer.Principal{
+			ID:     "alice",
+			Models: []string{"fake"},
+		})
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+
+	var thread Thread
+	doRequest(t, handler, http.MethodPost, "/threads", "application/json", []byte(`{}`), &thread)
+
+	for _, body := range []string{`{"model":"other"}`, `{"assistant_id":"other"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/threads/"+thread.ID+"/runs", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected %d, got %d %s", body, http.StatusForbidden, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestThreadOwnership(t *testing.T) {
+	h := newTestHandler(t, &scriptedCompleter{})
+
+	alice := asPrincipal(h, "alice")
+	bob := asPrincipal(h, "bob")
+
+	var thread Thread
+	doRequest(t, alice, http.MethodPost, "/threads", "application/json", []byte(`{"messages":[{"role":"user","content":"hello"}]}`), &thread)
+
+	for _, r := range []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/threads/" + thread.ID, ""},
+		{http.MethodGet, "/threads/" + thread.ID + "/messages", ""},
+		{http.MethodPost, "/threads/" + thread.ID + "/messages", `{"role":"user","content":"hi"}`},
+		{http.MethodGet, "/threads/" + thread.ID + "/runs", ""},
+		{http.MethodPost, "/threads/" + thread.ID + "/runs", `{"model":"fake"}`},
+		{http.MethodDelete, "/threads/" + thread.ID, ""},
+	} {
+		if code := requestStatus(bob, r.method, r.path, r.body); code != http.StatusNotFound {
+			t.Errorf("%s %s: expected %d for another principal, got %d", r.method, r.path, http.StatusNotFound, code)
+		}
+	}
+
+	if messages := threadMessages(t, alice, thread.ID); len(messages) != 1 {
+		t.Errorf("expected the message of the owner, got %+v", messages)
+	}
+
+	doRequest(t, alice, http.MethodDelete, "/threads/"+thread.ID, "", nil, nil)
+
+	if code := requestStatus(alice, http.MethodGet, "/threads/"+thread.ID, ""); code != http.StatusNotFound {
+		t.Errorf("expected deleted thread to be gone, got %d", code)
+	}
+}
+
+func TestThreadRunStatus(t *testing.T) {
+	completer := &blockingCompleter{release: make(chan struct{})}
+	h := newTestHandler(t, completer)
+
+	var thread Thread
+	doRequest(t, h, http.MethodPost, "/threads", "application/json", []byte(`{"messages":[{"role":"user","content":"hello"}]}`), &thread)
+
+	run := createRun(t, h, thread.ID, `{"model":"fake"}`)
+
+	if run.Status != RunStatusQueued {
+		t.Errorf("expected queued run, got %s", run.Status)
+	}
+
+	run = waitRunStatus(t, h, thread.ID, run.ID, RunStatusInProgress)
+
+	if run.StartedAt == nil || run.CompletedAt != nil {
+		t.Errorf("expected started run, got %+v", run)
+	}
+
+	// the thread is locked while the run is active
+	if code := requestStatus(h, http.MethodPost, "/threads/"+thread.ID+"/runs", `{"model":"fake"}`); code != http.StatusConflict {
+		t.Errorf("expected %d for a second run, got %d", http.StatusConflict, code)
+	}
+
+	if code := requestStatus(h, http.MethodPost, "/threads/"+thread.ID+"/messages", `{"role":"user","content":"hi"}`); code != http.StatusConflict {
+		t.Errorf("expected %d for a message during a run, got %d", http.StatusConflict, code)
+	}
+
+	close(completer.release)
+
+	run = waitRunStatus(t, h, thread.ID, run.ID, RunStatusCompleted)
+
+	if run.CompletedAt == nil {
+		t.Errorf("expected completed_at, got %+v", run)
+	}
+
+	messages := threadMessages(t, h, thread.ID)
+
+	if len(messages) != 2 || messages[1].Role != MessageRoleAssistant || messages[1].Content[0].Text.Value != "ok" || messages[1].RunID == nil || *messages[1].RunID != run.ID {
+		t.Errorf("expected the answer of the run, got %+v", messages)
+	}
+
+	// the thread is unlocked once the run completed
+	doRequest(t, h, http.MethodPost, "/threads/"+thread.ID+"/messages", "application/json", []byte(`{"role":"user","content":"thanks"}`), nil)
+}
+
+func TestThreadRunFailed(t *testing.T) {
+	h := newTestHandler(t, &scriptedCompleter{
+		script: []func(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error){
+			func(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+				return nil, errors.New("model overloaded")
+			},
+		},
+	})
+
+	var thread Thread
+	doRequest(t, h, http.MethodPost, "/threads", "application/json", []byte(`{}`), &thread)
+
+	run := createRun(t, h, thread.ID, `{"model":"fake"}`)
+	run = waitRunStatus(t, h, thread.ID, run.ID, RunStatusFailed)
+
+	if run.LastError == nil || run.LastError.Message != "model overloaded" || run.CompletedAt == nil {
+		t.Errorf("expected failed run with error, got %+v", run)
+	}
+}
+
+func TestThreadRunCancel(t *testing.T) {
+	completer := &blockingCompleter{release: make(chan struct{})}
+	h := newTestHandler(t, completer)
+
+	var thread Thread
+	doRequest(t, h, http.MethodPost, "/threads", "application/json", []byte(`{}`), &thread)
+
+	run := createRun(t, h, thread.ID, `{"model":"fake"}`)
+	waitRunStatus(t, h, thread.ID, run.ID, RunStatusInProgress)
+
+	doRequest(t, h, http.MethodPost, "/threads/"+thread.ID+"/runs/"+run.ID+"/cancel", "", nil, &run)
+
+	if run.Status != RunStatusCancelling {
+		t.Errorf("expected cancelling run, got %s", run.Status)
+	}
+
+	close(completer.release)
+
+	waitRunStatus(t, h, thread.ID, run.ID, RunStatusCancelled)
+
+	if messages := threadMessages(t, h, thread.ID); len(messages) != 0 {
+		t.Errorf("expected no answer of a cancelled run, got %+v", messages)
+	}
+}
+
+func TestThreadRunToolCalls(t *testing.T) {
+	var requests [][]provider.Message
+	var options []*provider.CompleteOptions
+
+	record := func(messages []provider.Message, o *provider.CompleteOptions) {
+		requests = append(requests, messages)
+		options = append(options, o)
+	}
+
+	h := newTestHandler(t, &scriptedCompleter{
+		script: []func(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error){
+			func(ctx context.Context, messages []provider.Message, o *provider.CompleteOptions) (*provider.Completion, error) {
+				record(messages, o)
+
+				return &provider.Completion{
+					Reason: provider.CompletionReasonTool,
+
+					Message: provider.Message{
+						Role: provider.MessageRoleAssistant,
+
+						ToolCalls: []provider.ToolCall{
+							{ID: "call_1", Name: "weather", Arguments: `{"city":"Zurich"}`},
+						},
+					},
+				}, nil
+			},
+
+			func(ctx context.Context, messages []provider.Message, o *provider.CompleteOptions) (*provider.Completion, error) {
+				record(messages, o)
+				return answer("sunny in Zurich"), nil
+			},
+		},
+	})
+
+	var thread Thread
+	doRequest(t, h, http.MethodPost, "/threads", "application/json", []byte(`{"messages":[{"role":"user","content":"weather in zurich?"}]}`), &thread)
+
+	run := createRun(t, h, thread.ID, `{
+		"model": "fake",
+		"instructions": "be brief",
+		"tools": [{"type": "function", "function": {"name": "weather", "parameters": {"type": "object"}}}],
+		"tool_choice": "required"
+	}`)
+
+	run = waitRunStatus(t, h, thread.ID, run.ID, RunStatusRequiresAction)
+
+	if run.RequiredAction == nil || len(run.RequiredAction.SubmitToolOutputs.ToolCalls) != 1 || run.RequiredAction.SubmitToolOutputs.ToolCalls[0].ID != "call_1" {
+		t.Fatalf("expected required tool call, got %+v", run.RequiredAction)
+	}
+
+	if len(run.Tools) != 1 || run.Tools[0].ToolFunction.Name != "weather" {
+		t.Errorf("expected run tools, got %+v", run.Tools)
+	}
+
+	// tool calls are not part of the conversation
+	if messages := threadMessages(t, h, thread.ID); len(messages) != 1 {
+		t.Errorf("expected only the user message, got %+v", messages)
+	}
+
+	if code := requestStatus(h, http.MethodPost, "/threads/"+thread.ID+"/runs/"+run.ID+"/submit_tool_outputs", `{"tool_outputs":[{"tool_call_id":"call_2","output":"sunny"}]}`); code != http.StatusBadRequest {
+		t.Errorf("expected %d for an unknown tool call, got %d", http.StatusBadRequest, code)
+	}
+
+	doRequest(t, h, http.MethodPost, "/threads/"+thread.ID+"/runs/"+run.ID+"/submit_tool_outputs", "application/json", []byte(`{"tool_outputs":[{"tool_call_id":"call_1","output":"sunny, 25°C"}]}`), &run)
+
+	run = waitRunStatus(t, h, thread.ID, run.ID, RunStatusCompleted)
+
+	if run.RequiredAction != nil {
+		t.Errorf("expected no required action, got %+v", run.RequiredAction)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	first := options[0]
+
+	if len(first.Tools) != 1 || first.Tools[0].Name != "weather" || first.ToolChoice == nil || first.ToolChoice.Mode != provider.ToolChoiceModeRequired {
+		t.Errorf("expected run tools and tool choice, got %+v", first)
+	}
+
+	if requests[0][0].Role != provider.MessageRoleSystem || requests[0][0].Content != "be brief" {
+		t.Errorf("expected run instructions, got %+v", requests[0][0])
+	}
+
+	// system, user, assistant tool call, tool output
+	second := requests[1]
+
+	if len(second) != 4 || len(second[2].ToolCalls) != 1 || second[3].Role != provider.MessageRoleTool || second[3].Tool != "call_1" || second[3].Content != "sunny, 25°C" {
+		t.Errorf("expected tool call and output in history, got %+v", second)
+	}
+
+	messages := threadMessages(t, h, thread.ID)
+
+	if len(messages) != 2 || messages[1].Content[0].Text.Value != "sunny in Zurich" {
+		t.Errorf("expected the answer of the run, got %+v", messages)
+	}
+}
+
+func TestThreadRunAttachments(t *testing.T) {
+	var files []string
+
+	h := newTestHandler(t, &scriptedCompleter{
+		script: []func(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error){
+			func(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+				for _, f := range messages[0].Files {
+					data, _ := io.ReadAll(f.Content)
+					files = append(files, f.Name+":"+string(data))
+				}
+
+				return answer("read"), nil
+			},
+		},
+	})
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("purpose", "assistants")
+
+	fw, _ := mw.CreateFormFile("file", "notes.txt")
+	fw.Write([]byte("remember the milk"))
+
+	mw.Close()
+
+	var file File
+	doRequest(t, h, http.MethodPost, "/files", mw.FormDataContentType(), body.Bytes(), &file)
+
+	var thread Thread
+	doRequest(t, h, http.MethodPost, "/threads", "application/json", []byte(`{}`), &thread)
+
+	if code := requestStatus(h, http.MethodPost, "/threads/"+thread.ID+"/messages", `{"role":"user","content":"read","attachments":[{"file_id":"file-missing"}]}`); code != http.StatusBadRequest {
+		t.Errorf("expected %d for a missing file, got %d", http.StatusBadRequest, code)
+	}
+
+	var message ThreadMessage
+	doRequest(t, h, http.MethodPost, "/threads/"+thread.ID+"/messages", "application/json", []byte(`{"role":"user","content":"read","attachments":[{"file_id":"`+file.ID+`"}]}`), &message)
+
+	if len(message.Attachments) != 1 || message.Attachments[0].FileID != file.ID {
+		t.Errorf("expected attachment, got %+v", message.Attachments)
+	}
+
+	run := createRun(t, h, thread.ID, `{"model":"fake"}`)
+	waitRunStatus(t, h, thread.ID, run.ID, RunStatusCompleted)
+
+	if len(files) != 1 || files[0] != "notes.txt:remember the milk" {
+		t.Errorf("expected attached file in request, got %v", files)
+	}
+}
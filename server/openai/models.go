@@ -26,17 +26,25 @@ type EmbeddingsRequest struct {
 	Input any    `json:"input"`
 	Model string `json:"model"`
 
-	// encoding_format string: float, base64
-	// dimensions int
+	Dimensions     *int           `json:"dimensions,omitempty"`
+	EncodingFormat EncodingFormat `json:"encoding_format,omitempty"`
+
 	// user string
 }
 
+type EncodingFormat string
+
+var (
+	EncodingFormatFloat  EncodingFormat = "float"
+	EncodingFormatBase64 EncodingFormat = "base64"
+)
+
 // https://platform.openai.com/docs/api-reference/embeddings/object
 type Embedding struct {
 	Object string `json:"object"` // "embedding"
 
-	Index     int       `json:"index"`
-	Embedding []float32 `json:"embedding"`
+	Index     int `json:"index"`
+	Embedding any `json:"embedding"` // []float32 | base64 encoded little-endian float32 string
 }
 
 // https://platform.openai.com/docs/api-reference/embeddings/create
@@ -61,8 +69,9 @@ var (
 type ResponseFormat string
 
 var (
-	ResponseFormatText ResponseFormat = "text"
-	ResponseFormatJSON ResponseFormat = "json_object"
+	ResponseFormatText       ResponseFormat = "text"
+	ResponseFormatJSON       ResponseFormat = "json_object"
+	ResponseFormatJSONSchema ResponseFormat = "json_schema"
 )
 
 // // https://platform.openai.com/docs/api-reference/chat/object
@@ -86,32 +95,168 @@ type ChatCompletionRequest struct {
 	Stop   any    `json:"stop,omitempty"`
 	Tools  []Tool `json:"tools,omitempty"`
 
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+
 	MaxTokens   *int     `json:"max_tokens,omitempty"`
 	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+
+	Seed *int `json:"seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+
+	N *int `json:"n,omitempty"`
+
+	Logprobs    bool `json:"logprobs,omitempty"`
+	TopLogprobs *int `json:"top_logprobs,omitempty"`
 
 	ResponseFormat *ChatCompletionResponseFormat `json:"response_format,omitempty"`
 
-	// frequency_penalty *float32
-	// presence_penalty *float32
+	// token biases are tokenizer specific and not passed to providers, so requests setting any are rejected
+	LogitBias map[string]float32 `json:"logit_bias,omitempty"`
+
+	// user string
+}
+
+// https://platform.openai.com/docs/api-reference/chat/create#chat-create-stream_options
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/chat/create#chat-create-tool_choice
+type ToolChoice struct {
+	Mode ToolChoiceMode `json:"-"`
+
+	Type     ToolType            `json:"type,omitempty"`
+	Function *ToolChoiceFunction `json:"function,omitempty"`
+}
 
-	// logit_bias
-	// logprobs *bool
-	// top_logprobs *int
+type ToolChoiceMode string
 
-	// n *int
+var (
+	ToolChoiceModeNone     ToolChoiceMode = "none"
+	ToolChoiceModeAuto     ToolChoiceMode = "auto"
+	ToolChoiceModeRequired ToolChoiceMode = "required"
+)
 
-	// seed *int
+type ToolChoiceFunction struct {
+	Name string `json:"name"`
+}
 
-	// top_p *float32
+func (c *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
 
-	// tool_choice string: none, auto
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*c = ToolChoice{
+			Mode: ToolChoiceMode(mode),
+		}
 
-	// user string
+		return nil
+	}
+
+	type choice ToolChoice
+
+	var val choice
+
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+
+	*c = ToolChoice(val)
+	return nil
+}
+
+func (c ToolChoice) MarshalJSON() ([]byte, error) {
+	if c.Mode != "" {
+		return json.Marshal(c.Mode)
+	}
+
+	type choice ToolChoice
+	return json.Marshal(choice(c))
 }
 
 // https://platform.openai.com/docs/api-reference/chat/create
 type ChatCompletionResponseFormat struct {
 	Type ResponseFormat `json:"type"`
+
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// https://platform.openai.com/docs/guides/structured-outputs
+type JSONSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Strict *bool          `json:"strict,omitempty"`
+	Schema map[string]any `json:"schema,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/completions/create
+type CompletionRequest struct {
+	Model string `json:"model"`
+
+	Prompt any    `json:"prompt"`
+	Suffix string `json:"suffix,omitempty"`
+
+	Stream bool `json:"stream,omitempty"`
+	Stop   any  `json:"stop,omitempty"`
+	Echo   bool `json:"echo,omitempty"`
+
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+
+	Seed *int `json:"seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+
+	N *int `json:"n,omitempty"`
+
+	Logprobs *int `json:"logprobs,omitempty"`
+
+	LogitBias map[string]float32 `json:"logit_bias,omitempty"`
+
+	// best_of int
+
+	// user string
+}
+
+// https://platform.openai.com/docs/api-reference/completions/object
+type Completion struct {
+	Object string `json:"object"` // "text_completion"
+
+	ID string `json:"id"`
+
+	Model   string `json:"model"`
+	Created int64  `json:"created"`
+
+	Choices []CompletionChoice `json:"choices"`
+
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+type CompletionChoice struct {
+	Index int `json:"index"`
+
+	Text string `json:"text"`
+
+	FinishReason *FinishReason `json:"finish_reason"`
+
+	Logprobs *CompletionLogprobs `json:"logprobs"`
+}
+
+type CompletionLogprobs struct {
+	Tokens        []string  `json:"tokens"`
+	TokenLogprobs []float64 `json:"token_logprobs"`
+
+	TopLogprobs []map[string]float64 `json:"top_logprobs"`
+	TextOffset  []int                `json:"text_offset"`
 }
 
 // https://platform.openai.com/docs/api-reference/chat/object
@@ -136,6 +281,21 @@ type ChatCompletionChoice struct {
 	Message *ChatCompletionMessage `json:"message,omitempty"`
 
 	FinishReason *FinishReason `json:"finish_reason"`
+
+	Logprobs *Logprobs `json:"logprobs,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/chat/object#chat/object-choices
+type Logprobs struct {
+	Content []TokenLogprob `json:"content"`
+}
+
+type TokenLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes"`
+
+	TopLogprobs []TokenLogprob `json:"top_logprobs,omitempty"`
 }
 
 // https://platform.openai.com/docs/api-reference/chat/object
@@ -241,9 +401,9 @@ type Tool struct {
 
 // https://platform.openai.com/docs/api-reference/chat/object
 type ToolCall struct {
-	ID string `json:"id"`
+	ID string `json:"id,omitempty"`
 
-	Type ToolType `json:"type"`
+	Type ToolType `json:"type,omitempty"`
 
 	Index int `json:"index"`
 
@@ -259,7 +419,7 @@ type Function struct {
 
 // https://platform.openai.com/docs/api-reference/chat/object
 type FunctionCall struct {
-	Name      string `json:"name"`
+	Name      string `json:"name,omitempty"`
 	Arguments string `json:"arguments"`
 }
 
@@ -269,6 +429,35 @@ type SpeechRequest struct {
 
 	Input string `json:"input"`
 	Voice string `json:"voice"`
+
+	Speed          *float32     `json:"speed,omitempty"`
+	ResponseFormat SpeechFormat `json:"response_format,omitempty"`
+}
+
+type SpeechFormat string
+
+var (
+	SpeechFormatMP3  SpeechFormat = "mp3"
+	SpeechFormatOpus SpeechFormat = "opus"
+	SpeechFormatAAC  SpeechFormat = "aac"
+	SpeechFormatFLAC SpeechFormat = "flac"
+	SpeechFormatWAV  SpeechFormat = "wav"
+	SpeechFormatPCM  SpeechFormat = "pcm"
+)
+
+type VoiceList struct {
+	Object string `json:"object"` // "list"
+
+	Voices []Voice `json:"data"`
+}
+
+type Voice struct {
+	Object string `json:"object"` // "voice"
+
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	Model string `json:"model"`
 }
 
 type Transcription struct {
@@ -278,6 +467,35 @@ type Transcription struct {
 	Duration float64 `json:"duration"`
 
 	Text string `json:"text"`
+
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Words    []TranscriptionWord    `json:"words,omitempty"`
+}
+
+type TranscriptionFormat string
+
+var (
+	TranscriptionFormatJSON        TranscriptionFormat = "json"
+	TranscriptionFormatText        TranscriptionFormat = "text"
+	TranscriptionFormatSRT         TranscriptionFormat = "srt"
+	TranscriptionFormatVTT         TranscriptionFormat = "vtt"
+	TranscriptionFormatVerboseJSON TranscriptionFormat = "verbose_json"
+)
+
+type TranscriptionSegment struct {
+	ID int `json:"id"`
+
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+
+	Text string `json:"text"`
+}
+
+type TranscriptionWord struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+
+	Word string `json:"word"`
 }
 
 // https://platform.openai.com/docs/api-reference/images/create
@@ -285,13 +503,19 @@ type ImageCreateRequest struct {
 	Model string `json:"model"`
 
 	Prompt string `json:"prompt"`
-	Style  string `json:"style,omitempty"`
+
+	N       *int   `json:"n,omitempty"`
+	Size    string `json:"size,omitempty"`
+	Quality string `json:"quality,omitempty"`
+	Style   string `json:"style,omitempty"`
 
 	ResponseFormat string `json:"response_format,omitempty"`
 }
 
 // https://platform.openai.com/docs/api-reference/images/create
 type ImageList struct {
+	Created int64 `json:"created"`
+
 	Images []Image `json:"data"`
 }
 
@@ -315,5 +539,299 @@ type ErrorResponse struct {
 
 type Error struct {
 	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// https://platform.openai.com/docs/api-reference/threads/createThread
+type ThreadCreateRequest struct {
+	Messages []ThreadMessageCreateRequest `json:"messages,omitempty"`
+	Metadata map[string]string            `json:"metadata,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/threads/object
+type Thread struct {
+	Object string `json:"object"` // "thread"
+
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+
+	Metadata map[string]string `json:"metadata"`
+}
+
+// https://platform.openai.com/docs/api-reference/threads/deleteThread
+type ThreadDeleted struct {
+	Object string `json:"object"` // "thread.deleted"
+
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// https://platform.openai.com/docs/api-reference/messages/createMessage
+type ThreadMessageCreateRequest struct {
+	Role    MessageRole `json:"role"`
+	Content any         `json:"content"`
+
+	Attachments []ThreadMessageAttachment `json:"attachments,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type ThreadMessageAttachment struct {
+	FileID string `json:"file_id"`
+	Tools  []any  `json:"tools,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/messages/object
+type ThreadMessage struct {
+	Object string `json:"object"` // "thread.message"
+
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+
+	ThreadID    string  `json:"thread_id"`
+	AssistantID *string `json:"assistant_id"`
+	RunID       *string `json:"run_id"`
+
+	Role    MessageRole            `json:"role"`
+	Content []ThreadMessageContent `json:"content"`
+
+	Attachments []ThreadMessageAttachment `json:"attachments"`
+
+	Metadata map[string]string `json:"metadata"`
+}
+
+type ThreadMessageContent struct {
+	Type string             `json:"type"` // "text"
+	Text *ThreadMessageText `json:"text,omitempty"`
+}
+
+type ThreadMessageText struct {
+	Value       string `json:"value"`
+	Annotations []any  `json:"annotations"`
+}
+
+// https://platform.openai.com/docs/api-reference/messages/listMessages
+type ThreadMessageList struct {
+	Object string `json:"object"` // "list"
+
+	Messages []ThreadMessage `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// https://platform.openai.com/docs/api-reference/runs/createRun
+type RunCreateRequest struct {
+	AssistantID string `json:"assistant_id"`
+	Model       string `json:"model,omitempty"`
+
+	Instructions           string `json:"instructions,omitempty"`
+	AdditionalInstructions string `json:"additional_instructions,omitempty"`
+
+	AdditionalMessages []ThreadMessageCreateRequest `json:"additional_messages,omitempty"`
+
+	Tools      []Tool      `json:"tools,omitempty"`
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+
+	Temperature *float32 `json:"temperature,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/runs/submitToolOutputs
+type RunSubmitToolOutputsRequest struct {
+	ToolOutputs []RunToolOutput `json:"tool_outputs"`
+}
+
+type RunToolOutput struct {
+	ToolCallID string `json:"tool_call_id"`
+	Output     string `json:"output"`
+}
+
+type RunStatus string
+
+var (
+	RunStatusQueued         RunStatus = "queued"
+	RunStatusInProgress     RunStatus = "in_progress"
+	RunStatusRequiresAction RunStatus = "requires_action"
+	RunStatusCancelling     RunStatus = "cancelling"
+	RunStatusCancelled      RunStatus = "cancelled"
+	RunStatusFailed         RunStatus = "failed"
+	RunStatusCompleted      RunStatus = "completed"
+)
+
+// https://platform.openai.com/docs/api-reference/runs/object
+type Run struct {
+	Object string `json:"object"` // "thread.run"
+
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+
+	ThreadID    string `json:"thread_id"`
+	AssistantID string `json:"assistant_id"`
+
+	Status RunStatus `json:"status"`
+
+	Model        string `json:"model"`
+	Instructions string `json:"instructions"`
+
+	Tools      []Tool      `json:"tools"`
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+
+	Temperature *float32 `json:"temperature,omitempty"`
+
+	RequiredAction *RunRequiredAction `json:"required_action"`
+
+	StartedAt   *int64 `json:"started_at"`
+	CompletedAt *int64 `json:"completed_at"`
+
+	LastError *RunError `json:"last_error"`
+
+	Usage *Usage `json:"usage"`
+}
+
+type RunRequiredAction struct {
+	Type string `json:"type"` // "submit_tool_outputs"
+
+	SubmitToolOutputs RunSubmitToolOutputs `json:"submit_tool_outputs"`
+}
+
+type RunSubmitToolOutputs struct {
+	ToolCalls []ToolCall `json:"tool_calls"`
+}
+
+type RunError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// https://platform.openai.com/docs/api-reference/runs/listRuns
+type RunList struct {
+	Object string `json:"object"` // "list"
+
+	Runs []Run `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// https://platform.openai.com/docs/api-reference/files/object
+type File struct {
+	Object string `json:"object"` // "file"
+
+	ID        string `json:"id"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+
+	Filename string `json:"filename"`
+	Purpose  string `json:"purpose"`
+}
+
+// https://platform.openai.com/docs/api-reference/files/list
+type FileList struct {
+	Object string `json:"object"` // "list"
+
+	Files []File `json:"data"`
+}
+
+// https://platform.openai.com/docs/api-reference/files/delete
+type FileDeleted struct {
+	Object string `json:"object"` // "file"
+
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/create
+type BatchCreateRequest struct {
+	InputFileID string `json:"input_file_id"`
+
+	Endpoint         string `json:"endpoint"`
+	CompletionWindow string `json:"completion_window"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/object
+type Batch struct {
+	Object string `json:"object"` // "batch"
+
+	ID string `json:"id"`
+
+	Endpoint         string `json:"endpoint"`
+	CompletionWindow string `json:"completion_window"`
+
+	Errors *BatchErrors `json:"errors"`
+
+	InputFileID  string  `json:"input_file_id"`
+	OutputFileID *string `json:"output_file_id"`
+	ErrorFileID  *string `json:"error_file_id"`
+
+	Status string `json:"status"`
+
+	CreatedAt    int64  `json:"created_at"`
+	InProgressAt *int64 `json:"in_progress_at"`
+	FinalizingAt *int64 `json:"finalizing_at"`
+	CompletedAt  *int64 `json:"completed_at"`
+	FailedAt     *int64 `json:"failed_at"`
+	CancelledAt  *int64 `json:"cancelled_at"`
+
+	RequestCounts BatchRequestCounts `json:"request_counts"`
+
+	Metadata map[string]string `json:"metadata"`
+}
+
+type BatchErrors struct {
+	Object string `json:"object"` // "list"
+
+	Data []BatchError `json:"data"`
+}
+
+type BatchError struct {
+	Code    string `json:"code"`
 	Message string `json:"message"`
+
+	Line *int `json:"line"`
+}
+
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/list
+type BatchList struct {
+	Object string `json:"object"` // "list"
+
+	Batches []Batch `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/request-input
+type BatchRequestInput struct {
+	CustomID string `json:"custom_id"`
+
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/request-output
+type BatchRequestOutput struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+
+	Response *BatchResponse `json:"response"`
+	Error    *Error         `json:"error"`
+}
+
+type BatchResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestID  string          `json:"request_id"`
+	Body       json.RawMessage `json:"body"`
 }
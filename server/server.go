@@ -4,8 +4,12 @@ import (
 	"net/http"
 
 	"github.com/adrianliechti/llama/config"
+	"github.com/adrianliechti/llama/pkg/authorizer"
+	"github.com/adrianliechti/llama/pkg/otel"
+	"github.com/adrianliechti/llama/server/anthropic"
 	"github.com/adrianliechti/llama/server/api"
 	"github.com/adrianliechti/llama/server/index"
+	"github.com/adrianliechti/llama/server/ollama"
 	"github.com/adrianliechti/llama/server/openai"
 	"github.com/adrianliechti/llama/server/unstructured"
 
@@ -24,6 +28,9 @@ type Server struct {
 	index  *index.Handler
 	openai *openai.Handler
 
+	ollama    *ollama.Handler
+	anthropic *anthropic.Handler
+
 	unstructured *unstructured.Handler
 }
 
@@ -40,6 +47,18 @@ func New(cfg *config.Config) (*Server, error) {
 		return nil, err
 	}
 
+	ollama, err := ollama.New(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	anthropic, err := anthropic.New(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
 	index, err := index.New(cfg)
 
 	if err != nil {
@@ -62,6 +81,9 @@ func New(cfg *config.Config) (*Server, error) {
 		index:  index,
 		openai: openai,
 
+		ollama:    ollama,
+		anthropic: anthropic,
+
 		unstructured: unstructured,
 	}
 
@@ -96,6 +118,7 @@ func New(cfg *config.Config) (*Server, error) {
 	mux.Route("/v1", func(r chi.Router) {
 		s.api.Attach(r)
 		s.openai.Attach(r)
+		s.anthropic.Attach(r)
 
 		s.unstructured.Attach(r)
 	})
@@ -104,6 +127,10 @@ func New(cfg *config.Config) (*Server, error) {
 		s.index.Attach(r)
 	})
 
+	mux.Route("/api", func(r chi.Router) {
+		s.ollama.Attach(r)
+	})
+
 	return s, nil
 }
 
@@ -118,7 +145,10 @@ func (s *Server) handleAuth(next http.Handler) http.Handler {
 		var authorized = len(s.Authorizers) == 0
 
 		for _, a := range s.Authorizers {
-			if err := a.Verify(ctx, r); err == nil {
+			if principal, err := a.Verify(ctx, r); err == nil {
+				ctx = authorizer.WithPrincipal(ctx, principal)
+				otel.SetEndUser(ctx, principal)
+
 				authorized = true
 				break
 			}